@@ -0,0 +1,86 @@
+package application
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"reflect"
+	"sort"
+)
+
+var bookingFields = []string{"host_id", "name", "review_reservation_request_automatically"}
+
+var searchFields = []string{"host_id", "name", "location", "photos", "guest_number", "default_price"}
+
+func changedFields(before, after *domain.Accommodation) map[string]interface{} {
+	changes := map[string]interface{}{}
+	previous := editableFields(before)
+	for field, value := range editableFields(after) {
+		if !reflect.DeepEqual(previous[field], value) {
+			changes[field] = value
+		}
+	}
+	return changes
+}
+
+func editableFields(accommodation *domain.Accommodation) map[string]interface{} {
+	return map[string]interface{}{
+		"host_id":                accommodation.HostId,
+		"name":                   accommodation.Name,
+		"description":            accommodation.Description,
+		"default_locale":         accommodation.DefaultLocale,
+		"translations":           accommodation.Translations,
+		"location":               accommodation.Location,
+		"address":                accommodation.Address,
+		"geo_location":           accommodation.GeoLocation,
+		"geocoding_needs_review": accommodation.GeocodingNeedsReview,
+		"benefits":               accommodation.Benefits,
+		"unmapped_benefits":      accommodation.UnmappedBenefits,
+		"photos":                 accommodation.Photos,
+		"guest_number":           accommodation.GuestNumber,
+		"default_price":          accommodation.DefaultPrice,
+		"house_rules":            accommodation.HouseRules,
+		"cancellation_policy":    accommodation.CancellationPolicy,
+		"review_reservation_request_automatically": accommodation.ReviewReservationRequestAutomatically,
+	}
+}
+
+func auditChanges(before, after *domain.Accommodation) []domain.FieldChange {
+	changes := []domain.FieldChange{}
+	previous := map[string]interface{}{}
+	if before != nil {
+		previous = auditedFields(before)
+	}
+	for field, value := range auditedFields(after) {
+		if !sameAuditValue(previous[field], value) {
+			changes = append(changes, domain.FieldChange{Field: field, Before: previous[field], After: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+func auditedFields(accommodation *domain.Accommodation) map[string]interface{} {
+	fields := editableFields(accommodation)
+	fields["special_price"] = accommodation.SpecialPrice
+	fields["room_types"] = accommodation.RoomTypes
+	fields["status"] = accommodation.Status.String()
+	return fields
+}
+
+func sameAuditValue(before, after interface{}) bool {
+	if reflect.DeepEqual(before, after) {
+		return true
+	}
+	beforeValue, afterValue := reflect.ValueOf(before), reflect.ValueOf(after)
+	return beforeValue.Kind() == reflect.Slice && afterValue.Kind() == reflect.Slice && beforeValue.Len() == 0 && afterValue.Len() == 0
+}
+
+func anyFieldChanged(changes map[string]interface{}, fields []string) bool {
+	for _, field := range fields {
+		if _, ok := changes[field]; ok {
+			return true
+		}
+	}
+	return false
+}
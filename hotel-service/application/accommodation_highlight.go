@@ -0,0 +1,86 @@
+package application
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	highlightOpen  = "<em>"
+	highlightClose = "</em>"
+	minStemLength  = 3
+)
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+func HighlightMatches(accommodation *domain.Accommodation, text string) map[string][]string {
+	terms := searchTerms(text)
+	highlights := map[string][]string{}
+	if highlighted, ok := highlight(accommodation.Name, terms); ok {
+		highlights["name"] = []string{highlighted}
+	}
+	if highlighted, ok := highlight(accommodation.Description, terms); ok {
+		highlights["description"] = []string{highlighted}
+	}
+	if highlighted, ok := highlight(accommodation.Location, terms); ok {
+		highlights["location"] = []string{highlighted}
+	}
+	for _, benefit := range accommodation.Benefits {
+		if highlighted, ok := highlight(benefit, terms); ok {
+			highlights["benefits"] = append(highlights["benefits"], highlighted)
+		}
+	}
+	return highlights
+}
+
+func searchTerms(text string) []string {
+	var terms []string
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+	for _, field := range fields {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		for _, word := range wordPattern.FindAllString(field, -1) {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+func highlight(value string, terms []string) (string, bool) {
+	var builder strings.Builder
+	matched := false
+	last := 0
+	for _, bounds := range wordPattern.FindAllStringIndex(value, -1) {
+		word := value[bounds[0]:bounds[1]]
+		if !matchesAnyTerm(strings.ToLower(word), terms) {
+			continue
+		}
+		builder.WriteString(value[last:bounds[0]])
+		builder.WriteString(highlightOpen + word + highlightClose)
+		last = bounds[1]
+		matched = true
+	}
+	builder.WriteString(value[last:])
+	return builder.String(), matched
+}
+
+func matchesAnyTerm(word string, terms []string) bool {
+	for _, term := range terms {
+		if word == term {
+			return true
+		}
+		shorter, longer := term, word
+		if len(shorter) > len(longer) {
+			shorter, longer = longer, shorter
+		}
+		if len(shorter) >= minStemLength && strings.HasPrefix(longer, shorter) {
+			return true
+		}
+	}
+	return false
+}
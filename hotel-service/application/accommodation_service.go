@@ -1,8 +1,10 @@
 package application
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	booking "github.com/ZMS-DevOps/booking-service/proto"
 	"github.com/ZMS-DevOps/hotel-service/application/external"
 	"github.com/ZMS-DevOps/hotel-service/domain"
@@ -14,20 +16,29 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
 )
 
 type AccommodationService struct {
 	store         domain.AccommodationStore
+	auditStore    domain.AccommodationAuditStore
 	bookingClient booking.BookingServiceClient
 	searchClient  search.SearchServiceClient
+	geocoder      domain.Geocoder
 	loki          promtail.Client
 }
 
-func NewAccommodationService(store domain.AccommodationStore, bookingClient booking.BookingServiceClient, searchClient search.SearchServiceClient, loki promtail.Client) *AccommodationService {
+func NewAccommodationService(store domain.AccommodationStore, auditStore domain.AccommodationAuditStore, bookingClient booking.BookingServiceClient, searchClient search.SearchServiceClient, geocoder domain.Geocoder, loki promtail.Client) *AccommodationService {
 	return &AccommodationService{
 		store:         store,
+		auditStore:    auditStore,
 		bookingClient: bookingClient,
 		searchClient:  searchClient,
+		geocoder:      geocoder,
 		loki:          loki,
 	}
 }
@@ -39,7 +50,17 @@ func (service *AccommodationService) Get(id primitive.ObjectID, span trace.Span,
 
 func (service *AccommodationService) GetAll(span trace.Span, loki promtail.Client) ([]*domain.Accommodation, error) {
 	util.HttpTraceInfo("Fetching all accommodations...", span, loki, "GetAll", "")
-	return service.store.GetAll()
+	accommodations, err := service.store.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	published := []*domain.Accommodation{}
+	for _, accommodation := range accommodations {
+		if accommodation.Status == domain.Published {
+			published = append(published, accommodation)
+		}
+	}
+	return published, nil
 }
 
 func (service *AccommodationService) GetByHostId(ownerId string, span trace.Span, loki promtail.Client) ([]*domain.Accommodation, error) {
@@ -47,111 +68,481 @@ func (service *AccommodationService) GetByHostId(ownerId string, span trace.Span
 	return service.store.GetByHostId(ownerId)
 }
 
-func (service *AccommodationService) Add(accommodation *domain.Accommodation, span trace.Span, loki promtail.Client) error {
+func (service *AccommodationService) Search(query *domain.AccommodationSearchQuery, span trace.Span, loki promtail.Client) (*dto.AccommodationSearchResponse, error) {
+	util.HttpTraceInfo("Searching accommodations...", span, loki, "Search", query.Text)
+	hits, total, err := service.store.Search(*query)
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range hits {
+		hit.Highlights = HighlightMatches(hit.Accommodation, query.Text)
+	}
+	return dto.MapAccommodationSearchResponse(query, hits, total), nil
+}
+
+func (service *AccommodationService) Nearby(query *domain.NearbyQuery, span trace.Span, loki promtail.Client) ([]*domain.NearbyHit, error) {
+	util.HttpTraceInfo("Fetching nearby accommodations...", span, loki, "Nearby", "")
+	return service.store.Nearby(*query)
+}
+
+func (service *AccommodationService) Add(ctx context.Context, accommodation *domain.Accommodation, actor domain.Actor, span trace.Span, loki promtail.Client) error {
+	if accommodation.Status == domain.Published {
+		if err := checkPublishable(accommodation); err != nil {
+			return err
+		}
+	} else {
+		accommodation.PendingBookingRegistration = true
+	}
+	service.resolveCoordinates(nil, accommodation, span, loki)
 	util.HttpTraceInfo("Inserting accommodation...", span, loki, "Add", "")
 	err := service.store.Insert(accommodation)
 	if err != nil {
 		return err
 	}
 	accommodation.SpecialPrice = []domain.SpecialPrice{}
-	_, err = external.CreateBookingUnavailability(service.bookingClient, accommodation.Id, accommodation.ReviewReservationRequestAutomatically, accommodation.HostId, accommodation.Name, span, loki)
+	service.recordAudit(accommodation.Id, actor, domain.AuditCreate, auditChanges(nil, accommodation), span, loki)
+	if accommodation.Status != domain.Published {
+		util.HttpTraceInfo("Accommodation saved as draft", span, loki, "Add", accommodation.Id.Hex())
+		return nil
+	}
+	return service.syncPublishedAccommodation(ctx, accommodation, true, span, loki)
+}
+
+func (service *AccommodationService) Publish(ctx context.Context, id primitive.ObjectID, requestedVersion int64, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	util.HttpTraceInfo("Publishing accommodation...", span, loki, "Publish", id.Hex())
+	accommodation, version, err := service.getVersioned(id, requestedVersion)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	switch accommodation.Status {
+	case domain.Published:
+		return accommodation, nil
+	case domain.Suspended:
+		return nil, domain.ErrListingSuspended
+	}
+	if err := checkPublishable(accommodation); err != nil {
+		return nil, err
+	}
+
+	publishedAccommodation, err := service.changeStatus(accommodation, version, domain.Published, domain.AuditPublish, actor, span, loki)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.syncPublishedAccommodation(ctx, publishedAccommodation, accommodation.PendingBookingRegistration, span, loki); err != nil {
+		return nil, err
+	}
+	return publishedAccommodation, nil
+}
+
+func (service *AccommodationService) Unpublish(ctx context.Context, id primitive.ObjectID, requestedVersion int64, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	util.HttpTraceInfo("Unpublishing accommodation...", span, loki, "Unpublish", id.Hex())
+	accommodation, version, err := service.getVersioned(id, requestedVersion)
+	if err != nil {
+		return nil, err
+	}
+	switch accommodation.Status {
+	case domain.Draft:
+		return accommodation, nil
+	case domain.Suspended:
+		return nil, domain.ErrListingSuspended
+	}
+	return service.withdrawAccommodation(ctx, accommodation, version, domain.Draft, domain.AuditUnpublish, actor, span, loki)
+}
+
+func (service *AccommodationService) Suspend(ctx context.Context, id primitive.ObjectID, requestedVersion int64, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	util.HttpTraceInfo("Suspending accommodation...", span, loki, "Suspend", id.Hex())
+	accommodation, version, err := service.getVersioned(id, requestedVersion)
+	if err != nil {
+		return nil, err
+	}
+	if accommodation.Status == domain.Suspended {
+		return accommodation, nil
+	}
+	return service.withdrawAccommodation(ctx, accommodation, version, domain.Suspended, domain.AuditSuspend, actor, span, loki)
+}
+
+func (service *AccommodationService) Reinstate(ctx context.Context, id primitive.ObjectID, requestedVersion int64, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	util.HttpTraceInfo("Reinstating accommodation...", span, loki, "Reinstate", id.Hex())
+	accommodation, version, err := service.getVersioned(id, requestedVersion)
+	if err != nil {
+		return nil, err
+	}
+	if accommodation.Status != domain.Suspended {
+		return accommodation, nil
+	}
+	return service.changeStatus(accommodation, version, domain.Draft, domain.AuditReinstate, actor, span, loki)
+}
+
+func (service *AccommodationService) getVersioned(id primitive.ObjectID, requestedVersion int64) (*domain.Accommodation, int64, error) {
+	accommodation, err := service.store.Get(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	version, err := expectedVersion(requestedVersion, accommodation.Version)
+	if err != nil {
+		return nil, 0, err
+	}
+	return accommodation, version, nil
+}
+
+func (service *AccommodationService) withdrawAccommodation(ctx context.Context, accommodation *domain.Accommodation, version int64, status domain.AccommodationStatus, action domain.AuditAction, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	wasPublished := accommodation.Status == domain.Published
+	updatedAccommodation, err := service.changeStatus(accommodation, version, status, action, actor, span, loki)
+	if err != nil {
+		return nil, err
+	}
+	if wasPublished {
+		if err := service.notifySearchServiceWhenDeletingAccommodation(ctx, accommodation.Id, span, loki); err != nil {
+			return nil, err
+		}
+	}
+	return updatedAccommodation, nil
+}
+
+func (service *AccommodationService) changeStatus(accommodation *domain.Accommodation, version int64, status domain.AccommodationStatus, action domain.AuditAction, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	pendingBookingRegistration := accommodation.PendingBookingRegistration && status != domain.Published
+	if err := service.store.UpdateStatus(accommodation.Id, status, pendingBookingRegistration, version); err != nil {
+		return nil, err
+	}
+
+	updatedAccommodation := *accommodation
+	updatedAccommodation.Status = status
+	updatedAccommodation.PendingBookingRegistration = pendingBookingRegistration
+	updatedAccommodation.Version = version + 1
+	service.recordAudit(accommodation.Id, actor, action, auditChanges(accommodation, &updatedAccommodation), span, loki)
+	return &updatedAccommodation, nil
+}
+
+func (service *AccommodationService) syncPublishedAccommodation(ctx context.Context, accommodation *domain.Accommodation, registerBooking bool, span trace.Span, loki promtail.Client) error {
+	var err error
+	if registerBooking {
+		_, err = external.CreateBookingUnavailability(ctx, service.bookingClient, accommodation.Id, accommodation.ReviewReservationRequestAutomatically, accommodation.HostId, accommodation.Name, span, loki)
+	} else {
+		_, err = external.UpdateBookingUnavailability(ctx, service.bookingClient, accommodation.Id, accommodation.ReviewReservationRequestAutomatically, accommodation.HostId, accommodation.Name, span, loki)
 	}
-	_, err = external.AddSearchAccommodation(service.searchClient, dto.MapToSearchAccommodation(accommodation), span, loki)
 	if err != nil {
 		return err
 	}
-	return nil
+	if err := service.syncRoomTypeBookings(ctx, accommodation, registerBooking, span, loki); err != nil {
+		return err
+	}
+	_, err = external.AddSearchAccommodation(ctx, service.searchClient, dto.MapToSearchAccommodation(accommodation), span, loki)
+	return err
 }
 
-func (service *AccommodationService) Update(id primitive.ObjectID, accommodation *domain.Accommodation, span trace.Span, loki promtail.Client) error {
+func (service *AccommodationService) Update(ctx context.Context, id primitive.ObjectID, accommodation *domain.Accommodation, actor domain.Actor, span trace.Span, loki promtail.Client) error {
 	util.HttpTraceInfo("Fetching accommodation by id...", span, loki, "Update", "")
-	_, err := service.store.Get(id)
+	currentAccommodation, err := service.store.Get(id)
 	if err != nil {
 		util.HttpTraceError(err, "can't decode login payload", span, service.loki, "Login", "")
 		return err
 	}
+	version, err := expectedVersion(accommodation.Version, currentAccommodation.Version)
+	if err != nil {
+		return err
+	}
+	accommodation.Version = version
+	service.resolveCoordinates(currentAccommodation, accommodation, span, loki)
+	carryUnmappedBenefits(currentAccommodation, accommodation)
 	util.HttpTraceInfo("Updating accommodation...", span, loki, "Add", "")
 	err = service.store.Update(id, accommodation)
 	if err != nil {
 		return err
 	}
-	_, err = external.UpdateBookingUnavailability(service.bookingClient, accommodation.Id, accommodation.ReviewReservationRequestAutomatically, accommodation.HostId, accommodation.Name, span, loki)
-	_, err = external.EditSearchAccommodation(service.searchClient, dto.MapToSearchAccommodation(accommodation), span, loki)
+	accommodation.Version++
+	accommodation.Status = currentAccommodation.Status
+	accommodation.RoomTypes = currentAccommodation.RoomTypes
+	service.recordAudit(id, actor, domain.AuditUpdate, auditChanges(currentAccommodation, withEditableFields(currentAccommodation, accommodation)), span, loki)
+	if accommodation.Status != domain.Published {
+		return nil
+	}
+	_, err = external.UpdateBookingUnavailability(ctx, service.bookingClient, accommodation.Id, accommodation.ReviewReservationRequestAutomatically, accommodation.HostId, accommodation.Name, span, loki)
+	if err != nil {
+		return err
+	}
+	if err := service.syncRoomTypeBookings(ctx, accommodation, false, span, loki); err != nil {
+		return err
+	}
+	_, err = external.EditSearchAccommodation(ctx, service.searchClient, dto.MapToSearchAccommodation(accommodation), span, loki)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (service *AccommodationService) Delete(id primitive.ObjectID, span trace.Span, loki promtail.Client) error {
+func (service *AccommodationService) Patch(ctx context.Context, id primitive.ObjectID, patched *domain.Accommodation, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	util.HttpTraceInfo("Fetching accommodation by id...", span, loki, "Patch", "")
+	currentAccommodation, err := service.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	version, err := expectedVersion(patched.Version, currentAccommodation.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedAccommodation := *currentAccommodation
+	updatedAccommodation.HostId = patched.HostId
+	updatedAccommodation.Name = patched.Name
+	updatedAccommodation.Description = patched.Description
+	updatedAccommodation.DefaultLocale = patched.DefaultLocale
+	updatedAccommodation.Translations = patched.Translations
+	updatedAccommodation.Location = patched.Location
+	updatedAccommodation.Address = patched.Address
+	updatedAccommodation.GeoLocation = patched.GeoLocation
+	updatedAccommodation.Benefits = patched.Benefits
+	updatedAccommodation.GuestNumber = patched.GuestNumber
+	updatedAccommodation.DefaultPrice = patched.DefaultPrice
+	updatedAccommodation.HouseRules = patched.HouseRules
+	updatedAccommodation.CancellationPolicy = patched.CancellationPolicy
+	updatedAccommodation.ReviewReservationRequestAutomatically = patched.ReviewReservationRequestAutomatically
+	service.resolveCoordinates(currentAccommodation, &updatedAccommodation, span, loki)
+	carryUnmappedBenefits(currentAccommodation, &updatedAccommodation)
+
+	changes := changedFields(currentAccommodation, &updatedAccommodation)
+	if len(changes) == 0 {
+		util.HttpTraceInfo("No accommodation fields changed", span, loki, "Patch", id.Hex())
+		return currentAccommodation, nil
+	}
+
+	util.HttpTraceInfo("Patching accommodation...", span, loki, "Patch", id.Hex())
+	updatedAccommodation.Version = version
+	if err := service.store.Update(id, &updatedAccommodation); err != nil {
+		return nil, err
+	}
+	updatedAccommodation.Version = version + 1
+	service.recordAudit(id, actor, domain.AuditPatch, auditChanges(currentAccommodation, &updatedAccommodation), span, loki)
+	if updatedAccommodation.Status != domain.Published {
+		return &updatedAccommodation, nil
+	}
+
+	if anyFieldChanged(changes, bookingFields) {
+		_, err = external.UpdateBookingUnavailability(ctx, service.bookingClient, updatedAccommodation.Id, updatedAccommodation.ReviewReservationRequestAutomatically, updatedAccommodation.HostId, updatedAccommodation.Name, span, loki)
+		if err != nil {
+			return nil, err
+		}
+		if err := service.syncRoomTypeBookings(ctx, &updatedAccommodation, false, span, loki); err != nil {
+			return nil, err
+		}
+	}
+	if anyFieldChanged(changes, searchFields) {
+		_, err = external.EditSearchAccommodation(ctx, service.searchClient, dto.MapToSearchAccommodation(&updatedAccommodation), span, loki)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &updatedAccommodation, nil
+}
+
+func (service *AccommodationService) Delete(ctx context.Context, id primitive.ObjectID, actor domain.Actor, span trace.Span, loki promtail.Client) error {
 	util.HttpTraceInfo("Deleting accommodation...", span, loki, "Delete", "")
-	canDelete, err := external.CheckAccommodationHasReservation(service.bookingClient, id, span, loki)
+	accommodation, err := service.store.Get(id)
+	if err != nil {
+		return err
+	}
+	reserved, err := service.hasReservations(ctx, accommodation, span, loki)
 	if err != nil {
 		return err
 	}
-	if canDelete.Success {
-		return service.deleteAccommodation(id, span, loki)
+	if reserved {
+		return domain.ErrHasReservations
 	}
-	return errors.New("accommodation could not be deleted")
+	return service.deleteAccommodation(ctx, id, actor, span, loki)
 }
 
-func (service *AccommodationService) deleteAccommodation(id primitive.ObjectID, span trace.Span, loki promtail.Client) error {
+func (service *AccommodationService) deleteAccommodation(ctx context.Context, id primitive.ObjectID, actor domain.Actor, span trace.Span, loki promtail.Client) error {
+	accommodation, err := service.store.Get(id)
+	if err != nil {
+		return err
+	}
 	util.HttpTraceInfo("Deleting accommodation from collection...", span, loki, "DeleteAccommodation", "")
-	if err := service.store.Delete(id); err != nil {
+	deletedAt := time.Now()
+	if err := service.store.SoftDelete(id, deletedAt); err != nil {
 		return err
 	}
-	if err := service.notifySearchServiceWhenDeletingAccommodation(id, span, loki); err != nil {
+	service.recordAudit(id, actor, domain.AuditDelete, deletionChanges(nil, &deletedAt), span, loki)
+	if accommodation.Status != domain.Published {
+		return nil
+	}
+	if err := service.notifySearchServiceWhenDeletingAccommodation(ctx, id, span, loki); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (service *AccommodationService) notifySearchServiceWhenDeletingAccommodation(id primitive.ObjectID, span trace.Span, loki promtail.Client) error {
-	_, err := external.DeleteSearchAccommodation(service.searchClient, id, span, loki)
+func (service *AccommodationService) Restore(ctx context.Context, id primitive.ObjectID, retention time.Duration, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	util.HttpTraceInfo("Fetching deleted accommodation by id...", span, loki, "Restore", "")
+	accommodation, err := service.store.GetDeleted(id)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(*accommodation.DeletedAt) > retention {
+		return nil, domain.ErrRetentionExpired
+	}
+
+	return service.restoreAccommodation(ctx, id, accommodation.DeletedAt, actor, span, loki)
+}
+
+func (service *AccommodationService) restoreAccommodation(ctx context.Context, id primitive.ObjectID, deletedAt *time.Time, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	util.HttpTraceInfo("Restoring accommodation...", span, loki, "Restore", id.Hex())
+	if err := service.store.Restore(id); err != nil {
+		return nil, err
+	}
+	restoredAccommodation, err := service.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	service.recordAudit(id, actor, domain.AuditRestore, deletionChanges(deletedAt, nil), span, loki)
+	if restoredAccommodation.Status != domain.Published {
+		return restoredAccommodation, nil
+	}
+	if err := service.syncPublishedAccommodation(ctx, restoredAccommodation, restoredAccommodation.PendingBookingRegistration, span, loki); err != nil {
+		return nil, err
+	}
+	return restoredAccommodation, nil
+}
+
+func (service *AccommodationService) PurgeDeleted(retention time.Duration, span trace.Span, loki promtail.Client) (int, error) {
+	util.HttpTraceInfo("Fetching expired deleted accommodations...", span, loki, "PurgeDeleted", "")
+	accommodations, err := service.store.GetDeletedBefore(time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, accommodation := range accommodations {
+		if err := service.store.Delete(accommodation.Id); err != nil {
+			util.HttpTraceError(err, "failed to purge accommodation", span, loki, "PurgeDeleted", accommodation.Id.Hex())
+			continue
+		}
+		removePhotos(accommodation.Photos)
+		for _, roomType := range accommodation.RoomTypes {
+			removePhotos(roomType.Photos)
+		}
+		service.recordAudit(accommodation.Id, domain.SystemActor("purger"), domain.AuditPurge, []domain.FieldChange{}, span, loki)
+		purged++
+	}
+	util.HttpTraceInfo(fmt.Sprintf("Purged %d deleted accommodations", purged), span, loki, "PurgeDeleted", "")
+	return purged, nil
+}
+
+func removePhotos(photos []string) {
+	uploadDirectory := filepath.Clean(domain.UploadDirectory) + string(filepath.Separator)
+	for _, photoPath := range photos {
+		if !strings.HasPrefix(filepath.Clean(photoPath), uploadDirectory) {
+			continue
+		}
+		if err := os.Remove(photoPath); err != nil && !os.IsNotExist(err) {
+			log.Println(err)
+		}
+	}
+}
+
+func (service *AccommodationService) notifySearchServiceWhenDeletingAccommodation(ctx context.Context, id primitive.ObjectID, span trace.Span, loki promtail.Client) error {
+	_, err := external.DeleteSearchAccommodation(ctx, service.searchClient, id, span, loki)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (service *AccommodationService) UpdatePrice(id primitive.ObjectID, updatePriceDto dto.UpdatePriceDto, span trace.Span, loki promtail.Client) error {
+func (service *AccommodationService) UpdatePrice(ctx context.Context, id primitive.ObjectID, updatePriceDto dto.UpdatePriceDto, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
 	util.HttpTraceInfo("Fetching accommodation...", span, loki, "UpdatePrice", "")
-	_, err := service.store.Get(id)
+	accommodation, err := service.store.Get(id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	version, err := expectedVersion(updatePriceDto.Version, accommodation.Version)
+	if err != nil {
+		return nil, err
 	}
 
+	defaultPrice, specialPrices := accommodation.DefaultPrice, accommodation.SpecialPrice
 	if updatePriceDto.DateRange == nil && updatePriceDto.Price != nil {
-		util.HttpTraceInfo("Updating accommodation default price...", span, loki, "Add", "")
-		if err := updateDefaultPrice(id, updatePriceDto, service); err != nil {
-			return err
-		}
+		defaultPrice.Price = *updatePriceDto.Price
 	} else if updatePriceDto.Price != nil {
-		util.HttpTraceInfo("Updating accommodation special price...", span, loki, "Add", "")
-		if err := updateSpecialPrice(id, updatePriceDto, service, span, loki); err != nil {
-			return err
-		}
+		specialPrices = AddSpecialPrice(accommodation.SpecialPrice, domain.SpecialPrice{
+			Price:     *updatePriceDto.Price,
+			DateRange: domain.DateRange{Start: updatePriceDto.DateRange.Start, End: updatePriceDto.DateRange.End},
+		})
 	}
 	if updatePriceDto.Type != nil {
-		util.HttpTraceInfo("Updating accommodation payment type...", span, loki, "Add", "")
-		if err := service.store.UpdateTypeOfPayment(id, updatePriceDto.Type); err != nil {
-			return err
+		pricingType := domain.ParsePricingType(updatePriceDto.Type)
+		if pricingType == nil {
+			return nil, fmt.Errorf("unknown payment type %q", *updatePriceDto.Type)
+		}
+		defaultPrice.Type = *pricingType
+	}
+	if updatePriceDto.Price != nil || updatePriceDto.Type != nil {
+		util.HttpTraceInfo("Updating accommodation price...", span, loki, "UpdatePrice", "")
+		if err := service.store.UpdatePrices(id, defaultPrice, specialPrices, version); err != nil {
+			return nil, err
 		}
 	}
 
 	util.HttpTraceInfo("Fetching accommodation...", span, loki, "Add", "")
 	updatedAccommodation, err := service.store.Get(id)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, err = external.EditSearchAccommodation(service.searchClient, dto.MapToSearchAccommodation(updatedAccommodation), span, loki)
+	service.recordAudit(id, actor, domain.AuditUpdatePrice, auditChanges(accommodation, updatedAccommodation), span, loki)
+	if updatedAccommodation.Status != domain.Published {
+		return updatedAccommodation, nil
+	}
+	_, err = external.EditSearchAccommodation(ctx, service.searchClient, dto.MapToSearchAccommodation(updatedAccommodation), span, loki)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return updatedAccommodation, nil
+}
+
+func (service *AccommodationService) UploadPriceCalendar(ctx context.Context, id primitive.ObjectID, nights []domain.SpecialPrice, dryRun bool, requestedVersion int64, actor domain.Actor, span trace.Span, loki promtail.Client) (*dto.PriceCalendarResponse, error) {
+	util.HttpTraceInfo("Fetching accommodation by id...", span, loki, "UploadPriceCalendar", "")
+	accommodation, err := service.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	version, err := expectedVersion(requestedVersion, accommodation.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	mergedSpecialPrices := MergePriceCalendar(accommodation.SpecialPrice, nights)
+	added, removed := DiffSpecialPrices(accommodation.SpecialPrice, mergedSpecialPrices)
+	if dryRun {
+		util.HttpTraceInfo("Price calendar dry run finished", span, loki, "UploadPriceCalendar", id.Hex())
+		return dto.MapPriceCalendarResponse(true, version, added, removed, mergedSpecialPrices), nil
+	}
+
+	util.HttpTraceInfo("Updating special prices...", span, loki, "UploadPriceCalendar", id.Hex())
+	if err := service.store.UpdateSpecialPrice(id, mergedSpecialPrices, version); err != nil {
+		return nil, err
+	}
+
+	updatedAccommodation, err := service.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	service.recordAudit(id, actor, domain.AuditUploadPriceCalendar, auditChanges(accommodation, updatedAccommodation), span, loki)
+	if updatedAccommodation.Status == domain.Published {
+		_, err = external.EditSearchAccommodation(ctx, service.searchClient, dto.MapToSearchAccommodation(updatedAccommodation), span, loki)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dto.MapPriceCalendarResponse(false, updatedAccommodation.Version, added, removed, updatedAccommodation.SpecialPrice), nil
+}
+
+func (service *AccommodationService) GetPriceCalendar(id primitive.ObjectID, request *dto.CalendarRequestDto, span trace.Span, loki promtail.Client) (*domain.Accommodation, *dto.CalendarResponse, error) {
+	util.HttpTraceInfo("Fetching accommodation by id...", span, loki, "GetPriceCalendar", "")
+	accommodation, err := service.store.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	nights := ResolveNightlyPrices(accommodation, request.From, request.To)
+	return accommodation, dto.MapCalendarResponse(accommodation, request, nights), nil
 }
 
 func (service *AccommodationService) GetImages(accommodationIds []dto.GetImagesRequest, span trace.Span, loki promtail.Client) ([]dto.ImageResponse, error) {
@@ -197,47 +588,110 @@ func encodeFileToBase64(filePath string) (string, error) {
 	return base64.StdEncoding.EncodeToString(fileBytes), nil
 }
 
-func (service *AccommodationService) OnDeleteAccommodations(hostId string, span trace.Span, loki promtail.Client) {
-	util.HttpTraceInfo("Fetching accommodation by host id...", span, loki, "OnDeleteAccommodations", "")
-	accommodations, err := service.store.GetByHostId(hostId)
-	if err != nil {
-		log.Println(err)
-		return
+func (service *AccommodationService) GetHistory(id primitive.ObjectID, page, size int, span trace.Span, loki promtail.Client) ([]*domain.AccommodationAudit, int64, error) {
+	util.HttpTraceInfo("Fetching accommodation history...", span, loki, "GetHistory", id.Hex())
+	return service.auditStore.GetByAccommodationId(id, page, size)
+}
+
+func (service *AccommodationService) GetIncludingDeleted(id primitive.ObjectID, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	util.HttpTraceInfo("Fetching accommodation by id...", span, loki, "GetIncludingDeleted", id.Hex())
+	accommodation, err := service.store.Get(id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return service.store.GetDeleted(id)
 	}
-	for _, accom := range accommodations {
-		if err := service.deleteAccommodation(accom.Id, span, loki); err != nil {
-			log.Println(err)
-			return
-		}
+	return accommodation, err
+}
+
+// recordAudit runs after the store write has already succeeded, so a failed audit insert is
+// logged rather than reported to the caller, who would otherwise retry a completed write.
+func (service *AccommodationService) recordAudit(id primitive.ObjectID, actor domain.Actor, action domain.AuditAction, changes []domain.FieldChange, span trace.Span, loki promtail.Client) {
+	audit := &domain.AccommodationAudit{
+		AccommodationId: id,
+		Actor:           actor,
+		Action:          action,
+		Timestamp:       time.Now(),
+		Changes:         changes,
 	}
+	if err := service.auditStore.Insert(audit); err != nil {
+		util.HttpTraceError(err, "failed to record accommodation audit", span, loki, "RecordAudit", id.Hex())
+	}
+}
+
+func deletionChanges(before, after *time.Time) []domain.FieldChange {
+	return []domain.FieldChange{{Field: "deleted_at", Before: before, After: after}}
 }
 
-func updateSpecialPrice(id primitive.ObjectID, updatePriceDto dto.UpdatePriceDto, service *AccommodationService, span trace.Span, loki promtail.Client) error {
-	util.HttpTraceInfo("Fetching special prices by accommodation id...", span, loki, "GetSpecialPrices", "")
-	currentSpecialPrices, err := service.store.GetSpecialPrices(id)
+func (service *AccommodationService) resolveCoordinates(current, accommodation *domain.Accommodation, span trace.Span, loki promtail.Client) {
+	accommodation.GeocodingNeedsReview = current != nil && current.GeocodingNeedsReview && !locationEdited(current, accommodation)
+	if accommodation.Address == nil || service.geocoder == nil || !needsGeocoding(current, accommodation) {
+		return
+	}
+	util.HttpTraceInfo("Geocoding accommodation address...", span, loki, "resolveCoordinates", accommodation.Address.Format())
+	point, err := service.geocoder.Geocode(accommodation.Address)
 	if err != nil {
-		return err
+		util.HttpTraceInfo(fmt.Sprintf("Geocoding failed, flagging accommodation for review: %v", err), span, loki, "resolveCoordinates", accommodation.Address.Format())
+		accommodation.GeocodingNeedsReview = true
+		return
+	}
+	accommodation.GeoLocation = point
+	accommodation.GeocodingNeedsReview = false
+}
+
+func needsGeocoding(current, accommodation *domain.Accommodation) bool {
+	if accommodation.GeoLocation == nil {
+		return true
+	}
+	if current == nil {
+		return false
 	}
+	return !reflect.DeepEqual(current.Address, accommodation.Address) && reflect.DeepEqual(current.GeoLocation, accommodation.GeoLocation)
+}
 
-	newSpecialPrice := domain.SpecialPrice{
-		Price: *updatePriceDto.Price,
-		DateRange: domain.DateRange{
-			Start: updatePriceDto.DateRange.Start,
-			End:   updatePriceDto.DateRange.End,
-		},
+func locationEdited(current, accommodation *domain.Accommodation) bool {
+	return !reflect.DeepEqual(current.Address, accommodation.Address) || !reflect.DeepEqual(current.GeoLocation, accommodation.GeoLocation)
+}
+
+func carryUnmappedBenefits(current, accommodation *domain.Accommodation) {
+	accommodation.UnmappedBenefits = nil
+	if reflect.DeepEqual(current.Benefits, accommodation.Benefits) {
+		accommodation.UnmappedBenefits = current.UnmappedBenefits
 	}
+}
 
-	newSpecialPrices := AddSpecialPrice(currentSpecialPrices, newSpecialPrice)
-	util.HttpTraceInfo("Updating special prices...", span, loki, "UpdateSpecialPrice", "")
-	if err := service.store.UpdateSpecialPrice(id, newSpecialPrices); err != nil {
+func withEditableFields(current, source *domain.Accommodation) *domain.Accommodation {
+	updated := *current
+	updated.HostId = source.HostId
+	updated.Name = source.Name
+	updated.Description = source.Description
+	updated.DefaultLocale = source.DefaultLocale
+	updated.Translations = source.Translations
+	updated.Location = source.Location
+	updated.Address = source.Address
+	updated.GeoLocation = source.GeoLocation
+	updated.GeocodingNeedsReview = source.GeocodingNeedsReview
+	updated.Benefits = source.Benefits
+	updated.UnmappedBenefits = source.UnmappedBenefits
+	updated.Photos = source.Photos
+	updated.GuestNumber = source.GuestNumber
+	updated.DefaultPrice = source.DefaultPrice
+	updated.HouseRules = source.HouseRules
+	updated.CancellationPolicy = source.CancellationPolicy
+	updated.ReviewReservationRequestAutomatically = source.ReviewReservationRequestAutomatically
+	return &updated
+}
+
+func (service *AccommodationService) CheckVersion(id primitive.ObjectID, requestedVersion int64) error {
+	accommodation, err := service.store.Get(id)
+	if err != nil {
 		return err
 	}
-	return nil
+	_, err = expectedVersion(requestedVersion, accommodation.Version)
+	return err
 }
 
-func updateDefaultPrice(id primitive.ObjectID, updatePriceDto dto.UpdatePriceDto, service *AccommodationService) error {
-	if err := service.store.UpdateDefaultPrice(id, updatePriceDto.Price); err != nil {
-		return err
+func expectedVersion(requestedVersion, currentVersion int64) (int64, error) {
+	if requestedVersion != 0 && requestedVersion != currentVersion {
+		return 0, domain.ErrVersionConflict
 	}
-	return nil
+	return currentVersion, nil
 }
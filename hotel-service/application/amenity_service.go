@@ -0,0 +1,98 @@
+package application
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/util"
+	"github.com/afiskon/promtail-client/promtail"
+	"go.opentelemetry.io/otel/trace"
+	"sync"
+	"time"
+)
+
+const knownCodesTTL = time.Minute
+
+type AmenityService struct {
+	store              domain.AmenityStore
+	accommodationStore domain.AccommodationStore
+	loki               promtail.Client
+	mutex              sync.RWMutex
+	knownCodes         map[string]bool
+	knownCodesLoadedAt time.Time
+	generation         int
+}
+
+func NewAmenityService(store domain.AmenityStore, accommodationStore domain.AccommodationStore, loki promtail.Client) *AmenityService {
+	return &AmenityService{
+		store:              store,
+		accommodationStore: accommodationStore,
+		loki:               loki,
+	}
+}
+
+func (service *AmenityService) Get(code string, span trace.Span, loki promtail.Client) (*domain.Amenity, error) {
+	util.HttpTraceInfo("Fetching amenity by code...", span, loki, "Get", code)
+	return service.store.Get(code)
+}
+
+func (service *AmenityService) GetAll(span trace.Span, loki promtail.Client) ([]*domain.Amenity, error) {
+	util.HttpTraceInfo("Fetching amenity catalogue...", span, loki, "GetAll", "")
+	return service.store.GetAll()
+}
+
+// KnownCodes is cached per replica; local changes invalidate it and the TTL bounds staleness from other replicas.
+func (service *AmenityService) KnownCodes(span trace.Span, loki promtail.Client) (map[string]bool, error) {
+	service.mutex.RLock()
+	codes, loadedAt, generation := service.knownCodes, service.knownCodesLoadedAt, service.generation
+	service.mutex.RUnlock()
+	if codes != nil && time.Since(loadedAt) < knownCodesTTL {
+		return codes, nil
+	}
+
+	amenities, err := service.GetAll(span, loki)
+	if err != nil {
+		return nil, err
+	}
+	codes = make(map[string]bool, len(amenities))
+	for _, amenity := range amenities {
+		codes[amenity.Code] = true
+	}
+	service.mutex.Lock()
+	if service.generation == generation {
+		service.knownCodes, service.knownCodesLoadedAt = codes, time.Now()
+	}
+	service.mutex.Unlock()
+	return codes, nil
+}
+
+func (service *AmenityService) invalidateKnownCodes() {
+	service.mutex.Lock()
+	service.knownCodes = nil
+	service.generation++
+	service.mutex.Unlock()
+}
+
+func (service *AmenityService) Add(amenity *domain.Amenity, span trace.Span, loki promtail.Client) error {
+	util.HttpTraceInfo("Inserting amenity...", span, loki, "Add", amenity.Code)
+	defer service.invalidateKnownCodes()
+	return service.store.Insert(amenity)
+}
+
+func (service *AmenityService) Update(amenity *domain.Amenity, span trace.Span, loki promtail.Client) error {
+	util.HttpTraceInfo("Updating amenity...", span, loki, "Update", amenity.Code)
+	defer service.invalidateKnownCodes()
+	return service.store.Update(amenity)
+}
+
+func (service *AmenityService) Delete(code string, span trace.Span, loki promtail.Client) error {
+	util.HttpTraceInfo("Checking amenity usage...", span, loki, "Delete", code)
+	used, err := service.accommodationStore.CountWithAmenity(code)
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return domain.ErrAmenityInUse
+	}
+	util.HttpTraceInfo("Deleting amenity...", span, loki, "Delete", code)
+	defer service.invalidateKnownCodes()
+	return service.store.Delete(code)
+}
@@ -0,0 +1,39 @@
+package application
+
+import (
+	"errors"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"log"
+)
+
+type CachingGeocoder struct {
+	geocoder domain.Geocoder
+	cache    domain.GeocodeCacheStore
+}
+
+func NewCachingGeocoder(geocoder domain.Geocoder, cache domain.GeocodeCacheStore) *CachingGeocoder {
+	return &CachingGeocoder{
+		geocoder: geocoder,
+		cache:    cache,
+	}
+}
+
+func (geocoder *CachingGeocoder) Geocode(address *domain.Address) (*domain.GeoPoint, error) {
+	key := address.GeocodeKey()
+	point, err := geocoder.cache.Get(key)
+	if err == nil {
+		return point, nil
+	}
+	if !errors.Is(err, domain.ErrGeocodeNotCached) {
+		log.Printf("Failed to read geocode cache: %v", err)
+	}
+
+	point, err = geocoder.geocoder.Geocode(address)
+	if err != nil {
+		return nil, err
+	}
+	if err := geocoder.cache.Put(key, point); err != nil {
+		log.Printf("Failed to write geocode cache: %v", err)
+	}
+	return point, nil
+}
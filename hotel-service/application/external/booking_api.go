@@ -7,27 +7,25 @@ import (
 	"github.com/afiskon/promtail-client/promtail"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.opentelemetry.io/otel/trace"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 	"log"
 )
 
-func NewBookingClient(address string) booking.BookingServiceClient {
-	conn, err := getConnection(address)
+func NewBookingClient(address string, config ResilienceConfig, transport credentials.TransportCredentials) booking.BookingServiceClient {
+	conn, err := dial(address, "booking", config, transport)
 	if err != nil {
 		log.Fatalf("Failed to start gRPC connection to Catalogue service: %v", err)
 	}
 	return booking.NewBookingServiceClient(conn)
 }
 
-func getConnection(address string) (*grpc.ClientConn, error) {
-	return grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
-}
-
-func CreateBookingUnavailability(bookingClient booking.BookingServiceClient, id primitive.ObjectID, reviewReservationRequestAutomatically bool, hostId string, name string, span trace.Span, loki promtail.Client) (*booking.AddUnavailabilityResponse, error) {
+// House rules and the cancellation policy are not sent to booking yet: booking-service v1.0.13's
+// AddUnavailabilityRequest and EditAccommodationRequest have no fields for them. Syncing them is
+// blocked on a booking-service proto release that adds those fields.
+func CreateBookingUnavailability(ctx context.Context, bookingClient booking.BookingServiceClient, id primitive.ObjectID, reviewReservationRequestAutomatically bool, hostId string, name string, span trace.Span, loki promtail.Client) (*booking.AddUnavailabilityResponse, error) {
 	util.HttpTraceInfo("Adding unavailability in booking service...", span, loki, "CreateBookingUnavailability", "")
 	return bookingClient.AddUnavailability(
-		context.TODO(),
+		callContext(ctx, span),
 		&booking.AddUnavailabilityRequest{
 			Id:                id.Hex(),
 			Automatically:     reviewReservationRequestAutomatically,
@@ -36,10 +34,10 @@ func CreateBookingUnavailability(bookingClient booking.BookingServiceClient, id
 		})
 }
 
-func UpdateBookingUnavailability(bookingClient booking.BookingServiceClient, id primitive.ObjectID, reviewReservationRequestAutomatically bool, hostId string, name string, span trace.Span, loki promtail.Client) (*booking.EditAccommodationResponse, error) {
+func UpdateBookingUnavailability(ctx context.Context, bookingClient booking.BookingServiceClient, id primitive.ObjectID, reviewReservationRequestAutomatically bool, hostId string, name string, span trace.Span, loki promtail.Client) (*booking.EditAccommodationResponse, error) {
 	util.HttpTraceInfo("Update unavailability in booking service...", span, loki, "UpdateBookingUnavailability", "")
 	return bookingClient.EditAccommodation(
-		context.TODO(),
+		callContext(ctx, span),
 		&booking.EditAccommodationRequest{
 			Id:                id.Hex(),
 			Automatically:     reviewReservationRequestAutomatically,
@@ -48,10 +46,10 @@ func UpdateBookingUnavailability(bookingClient booking.BookingServiceClient, id
 		})
 }
 
-func CheckAccommodationHasReservation(bookingClient booking.BookingServiceClient, accommodationId primitive.ObjectID, span trace.Span, loki promtail.Client) (*booking.CheckAccommodationHasReservationResponse, error) {
+func CheckAccommodationHasReservation(ctx context.Context, bookingClient booking.BookingServiceClient, accommodationId primitive.ObjectID, span trace.Span, loki promtail.Client) (*booking.CheckAccommodationHasReservationResponse, error) {
 	util.HttpTraceInfo("Check if accommodation has reservation in booking service...", span, loki, "CheckAccommodationHasReservation", "")
 	return bookingClient.CheckAccommodationHasReservation(
-		context.TODO(),
+		callContext(ctx, span),
 		&booking.CheckAccommodationHasReservationRequest{
 			AccommodationId: accommodationId.Hex(),
 		})
@@ -0,0 +1,88 @@
+package external
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type CircuitBreaker struct {
+	mutex            sync.Mutex
+	failureThreshold int
+	openTimeout      time.Duration
+	state            string
+	failures         int
+	openedAt         time.Time
+	probeInFlight    bool
+	now              func() time.Time
+}
+
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            CircuitClosed,
+		now:              time.Now,
+	}
+}
+
+func (breaker *CircuitBreaker) Allow() error {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	switch breaker.state {
+	case CircuitOpen:
+		if breaker.now().Sub(breaker.openedAt) < breaker.openTimeout {
+			return ErrCircuitOpen
+		}
+		breaker.state = CircuitHalfOpen
+		breaker.probeInFlight = true
+		return nil
+	case CircuitHalfOpen:
+		if breaker.probeInFlight {
+			return ErrCircuitOpen
+		}
+		breaker.probeInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (breaker *CircuitBreaker) Record(success bool) {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	breaker.probeInFlight = false
+	if success {
+		breaker.state = CircuitClosed
+		breaker.failures = 0
+		return
+	}
+	breaker.failures++
+	if breaker.state == CircuitHalfOpen || (breaker.failureThreshold > 0 && breaker.failures >= breaker.failureThreshold) {
+		breaker.state = CircuitOpen
+		breaker.openedAt = breaker.now()
+	}
+}
+
+func (breaker *CircuitBreaker) State() string {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	if breaker.state == CircuitOpen && breaker.now().Sub(breaker.openedAt) >= breaker.openTimeout {
+		return CircuitHalfOpen
+	}
+	return breaker.state
+}
+
+func (breaker *CircuitBreaker) Release() {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	breaker.probeInFlight = false
+}
@@ -0,0 +1,197 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	booking "github.com/ZMS-DevOps/booking-service/proto"
+	search "github.com/ZMS-DevOps/search-service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxRecordedStubCalls = 500
+
+type StubCall struct {
+	Method  string      `json:"method"`
+	Request interface{} `json:"request"`
+	Error   string      `json:"error,omitempty"`
+	Time    time.Time   `json:"time"`
+}
+
+type StubRecorder struct {
+	mutex    sync.Mutex
+	calls    []StubCall
+	failures map[string]codes.Code
+}
+
+func NewStubRecorder(failures string) (*StubRecorder, error) {
+	recorder := &StubRecorder{failures: map[string]codes.Code{}}
+	for _, failure := range strings.Split(failures, ",") {
+		if strings.TrimSpace(failure) == "" {
+			continue
+		}
+		method, code, found := strings.Cut(failure, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid stub failure %q, expected method=code", failure)
+		}
+		if err := recorder.SetFailure(strings.TrimSpace(method), strings.TrimSpace(code)); err != nil {
+			return nil, err
+		}
+	}
+	return recorder, nil
+}
+
+func (recorder *StubRecorder) SetFailure(method string, code string) error {
+	var parsed codes.Code
+	if err := parsed.UnmarshalJSON([]byte(`"` + strings.ToUpper(code) + `"`)); err != nil {
+		return fmt.Errorf("invalid gRPC status code %q", code)
+	}
+	if parsed == codes.OK {
+		return fmt.Errorf("failure for %s must not be OK", method)
+	}
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.failures[method] = parsed
+	return nil
+}
+
+func (recorder *StubRecorder) ClearFailure(method string) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	delete(recorder.failures, method)
+}
+
+func (recorder *StubRecorder) Failures() map[string]string {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	failures := make(map[string]string, len(recorder.failures))
+	for method, code := range recorder.failures {
+		failures[method] = code.String()
+	}
+	return failures
+}
+
+func (recorder *StubRecorder) Calls() []StubCall {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	return append([]StubCall{}, recorder.calls...)
+}
+
+func (recorder *StubRecorder) Reset() {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.calls = nil
+}
+
+func (recorder *StubRecorder) record(method string, request interface{}) error {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	call := StubCall{Method: method, Request: request, Time: time.Now()}
+	var err error
+	if code, ok := recorder.failures[method]; ok {
+		err = status.Errorf(code, "local stub failure injected for %s", method)
+		call.Error = err.Error()
+	}
+	recorder.calls = append(recorder.calls, call)
+	if len(recorder.calls) > maxRecordedStubCalls {
+		recorder.calls = recorder.calls[len(recorder.calls)-maxRecordedStubCalls:]
+	}
+	return err
+}
+
+type LocalBookingClient struct {
+	recorder *StubRecorder
+}
+
+func NewLocalBookingClient(recorder *StubRecorder) booking.BookingServiceClient {
+	return &LocalBookingClient{recorder: recorder}
+}
+
+func (client *LocalBookingClient) AddUnavailability(_ context.Context, in *booking.AddUnavailabilityRequest, _ ...grpc.CallOption) (*booking.AddUnavailabilityResponse, error) {
+	if err := client.recorder.record("booking.AddUnavailability", in); err != nil {
+		return nil, err
+	}
+	return &booking.AddUnavailabilityResponse{}, nil
+}
+
+func (client *LocalBookingClient) EditAccommodation(_ context.Context, in *booking.EditAccommodationRequest, _ ...grpc.CallOption) (*booking.EditAccommodationResponse, error) {
+	if err := client.recorder.record("booking.EditAccommodation", in); err != nil {
+		return nil, err
+	}
+	return &booking.EditAccommodationResponse{}, nil
+}
+
+func (client *LocalBookingClient) FilterAvailableAccommodation(_ context.Context, in *booking.FilterAvailableAccommodationRequest, _ ...grpc.CallOption) (*booking.FilterAvailableAccommodationResponse, error) {
+	if err := client.recorder.record("booking.FilterAvailableAccommodation", in); err != nil {
+		return nil, err
+	}
+	return &booking.FilterAvailableAccommodationResponse{AccommodationIds: in.AccommodationIds}, nil
+}
+
+func (client *LocalBookingClient) CheckDeleteHost(_ context.Context, in *booking.CheckDeleteHostRequest, _ ...grpc.CallOption) (*booking.CheckDeleteHostResponse, error) {
+	if err := client.recorder.record("booking.CheckDeleteHost", in); err != nil {
+		return nil, err
+	}
+	return &booking.CheckDeleteHostResponse{Success: true}, nil
+}
+
+func (client *LocalBookingClient) CheckDeleteClient(_ context.Context, in *booking.CheckDeleteClientRequest, _ ...grpc.CallOption) (*booking.CheckDeleteClientResponse, error) {
+	if err := client.recorder.record("booking.CheckDeleteClient", in); err != nil {
+		return nil, err
+	}
+	return &booking.CheckDeleteClientResponse{Success: true}, nil
+}
+
+func (client *LocalBookingClient) CheckGuestHasReservationForHost(_ context.Context, in *booking.CheckGuestHasReservationForHostRequest, _ ...grpc.CallOption) (*booking.CheckGuestHasReservationForHostResponse, error) {
+	if err := client.recorder.record("booking.CheckGuestHasReservationForHost", in); err != nil {
+		return nil, err
+	}
+	return &booking.CheckGuestHasReservationForHostResponse{}, nil
+}
+
+func (client *LocalBookingClient) CheckGuestHasReservationForAccommodation(_ context.Context, in *booking.CheckGuestHasReservationForAccommodationRequest, _ ...grpc.CallOption) (*booking.CheckGuestHasReservationForAccommodationResponse, error) {
+	if err := client.recorder.record("booking.CheckGuestHasReservationForAccommodation", in); err != nil {
+		return nil, err
+	}
+	return &booking.CheckGuestHasReservationForAccommodationResponse{}, nil
+}
+
+func (client *LocalBookingClient) CheckAccommodationHasReservation(_ context.Context, in *booking.CheckAccommodationHasReservationRequest, _ ...grpc.CallOption) (*booking.CheckAccommodationHasReservationResponse, error) {
+	if err := client.recorder.record("booking.CheckAccommodationHasReservation", in); err != nil {
+		return nil, err
+	}
+	return &booking.CheckAccommodationHasReservationResponse{Success: true}, nil
+}
+
+type LocalSearchClient struct {
+	recorder *StubRecorder
+}
+
+func NewLocalSearchClient(recorder *StubRecorder) search.SearchServiceClient {
+	return &LocalSearchClient{recorder: recorder}
+}
+
+func (client *LocalSearchClient) AddAccommodation(_ context.Context, in *search.AddAccommodationRequest, _ ...grpc.CallOption) (*search.AddAccommodationResponse, error) {
+	if err := client.recorder.record("search.AddAccommodation", in); err != nil {
+		return nil, err
+	}
+	return &search.AddAccommodationResponse{}, nil
+}
+
+func (client *LocalSearchClient) EditAccommodation(_ context.Context, in *search.EditAccommodationRequest, _ ...grpc.CallOption) (*search.EditAccommodationResponse, error) {
+	if err := client.recorder.record("search.EditAccommodation", in); err != nil {
+		return nil, err
+	}
+	return &search.EditAccommodationResponse{}, nil
+}
+
+func (client *LocalSearchClient) DeleteAccommodation(_ context.Context, in *search.DeleteAccommodationRequest, _ ...grpc.CallOption) (*search.DeleteAccommodationResponse, error) {
+	if err := client.recorder.record("search.DeleteAccommodation", in); err != nil {
+		return nil, err
+	}
+	return &search.DeleteAccommodationResponse{}, nil
+}
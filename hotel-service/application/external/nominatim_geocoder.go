@@ -0,0 +1,97 @@
+package external
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type NominatimGeocoder struct {
+	baseUrl     string
+	userAgent   string
+	client      *http.Client
+	minInterval time.Duration
+	mutex       sync.Mutex
+	lastRequest time.Time
+}
+
+type nominatimResult struct {
+	Latitude  string `json:"lat"`
+	Longitude string `json:"lon"`
+}
+
+func NewNominatimGeocoder(baseUrl, userAgent string, timeout, minInterval time.Duration) *NominatimGeocoder {
+	return &NominatimGeocoder{
+		baseUrl:     strings.TrimRight(baseUrl, "/"),
+		userAgent:   userAgent,
+		client:      &http.Client{Timeout: timeout},
+		minInterval: minInterval,
+	}
+}
+
+func (geocoder *NominatimGeocoder) Geocode(address *domain.Address) (*domain.GeoPoint, error) {
+	query := url.Values{}
+	query.Set("format", "jsonv2")
+	query.Set("limit", "1")
+	setIfPresent(query, "street", address.Street)
+	setIfPresent(query, "city", address.City)
+	setIfPresent(query, "state", address.Region)
+	setIfPresent(query, "countrycodes", strings.ToLower(address.CountryCode))
+	setIfPresent(query, "postalcode", address.PostalCode)
+
+	request, err := http.NewRequest(http.MethodGet, geocoder.baseUrl+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", geocoder.userAgent)
+	request.Header.Set("Accept", "application/json")
+
+	geocoder.throttle()
+	response, err := geocoder.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding request failed with status %d", response.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(response.Body).Decode(&results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, domain.ErrAddressNotFound
+	}
+	latitude, err := strconv.ParseFloat(results[0].Latitude, 64)
+	if err != nil {
+		return nil, err
+	}
+	longitude, err := strconv.ParseFloat(results[0].Longitude, 64)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewGeoPoint(latitude, longitude), nil
+}
+
+// throttle spaces requests at least minInterval apart across all callers.
+func (geocoder *NominatimGeocoder) throttle() {
+	geocoder.mutex.Lock()
+	defer geocoder.mutex.Unlock()
+	if wait := geocoder.minInterval - time.Since(geocoder.lastRequest); wait > 0 {
+		time.Sleep(wait)
+	}
+	geocoder.lastRequest = time.Now()
+}
+
+func setIfPresent(query url.Values, key, value string) {
+	if strings.TrimSpace(value) != "" {
+		query.Set(key, strings.TrimSpace(value))
+	}
+}
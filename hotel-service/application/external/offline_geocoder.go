@@ -0,0 +1,59 @@
+package external
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"strconv"
+	"strings"
+)
+
+//go:embed gazetteer/cities.csv
+var gazetteer []byte
+
+type OfflineGeocoder struct {
+	byCity   map[string]*domain.GeoPoint
+	byRegion map[string]*domain.GeoPoint
+}
+
+func NewOfflineGeocoder() (*OfflineGeocoder, error) {
+	records, err := csv.NewReader(bytes.NewReader(gazetteer)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	geocoder := &OfflineGeocoder{
+		byCity:   map[string]*domain.GeoPoint{},
+		byRegion: map[string]*domain.GeoPoint{},
+	}
+	for _, record := range records[1:] {
+		latitude, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return nil, err
+		}
+		longitude, err := strconv.ParseFloat(record[4], 64)
+		if err != nil {
+			return nil, err
+		}
+		point := domain.NewGeoPoint(latitude, longitude)
+		geocoder.byCity[gazetteerKey(record[0], record[2])] = point
+		if _, ok := geocoder.byRegion[gazetteerKey(record[1], record[2])]; !ok {
+			geocoder.byRegion[gazetteerKey(record[1], record[2])] = point
+		}
+	}
+	return geocoder, nil
+}
+
+func (geocoder *OfflineGeocoder) Geocode(address *domain.Address) (*domain.GeoPoint, error) {
+	if point, ok := geocoder.byCity[gazetteerKey(address.City, address.CountryCode)]; ok {
+		return point, nil
+	}
+	if point, ok := geocoder.byRegion[gazetteerKey(address.Region, address.CountryCode)]; ok {
+		return point, nil
+	}
+	return nil, domain.ErrAddressNotFound
+}
+
+func gazetteerKey(place, countryCode string) string {
+	return strings.ToLower(strings.TrimSpace(place)) + "|" + strings.ToUpper(strings.TrimSpace(countryCode))
+}
@@ -0,0 +1,195 @@
+package external
+
+import (
+	"context"
+	"expvar"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"math/rand"
+	"time"
+)
+
+var (
+	clientAttempts      = expvar.NewMap("grpc_client_attempts")
+	clientRetries       = expvar.NewMap("grpc_client_retries")
+	clientRejections    = expvar.NewMap("grpc_client_circuit_rejections")
+	clientLatencyMillis = expvar.NewMap("grpc_client_latency_ms")
+	clientCircuitStates = expvar.NewMap("grpc_client_circuit_state")
+)
+
+var idempotentMethods = map[string]bool{
+	"/booking.BookingService/EditAccommodation":                        true,
+	"/booking.BookingService/FilterAvailableAccommodation":             true,
+	"/booking.BookingService/CheckDeleteHost":                          true,
+	"/booking.BookingService/CheckDeleteClient":                        true,
+	"/booking.BookingService/CheckGuestHasReservationForHost":          true,
+	"/booking.BookingService/CheckGuestHasReservationForAccommodation": true,
+	"/booking.BookingService/CheckAccommodationHasReservation":         true,
+	"/search.SearchService/EditAccommodation":                          true,
+	"/search.SearchService/DeleteAccommodation":                        true,
+}
+
+type ResilienceConfig struct {
+	Timeout          time.Duration
+	MethodTimeouts   map[string]time.Duration
+	MaxRetries       int
+	BaseBackoff      time.Duration
+	MaxBackoff       time.Duration
+	RetryDeadline    time.Duration
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+func NewResilienceInterceptor(service string, config ResilienceConfig) grpc.UnaryClientInterceptor {
+	breaker := NewCircuitBreaker(config.FailureThreshold, config.OpenTimeout)
+	clientCircuitStates.Set(service, expvar.Func(func() interface{} { return breaker.State() }))
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		span := trace.SpanFromContext(ctx)
+		if config.RetryDeadline > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, config.RetryDeadline)
+			defer cancel()
+		}
+		maxAttempts := 1
+		if idempotentMethods[method] {
+			maxAttempts += config.MaxRetries
+		}
+
+		var err error
+		for attempt := 1; attempt <= maxAttempts; attempt++ {
+			if attempt > 1 {
+				backoff := jitteredBackoff(config.BaseBackoff, config.MaxBackoff, attempt-1)
+				clientRetries.Add(method, 1)
+				span.AddEvent("grpc.retry", trace.WithAttributes(
+					attribute.String("rpc.method", method),
+					attribute.Int("rpc.attempt", attempt),
+					attribute.Int64("backoff_ms", backoff.Milliseconds())))
+				if sleepErr := sleep(ctx, backoff); sleepErr != nil {
+					return err
+				}
+			}
+
+			if breakerErr := breaker.Allow(); breakerErr != nil {
+				clientRejections.Add(service, 1)
+				span.AddEvent("grpc.circuit_open", trace.WithAttributes(attribute.String("rpc.method", method)))
+				if err != nil {
+					return err
+				}
+				return status.Errorf(codes.Unavailable, "%s: %v", service, breakerErr)
+			}
+
+			err = invokeAttempt(ctx, config.timeoutFor(method), method, req, reply, cc, invoker, opts...)
+			code := status.Code(err)
+			switch {
+			case isDependencyFailure(code):
+				breaker.Record(false)
+			case code == codes.Canceled:
+				breaker.Release()
+			default:
+				breaker.Record(true)
+			}
+
+			clientAttempts.Add(method+":"+code.String(), 1)
+			span.AddEvent("grpc.attempt", trace.WithAttributes(
+				attribute.String("rpc.method", method),
+				attribute.Int("rpc.attempt", attempt),
+				attribute.String("rpc.grpc.status_code", code.String())))
+
+			if err == nil || !isRetryable(code) || ctx.Err() != nil {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+func invokeAttempt(ctx context.Context, timeout time.Duration, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	clientLatencyMillis.Add(method, time.Since(start).Milliseconds())
+	return err
+}
+
+func (config ResilienceConfig) timeoutFor(method string) time.Duration {
+	if timeout, ok := config.MethodTimeouts[method]; ok {
+		return timeout
+	}
+	return config.Timeout
+}
+
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if st, ok := status.FromError(err); ok {
+		return isRetryable(st.Code())
+	}
+	return false
+}
+
+func isRetryable(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+func isDependencyFailure(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+func jitteredBackoff(base, max time.Duration, retry int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	shift := retry - 1
+	backoff := base << shift
+	if shift >= 63 || backoff>>shift != base || backoff <= 0 || (max > 0 && backoff > max) {
+		backoff = max
+	}
+	if backoff <= 0 {
+		backoff = base
+	}
+	return time.Duration(rand.Int63n(int64(backoff)) + 1)
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func callContext(ctx context.Context, span trace.Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, span)
+}
+
+func dial(address string, service string, config ResilienceConfig, transport credentials.TransportCredentials) (*grpc.ClientConn, error) {
+	return grpc.Dial(address,
+		grpc.WithTransportCredentials(transport),
+		grpc.WithUnaryInterceptor(NewResilienceInterceptor(service, config)))
+}
@@ -7,34 +7,29 @@ import (
 	"github.com/afiskon/promtail-client/promtail"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.opentelemetry.io/otel/trace"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 	"log"
 )
 
-func NewSearchClient(address string) search.SearchServiceClient {
-	conn, err := getSearchConnection(address)
+func NewSearchClient(address string, config ResilienceConfig, transport credentials.TransportCredentials) search.SearchServiceClient {
+	conn, err := dial(address, "search", config, transport)
 	if err != nil {
 		log.Fatalf("Failed to start gRPC connection to Search service: %v", err)
 	}
 	return search.NewSearchServiceClient(conn)
 }
 
-func getSearchConnection(address string) (*grpc.ClientConn, error) {
-	return grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
-}
-
-func AddSearchAccommodation(searchClient search.SearchServiceClient, accommodation *search.Accommodation, span trace.Span, loki promtail.Client) (*search.AddAccommodationResponse, error) {
+func AddSearchAccommodation(ctx context.Context, searchClient search.SearchServiceClient, accommodation *search.Accommodation, span trace.Span, loki promtail.Client) (*search.AddAccommodationResponse, error) {
 	util.HttpTraceInfo("Adding accommodation in search service...", span, loki, "AddSearchAccommodation", "")
-	return searchClient.AddAccommodation(context.TODO(), &search.AddAccommodationRequest{Accommodation: accommodation})
+	return searchClient.AddAccommodation(callContext(ctx, span), &search.AddAccommodationRequest{Accommodation: accommodation})
 }
 
-func EditSearchAccommodation(searchClient search.SearchServiceClient, accommodation *search.Accommodation, span trace.Span, loki promtail.Client) (*search.EditAccommodationResponse, error) {
+func EditSearchAccommodation(ctx context.Context, searchClient search.SearchServiceClient, accommodation *search.Accommodation, span trace.Span, loki promtail.Client) (*search.EditAccommodationResponse, error) {
 	util.HttpTraceInfo("Edit accommodation in search service...", span, loki, "EditSearchAccommodation", "")
-	return searchClient.EditAccommodation(context.TODO(), &search.EditAccommodationRequest{Accommodation: accommodation})
+	return searchClient.EditAccommodation(callContext(ctx, span), &search.EditAccommodationRequest{Accommodation: accommodation})
 }
 
-func DeleteSearchAccommodation(searchClient search.SearchServiceClient, id primitive.ObjectID, span trace.Span, loki promtail.Client) (*search.DeleteAccommodationResponse, error) {
+func DeleteSearchAccommodation(ctx context.Context, searchClient search.SearchServiceClient, id primitive.ObjectID, span trace.Span, loki promtail.Client) (*search.DeleteAccommodationResponse, error) {
 	util.HttpTraceInfo("Delete accommodation in search service...", span, loki, "DeleteSearchAccommodation", "")
-	return searchClient.DeleteAccommodation(context.TODO(), &search.DeleteAccommodationRequest{AccommodationId: id.Hex()})
+	return searchClient.DeleteAccommodation(callContext(ctx, span), &search.DeleteAccommodationRequest{AccommodationId: id.Hex()})
 }
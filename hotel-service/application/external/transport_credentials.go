@@ -0,0 +1,200 @@
+package external
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	TransportInsecure = "insecure"
+	TransportTLS      = "tls"
+	TransportMTLS     = "mtls"
+)
+
+type TransportConfig struct {
+	Mode       string
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+func (config TransportConfig) Validate() error {
+	switch config.Mode {
+	case "", TransportInsecure:
+		return nil
+	case TransportTLS:
+		return nil
+	case TransportMTLS:
+		if config.CertFile == "" || config.KeyFile == "" {
+			return fmt.Errorf("transport %q requires a certificate and key file", config.Mode)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown transport %q, expected %s, %s or %s", config.Mode, TransportInsecure, TransportTLS, TransportMTLS)
+	}
+}
+
+func NewClientCredentials(config TransportConfig) (credentials.TransportCredentials, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Mode == "" || config.Mode == TransportInsecure {
+		return insecure.NewCredentials(), nil
+	}
+	reloader := NewCertificateReloader(config)
+	if _, err := reloader.current(); err != nil {
+		return nil, err
+	}
+	return &reloadingCredentials{reloader: reloader, serverName: config.ServerName}, nil
+}
+
+type certificateMaterial struct {
+	certificate *tls.Certificate
+	roots       *x509.CertPool
+}
+
+type CertificateReloader struct {
+	mutex    sync.Mutex
+	config   TransportConfig
+	material *certificateMaterial
+	modTimes map[string]time.Time
+}
+
+func NewCertificateReloader(config TransportConfig) *CertificateReloader {
+	return &CertificateReloader{config: config}
+}
+
+func (reloader *CertificateReloader) current() (*certificateMaterial, error) {
+	reloader.mutex.Lock()
+	defer reloader.mutex.Unlock()
+	modTimes, err := reloader.statFiles()
+	if err == nil && reloader.material != nil && sameModTimes(modTimes, reloader.modTimes) {
+		return reloader.material, nil
+	}
+	if err == nil {
+		var material *certificateMaterial
+		if material, err = reloader.load(); err == nil {
+			if reloader.material != nil {
+				log.Printf("Reloaded gRPC transport certificates")
+			}
+			reloader.material = material
+			reloader.modTimes = modTimes
+			return material, nil
+		}
+	}
+	if reloader.material == nil {
+		return nil, err
+	}
+	log.Printf("Failed to reload gRPC transport certificates, keeping previous ones: %v", err)
+	return reloader.material, nil
+}
+
+func (reloader *CertificateReloader) files() []string {
+	var files []string
+	for _, file := range []string{reloader.config.CAFile, reloader.config.CertFile, reloader.config.KeyFile} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+func (reloader *CertificateReloader) statFiles() (map[string]time.Time, error) {
+	modTimes := map[string]time.Time{}
+	for _, file := range reloader.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[file] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func (reloader *CertificateReloader) load() (*certificateMaterial, error) {
+	material := &certificateMaterial{}
+	if reloader.config.CAFile != "" {
+		pem, err := os.ReadFile(reloader.config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		material.roots = x509.NewCertPool()
+		if !material.roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", reloader.config.CAFile)
+		}
+	}
+	if reloader.config.CertFile != "" && reloader.config.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(reloader.config.CertFile, reloader.config.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		material.certificate = &certificate
+	}
+	return material, nil
+}
+
+func sameModTimes(first, second map[string]time.Time) bool {
+	if len(first) != len(second) {
+		return false
+	}
+	for file, modTime := range first {
+		if !modTime.Equal(second[file]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (reloader *CertificateReloader) clientConfig(serverName string) (*tls.Config, error) {
+	material, err := reloader.current()
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: material.roots, ServerName: serverName}
+	if reloader.config.Mode == TransportMTLS {
+		config.Certificates = []tls.Certificate{*material.certificate}
+	}
+	return config, nil
+}
+
+type reloadingCredentials struct {
+	reloader   *CertificateReloader
+	serverName string
+}
+
+func (creds *reloadingCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	config, err := creds.reloader.clientConfig(creds.serverName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return credentials.NewTLS(config).ClientHandshake(ctx, authority, conn)
+}
+
+// ServerHandshake is never used: the service only dials booking and search, it serves no gRPC itself.
+func (creds *reloadingCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("gRPC transport credentials are client-only")
+}
+
+func (creds *reloadingCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "tls", SecurityVersion: "1.2", ServerName: creds.serverName}
+}
+
+func (creds *reloadingCredentials) Clone() credentials.TransportCredentials {
+	return &reloadingCredentials{reloader: creds.reloader, serverName: creds.serverName}
+}
+
+func (creds *reloadingCredentials) OverrideServerName(serverName string) error {
+	creds.serverName = serverName
+	return nil
+}
@@ -0,0 +1,30 @@
+package external
+
+import (
+	"encoding/json"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/dto"
+	"github.com/ZMS-DevOps/hotel-service/util"
+	"github.com/afiskon/promtail-client/promtail"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const HostDeletionRejectedTopic = "accommodation.delete.rejected"
+
+type EventProducer interface {
+	Produce(msg *kafka.Message, deliveryChan chan kafka.Event) error
+}
+
+func PublishHostDeletionRejected(producer EventProducer, hostId string, reason string, span trace.Span, loki promtail.Client) error {
+	util.HttpTraceInfo("Publishing host deletion rejection to user service...", span, loki, "PublishHostDeletionRejected", hostId)
+	value, err := json.Marshal(dto.HostDeletionRejectedEvent{HostId: hostId, Reason: reason})
+	if err != nil {
+		return err
+	}
+	topic := HostDeletionRejectedTopic
+	return producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            []byte(hostId),
+		Value:          value,
+	}, nil)
+}
@@ -0,0 +1,261 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	booking "github.com/ZMS-DevOps/booking-service/proto"
+	"github.com/ZMS-DevOps/hotel-service/application/external"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/util"
+	"github.com/afiskon/promtail-client/promtail"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+var hostDeletionActor = domain.SystemActor("host-deletion-saga")
+
+const (
+	hostDeletionAttempts     = 3
+	hostDeletionRetryBackoff = 100 * time.Millisecond
+)
+
+type HostDeletionSagaService struct {
+	sagaStore            domain.HostDeletionSagaStore
+	accommodationService *AccommodationService
+	bookingClient        booking.BookingServiceClient
+	producer             external.EventProducer
+	lease                time.Duration
+	loki                 promtail.Client
+}
+
+func NewHostDeletionSagaService(sagaStore domain.HostDeletionSagaStore, accommodationService *AccommodationService, bookingClient booking.BookingServiceClient, producer external.EventProducer, lease time.Duration, loki promtail.Client) *HostDeletionSagaService {
+	return &HostDeletionSagaService{
+		sagaStore:            sagaStore,
+		accommodationService: accommodationService,
+		bookingClient:        bookingClient,
+		producer:             producer,
+		lease:                lease,
+		loki:                 loki,
+	}
+}
+
+func (service *HostDeletionSagaService) Start(ctx context.Context, hostId string, span trace.Span, loki promtail.Client) (*domain.HostDeletionSaga, error) {
+	util.HttpTraceInfo("Starting host deletion saga...", span, loki, "StartHostDeletionSaga", hostId)
+	saga, err := service.sagaStore.GetUnfinishedByHostId(hostId)
+	if err == nil {
+		return saga, service.run(ctx, saga, span, loki)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	accommodations, err := service.accommodationService.store.GetByHostId(hostId)
+	if err != nil {
+		return nil, err
+	}
+	saga = &domain.HostDeletionSaga{
+		HostId:           hostId,
+		Status:           domain.HostDeletionChecking,
+		AccommodationIds: []primitive.ObjectID{},
+		DeletedIds:       []primitive.ObjectID{},
+	}
+	for _, accommodation := range accommodations {
+		saga.AccommodationIds = append(saga.AccommodationIds, accommodation.Id)
+		for _, roomType := range accommodation.RoomTypes {
+			saga.RoomTypeIds = append(saga.RoomTypeIds, roomType.Id)
+		}
+	}
+	if err := service.sagaStore.Insert(saga); err != nil {
+		return nil, err
+	}
+	return saga, service.run(ctx, saga, span, loki)
+}
+
+func (service *HostDeletionSagaService) ResumeUnfinished(ctx context.Context, span trace.Span, loki promtail.Client) error {
+	util.HttpTraceInfo("Resuming unfinished host deletion sagas...", span, loki, "ResumeHostDeletionSagas", "")
+	sagas, err := service.sagaStore.GetUnfinished()
+	if err != nil {
+		return err
+	}
+	for _, saga := range sagas {
+		if err := service.run(ctx, saga, span, loki); err != nil {
+			util.HttpTraceError(err, "failed to resume host deletion saga", span, loki, "ResumeHostDeletionSagas", saga.HostId)
+		}
+	}
+	return nil
+}
+
+// run holds the saga lease while advancing it, so the startup resumer, the periodic resumer and a
+// redelivered Kafka message never work on the same saga at once.
+func (service *HostDeletionSagaService) run(ctx context.Context, saga *domain.HostDeletionSaga, span trace.Span, loki promtail.Client) error {
+	if err := service.sagaStore.Lease(saga, time.Now().Add(service.lease)); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			util.HttpTraceInfo("Host deletion saga is held by another runner, skipping", span, loki, "RunHostDeletionSaga", saga.HostId)
+			return nil
+		}
+		return err
+	}
+	err := service.advance(ctx, saga, span, loki)
+	if errors.Is(err, domain.ErrVersionConflict) {
+		util.HttpTraceInfo("Host deletion saga was taken over by another runner", span, loki, "RunHostDeletionSaga", saga.HostId)
+		return nil
+	}
+	saga.LeaseExpiresAt = time.Time{}
+	if releaseErr := service.sagaStore.Update(saga); releaseErr != nil {
+		util.HttpTraceError(releaseErr, "failed to release host deletion saga lease", span, loki, "RunHostDeletionSaga", saga.HostId)
+	}
+	return err
+}
+
+func (service *HostDeletionSagaService) advance(ctx context.Context, saga *domain.HostDeletionSaga, span trace.Span, loki promtail.Client) error {
+	if saga.Status == domain.HostDeletionChecking {
+		if err := service.checkReservations(ctx, saga, span, loki); err != nil {
+			return err
+		}
+	}
+	if saga.Status == domain.HostDeletionDeleting {
+		if err := service.deleteAccommodations(ctx, saga, span, loki); err != nil {
+			return err
+		}
+	}
+	if saga.Status == domain.HostDeletionCompensating {
+		if err := service.compensate(ctx, saga, span, loki); err != nil {
+			return err
+		}
+	}
+	if saga.Status == domain.HostDeletionRejecting {
+		return service.publishRejection(saga, span, loki)
+	}
+	return nil
+}
+
+func (service *HostDeletionSagaService) checkReservations(ctx context.Context, saga *domain.HostDeletionSaga, span trace.Span, loki promtail.Client) error {
+	util.HttpTraceInfo("Checking host accommodations for reservations...", span, loki, "CheckReservations", saga.HostId)
+	var reserved []string
+	for _, id := range append(append([]primitive.ObjectID{}, saga.AccommodationIds...), saga.RoomTypeIds...) {
+		canDelete, err := external.CheckAccommodationHasReservation(ctx, service.bookingClient, id, span, loki)
+		if err != nil {
+			return err
+		}
+		if !canDelete.Success {
+			reserved = append(reserved, id.Hex())
+		}
+	}
+	if len(reserved) > 0 {
+		return service.transition(saga, domain.HostDeletionRejecting, domain.HostDeletionRejected, fmt.Sprintf("accommodations have active reservations: %v", reserved))
+	}
+
+	saga.Status = domain.HostDeletionDeleting
+	return service.save(saga)
+}
+
+func (service *HostDeletionSagaService) deleteAccommodations(ctx context.Context, saga *domain.HostDeletionSaga, span trace.Span, loki promtail.Client) error {
+	util.HttpTraceInfo("Deleting host accommodations...", span, loki, "DeleteHostAccommodations", saga.HostId)
+	for _, id := range saga.AccommodationIds {
+		if saga.IsDeleted(id) {
+			continue
+		}
+		deleted, err := service.deleteAccommodationWithRetry(ctx, saga, id, span, loki)
+		if deleted && !isTransient(err) {
+			saga.DeletedIds = append(saga.DeletedIds, id)
+			if err := service.save(saga); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			util.HttpTraceError(err, "failed to delete host accommodation", span, loki, "DeleteHostAccommodations", id.Hex())
+			if isTransient(err) {
+				return err
+			}
+			return service.transition(saga, domain.HostDeletionCompensating, domain.HostDeletionCompensated, fmt.Sprintf("accommodations could not be deleted: %v", err))
+		}
+	}
+
+	saga.Status = domain.HostDeletionCompleted
+	return service.save(saga)
+}
+
+func (service *HostDeletionSagaService) deleteAccommodationWithRetry(ctx context.Context, saga *domain.HostDeletionSaga, id primitive.ObjectID, span trace.Span, loki promtail.Client) (bool, error) {
+	backoff := hostDeletionRetryBackoff
+	for attempt := 1; ; attempt++ {
+		deleted, err := service.deleteAccommodation(ctx, saga, id, span, loki)
+		if err == nil || !isTransient(err) || attempt == hostDeletionAttempts {
+			return deleted, err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (service *HostDeletionSagaService) deleteAccommodation(ctx context.Context, saga *domain.HostDeletionSaga, id primitive.ObjectID, span trace.Span, loki promtail.Client) (bool, error) {
+	accommodation, err := service.accommodationService.GetIncludingDeleted(id, span, loki)
+	if err != nil {
+		return false, err
+	}
+	if accommodation.DeletedAt != nil && accommodation.DeletedAt.Before(saga.CreatedAt) {
+		return false, nil
+	}
+	deletedAt := time.Now()
+	err = service.accommodationService.store.SoftDelete(id, deletedAt)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return false, err
+	}
+	if err == nil {
+		service.accommodationService.recordAudit(id, hostDeletionActor, domain.AuditDelete, deletionChanges(nil, &deletedAt), span, loki)
+	}
+	if accommodation.Status != domain.Published {
+		return true, nil
+	}
+	return true, service.accommodationService.notifySearchServiceWhenDeletingAccommodation(ctx, id, span, loki)
+}
+
+func (service *HostDeletionSagaService) compensate(ctx context.Context, saga *domain.HostDeletionSaga, span trace.Span, loki promtail.Client) error {
+	util.HttpTraceInfo("Compensating host deletion saga...", span, loki, "CompensateHostDeletion", saga.HostId)
+	for len(saga.DeletedIds) > 0 {
+		id := saga.DeletedIds[0]
+		_, err := service.accommodationService.restoreAccommodation(ctx, id, nil, hostDeletionActor, span, loki)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			util.HttpTraceError(err, "failed to restore host accommodation", span, loki, "CompensateHostDeletion", id.Hex())
+			return err
+		}
+		saga.DeletedIds = saga.DeletedIds[1:]
+		if err := service.save(saga); err != nil {
+			return err
+		}
+	}
+	saga.Status = domain.HostDeletionRejecting
+	return service.save(saga)
+}
+
+func (service *HostDeletionSagaService) transition(saga *domain.HostDeletionSaga, status domain.HostDeletionStatus, outcome domain.HostDeletionStatus, reason string) error {
+	saga.Status = status
+	saga.Outcome = outcome
+	saga.Reason = reason
+	return service.save(saga)
+}
+
+// save renews the lease with every step, so a long saga is not picked up by another runner halfway.
+func (service *HostDeletionSagaService) save(saga *domain.HostDeletionSaga) error {
+	saga.LeaseExpiresAt = time.Now().Add(service.lease)
+	return service.sagaStore.Update(saga)
+}
+
+func (service *HostDeletionSagaService) publishRejection(saga *domain.HostDeletionSaga, span trace.Span, loki promtail.Client) error {
+	if service.producer == nil {
+		return errors.New("no event producer configured to publish host deletion rejection")
+	}
+	if err := external.PublishHostDeletionRejected(service.producer, saga.HostId, saga.Reason, span, loki); err != nil {
+		return err
+	}
+	saga.Status = saga.Outcome
+	return service.save(saga)
+}
+
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	return external.IsTransient(err) || errors.Is(err, domain.ErrStoreUnavailable)
+}
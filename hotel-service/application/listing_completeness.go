@@ -0,0 +1,24 @@
+package application
+
+import "github.com/ZMS-DevOps/hotel-service/domain"
+
+func PublishProblems(accommodation *domain.Accommodation) []string {
+	var problems []string
+	if len(accommodation.Photos) == 0 {
+		problems = append(problems, "at least one photo is required")
+	}
+	if accommodation.DefaultPrice.Price <= 0 {
+		problems = append(problems, "default price must be set")
+	}
+	if accommodation.GuestNumber.Min < 1 || accommodation.GuestNumber.Max < accommodation.GuestNumber.Min {
+		problems = append(problems, "guest range must be valid")
+	}
+	return problems
+}
+
+func checkPublishable(accommodation *domain.Accommodation) error {
+	if problems := PublishProblems(accommodation); len(problems) > 0 {
+		return &domain.IncompleteListingError{Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package application
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"sort"
+)
+
+func MergePriceCalendar(specialPrices []domain.SpecialPrice, nights []domain.SpecialPrice) []domain.SpecialPrice {
+	merged := append([]domain.SpecialPrice{}, specialPrices...)
+	for _, specialPrice := range coalesceNights(nights) {
+		merged = AddSpecialPrice(merged, specialPrice)
+	}
+	return merged
+}
+
+func DiffSpecialPrices(before, after []domain.SpecialPrice) (added []domain.SpecialPrice, removed []domain.SpecialPrice) {
+	for _, sp := range after {
+		if !containsSpecialPrice(before, sp) {
+			added = append(added, sp)
+		}
+	}
+	for _, sp := range before {
+		if !containsSpecialPrice(after, sp) {
+			removed = append(removed, sp)
+		}
+	}
+	return added, removed
+}
+
+func coalesceNights(nights []domain.SpecialPrice) []domain.SpecialPrice {
+	sorted := append([]domain.SpecialPrice{}, nights...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].DateRange.Start.Before(sorted[j].DateRange.Start)
+	})
+
+	var ranges []domain.SpecialPrice
+	for _, night := range sorted {
+		last := len(ranges) - 1
+		if last >= 0 && ranges[last].Price == night.Price && ranges[last].DateRange.End.Equal(night.DateRange.Start) {
+			ranges[last].DateRange.End = night.DateRange.End
+			continue
+		}
+		ranges = append(ranges, night)
+	}
+	return ranges
+}
+
+func containsSpecialPrice(specialPrices []domain.SpecialPrice, specialPrice domain.SpecialPrice) bool {
+	for _, sp := range specialPrices {
+		if sp.Price == specialPrice.Price &&
+			sp.DateRange.Start.Equal(specialPrice.DateRange.Start) &&
+			sp.DateRange.End.Equal(specialPrice.DateRange.End) {
+			return true
+		}
+	}
+	return false
+}
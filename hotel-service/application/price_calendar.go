@@ -0,0 +1,38 @@
+package application
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"time"
+)
+
+func ResolveNightlyPrices(accommodation *domain.Accommodation, from, to time.Time) []domain.NightlyPrice {
+	var nights []domain.NightlyPrice
+	for date := from; date.Before(to); date = date.AddDate(0, 0, 1) {
+		nights = append(nights, resolveNightlyPrice(accommodation, date))
+	}
+	return nights
+}
+
+func resolveNightlyPrice(accommodation *domain.Accommodation, date time.Time) domain.NightlyPrice {
+	for i := len(accommodation.SpecialPrice) - 1; i >= 0; i-- {
+		specialPrice := accommodation.SpecialPrice[i]
+		if coversNight(specialPrice.DateRange, date) {
+			return domain.NightlyPrice{
+				Date:   date,
+				Price:  specialPrice.Price,
+				Source: domain.SpecialPriceSource,
+				Type:   accommodation.DefaultPrice.Type,
+			}
+		}
+	}
+	return domain.NightlyPrice{
+		Date:   date,
+		Price:  accommodation.DefaultPrice.Price,
+		Source: domain.DefaultPriceSource,
+		Type:   accommodation.DefaultPrice.Type,
+	}
+}
+
+func coversNight(dateRange domain.DateRange, date time.Time) bool {
+	return !date.Before(dateRange.Start) && date.Before(dateRange.End)
+}
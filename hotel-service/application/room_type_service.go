@@ -0,0 +1,169 @@
+package application
+
+import (
+	"context"
+	"github.com/ZMS-DevOps/hotel-service/application/external"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/dto"
+	"github.com/ZMS-DevOps/hotel-service/util"
+	"github.com/afiskon/promtail-client/promtail"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func (service *AccommodationService) GetRoomType(id, roomTypeId primitive.ObjectID, span trace.Span, loki promtail.Client) (*domain.Accommodation, *domain.RoomType, error) {
+	util.HttpTraceInfo("Fetching room type...", span, loki, "GetRoomType", roomTypeId.Hex())
+	accommodation, err := service.store.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	roomType, _ := accommodation.RoomType(roomTypeId)
+	if roomType == nil {
+		return nil, nil, domain.ErrRoomTypeNotFound
+	}
+	return accommodation, roomType, nil
+}
+
+func (service *AccommodationService) AddRoomType(ctx context.Context, id primitive.ObjectID, roomType *domain.RoomType, requestedVersion int64, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	util.HttpTraceInfo("Adding room type...", span, loki, "AddRoomType", id.Hex())
+	accommodation, version, err := service.getVersioned(id, requestedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	roomType.Id = primitive.NewObjectID()
+	if roomType.SpecialPrice == nil {
+		roomType.SpecialPrice = []domain.SpecialPrice{}
+	}
+	// Booking is registered first so a saved room type is always bookable; if the save then fails,
+	// the registration is keyed by an id that was never exposed and stays unused.
+	if !accommodation.PendingBookingRegistration {
+		_, err = external.CreateBookingUnavailability(ctx, service.bookingClient, roomType.Id, accommodation.ReviewReservationRequestAutomatically, accommodation.HostId, accommodation.RoomTypeBookingName(roomType), span, loki)
+		if err != nil {
+			return nil, err
+		}
+	}
+	roomTypes := append(append([]domain.RoomType{}, accommodation.RoomTypes...), *roomType)
+	updatedAccommodation, err := service.updateRoomTypes(ctx, accommodation, roomTypes, version, domain.AuditAddRoomType, actor, span, loki)
+	if err != nil {
+		return nil, err
+	}
+	return updatedAccommodation, service.syncRoomTypesWithSearch(ctx, updatedAccommodation, span, loki)
+}
+
+func (service *AccommodationService) UpdateRoomType(ctx context.Context, id, roomTypeId primitive.ObjectID, roomType *domain.RoomType, requestedVersion int64, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	util.HttpTraceInfo("Updating room type...", span, loki, "UpdateRoomType", roomTypeId.Hex())
+	accommodation, version, err := service.getVersioned(id, requestedVersion)
+	if err != nil {
+		return nil, err
+	}
+	_, index := accommodation.RoomType(roomTypeId)
+	if index < 0 {
+		return nil, domain.ErrRoomTypeNotFound
+	}
+
+	roomType.Id = roomTypeId
+	if roomType.Photos == nil {
+		roomType.Photos = accommodation.RoomTypes[index].Photos
+	}
+	if roomType.SpecialPrice == nil {
+		roomType.SpecialPrice = []domain.SpecialPrice{}
+	}
+	previous := &accommodation.RoomTypes[index]
+	if !accommodation.PendingBookingRegistration {
+		_, err = external.UpdateBookingUnavailability(ctx, service.bookingClient, roomType.Id, accommodation.ReviewReservationRequestAutomatically, accommodation.HostId, accommodation.RoomTypeBookingName(roomType), span, loki)
+		if err != nil {
+			return nil, err
+		}
+	}
+	roomTypes := append([]domain.RoomType{}, accommodation.RoomTypes...)
+	roomTypes[index] = *roomType
+	updatedAccommodation, err := service.updateRoomTypes(ctx, accommodation, roomTypes, version, domain.AuditUpdateRoomType, actor, span, loki)
+	if err != nil {
+		if !accommodation.PendingBookingRegistration {
+			_, rollbackErr := external.UpdateBookingUnavailability(ctx, service.bookingClient, previous.Id, accommodation.ReviewReservationRequestAutomatically, accommodation.HostId, accommodation.RoomTypeBookingName(previous), span, loki)
+			if rollbackErr != nil {
+				util.HttpTraceError(rollbackErr, "failed to roll back booking room type", span, loki, "UpdateRoomType", roomTypeId.Hex())
+			}
+		}
+		return nil, err
+	}
+	return updatedAccommodation, service.syncRoomTypesWithSearch(ctx, updatedAccommodation, span, loki)
+}
+
+func (service *AccommodationService) DeleteRoomType(ctx context.Context, id, roomTypeId primitive.ObjectID, requestedVersion int64, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	util.HttpTraceInfo("Deleting room type...", span, loki, "DeleteRoomType", roomTypeId.Hex())
+	accommodation, version, err := service.getVersioned(id, requestedVersion)
+	if err != nil {
+		return nil, err
+	}
+	_, index := accommodation.RoomType(roomTypeId)
+	if index < 0 {
+		return nil, domain.ErrRoomTypeNotFound
+	}
+
+	if !accommodation.PendingBookingRegistration {
+		canDelete, err := external.CheckAccommodationHasReservation(ctx, service.bookingClient, roomTypeId, span, loki)
+		if err != nil {
+			return nil, err
+		}
+		if !canDelete.Success {
+			return nil, domain.ErrHasReservations
+		}
+	}
+
+	roomTypes := append(append([]domain.RoomType{}, accommodation.RoomTypes[:index]...), accommodation.RoomTypes[index+1:]...)
+	updatedAccommodation, err := service.updateRoomTypes(ctx, accommodation, roomTypes, version, domain.AuditDeleteRoomType, actor, span, loki)
+	if err != nil {
+		return nil, err
+	}
+	return updatedAccommodation, service.syncRoomTypesWithSearch(ctx, updatedAccommodation, span, loki)
+}
+
+func (service *AccommodationService) updateRoomTypes(ctx context.Context, accommodation *domain.Accommodation, roomTypes []domain.RoomType, version int64, action domain.AuditAction, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error) {
+	if err := service.store.UpdateRoomTypes(accommodation.Id, roomTypes, version); err != nil {
+		return nil, err
+	}
+	updatedAccommodation := *accommodation
+	updatedAccommodation.RoomTypes = roomTypes
+	updatedAccommodation.Version = version + 1
+	service.recordAudit(accommodation.Id, actor, action, auditChanges(accommodation, &updatedAccommodation), span, loki)
+	return &updatedAccommodation, nil
+}
+
+func (service *AccommodationService) syncRoomTypesWithSearch(ctx context.Context, accommodation *domain.Accommodation, span trace.Span, loki promtail.Client) error {
+	if accommodation.Status != domain.Published {
+		return nil
+	}
+	_, err := external.EditSearchAccommodation(ctx, service.searchClient, dto.MapToSearchAccommodation(accommodation), span, loki)
+	return err
+}
+
+func (service *AccommodationService) syncRoomTypeBookings(ctx context.Context, accommodation *domain.Accommodation, register bool, span trace.Span, loki promtail.Client) error {
+	for i := range accommodation.RoomTypes {
+		roomType := &accommodation.RoomTypes[i]
+		var err error
+		if register {
+			_, err = external.CreateBookingUnavailability(ctx, service.bookingClient, roomType.Id, accommodation.ReviewReservationRequestAutomatically, accommodation.HostId, accommodation.RoomTypeBookingName(roomType), span, loki)
+		} else {
+			_, err = external.UpdateBookingUnavailability(ctx, service.bookingClient, roomType.Id, accommodation.ReviewReservationRequestAutomatically, accommodation.HostId, accommodation.RoomTypeBookingName(roomType), span, loki)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *AccommodationService) hasReservations(ctx context.Context, accommodation *domain.Accommodation, span trace.Span, loki promtail.Client) (bool, error) {
+	for _, id := range accommodation.BookableUnitIds() {
+		canDelete, err := external.CheckAccommodationHasReservation(ctx, service.bookingClient, id, span, loki)
+		if err != nil {
+			return false, err
+		}
+		if !canDelete.Success {
+			return true, nil
+		}
+	}
+	return false, nil
+}
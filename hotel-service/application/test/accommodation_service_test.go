@@ -1,8 +1,10 @@
 package application_test
 
 import (
+	"context"
 	booking "github.com/ZMS-DevOps/booking-service/proto"
 	application2 "github.com/ZMS-DevOps/hotel-service/application"
+	"github.com/ZMS-DevOps/hotel-service/application/external"
 	"github.com/ZMS-DevOps/hotel-service/application/test"
 	"github.com/ZMS-DevOps/hotel-service/domain"
 	"github.com/ZMS-DevOps/hotel-service/infrastructure/dto"
@@ -11,21 +13,26 @@ import (
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"testing"
+	"time"
 )
 
+var actor = domain.Actor{Id: "aaa92793-08e6-451d-9a40-863d8ead326b", Username: "host"}
+
 func TestAccommodationService_Get(t *testing.T) {
 	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
 	mockBookingClient := new(application.MockBookingServiceClient)
 	mockSearchClient := new(application.MockSearchServiceClient)
 	lokiMock := new(application.LokiMock)
 	spanMock := new(application.SpanMock)
-	service := application2.NewAccommodationService(mockStore, mockBookingClient, mockSearchClient, lokiMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
 
 	accommodationID := primitive.NewObjectID()
 	expectedAccommodation := &domain.Accommodation{Id: accommodationID}
 
 	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
 	mockStore.On("Get", accommodationID).Return(expectedAccommodation, nil)
 
 	result, err := service.Get(accommodationID, spanMock, lokiMock)
@@ -37,11 +44,12 @@ func TestAccommodationService_Get(t *testing.T) {
 
 func TestAccommodationService_GetAll(t *testing.T) {
 	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
 	mockBookingClient := new(application.MockBookingServiceClient)
 	mockSearchClient := new(application.MockSearchServiceClient)
 	lokiMock := new(application.LokiMock)
 	spanMock := new(application.SpanMock)
-	service := application2.NewAccommodationService(mockStore, mockBookingClient, mockSearchClient, lokiMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
 
 	expectedAccommodations := []*domain.Accommodation{
 		{Id: primitive.NewObjectID()},
@@ -49,6 +57,7 @@ func TestAccommodationService_GetAll(t *testing.T) {
 	}
 	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
 	mockStore.On("GetAll").Return(expectedAccommodations, nil)
 
 	result, err := service.GetAll(spanMock, lokiMock)
@@ -60,22 +69,31 @@ func TestAccommodationService_GetAll(t *testing.T) {
 
 func TestAccommodationService_Add(t *testing.T) {
 	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
 	mockBookingClient := new(application.MockBookingServiceClient)
 	mockSearchClient := new(application.MockSearchServiceClient)
 	lokiMock := new(application.LokiMock)
 	spanMock := new(application.SpanMock)
-	service := application2.NewAccommodationService(mockStore, mockBookingClient, mockSearchClient, lokiMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
 
 	accommodationID := primitive.NewObjectID()
-	newAccommodation := &domain.Accommodation{Id: accommodationID, Name: "Test Accommodation"}
+	newAccommodation := &domain.Accommodation{
+		Id:           accommodationID,
+		Name:         "Test Accommodation",
+		Photos:       []string{"uploads/photo.jpg"},
+		GuestNumber:  domain.GuestNumber{Min: 1, Max: 4},
+		DefaultPrice: domain.DefaultPrice{Price: 100},
+		Status:       domain.Published,
+	}
 
 	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
 	mockStore.On("Insert", newAccommodation).Return(nil)
 	mockBookingClient.On("AddUnavailability", mock.Anything, mock.Anything, mock.Anything).Return(&booking.AddUnavailabilityResponse{}, nil)
 	mockSearchClient.On("AddAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.AddAccommodationResponse{}, nil)
 
-	err := service.Add(newAccommodation, spanMock, lokiMock)
+	err := service.Add(context.Background(), newAccommodation, actor, spanMock, lokiMock)
 
 	assert.NoError(t, err)
 	mockStore.AssertExpectations(t)
@@ -84,46 +102,79 @@ func TestAccommodationService_Add(t *testing.T) {
 
 func TestAccommodationService_Update(t *testing.T) {
 	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
 	mockBookingClient := new(application.MockBookingServiceClient)
 	mockSearchClient := new(application.MockSearchServiceClient)
 	lokiMock := new(application.LokiMock)
 	spanMock := new(application.SpanMock)
-	service := application2.NewAccommodationService(mockStore, mockBookingClient, mockSearchClient, lokiMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
 
 	accommodationID := primitive.NewObjectID()
 	updatedAccommodation := &domain.Accommodation{Id: accommodationID, Name: "Updated Accommodation"}
 
 	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
 	mockStore.On("Get", accommodationID).Return(updatedAccommodation, nil)
 	mockStore.On("Update", accommodationID, updatedAccommodation).Return(nil)
 	mockBookingClient.On("EditAccommodation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&booking.EditAccommodationResponse{}, nil)
 	mockSearchClient.On("EditAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.EditAccommodationResponse{}, nil)
 
-	err := service.Update(accommodationID, updatedAccommodation, spanMock, lokiMock)
+	err := service.Update(context.Background(), accommodationID, updatedAccommodation, actor, spanMock, lokiMock)
 
 	assert.NoError(t, err)
 	mockStore.AssertExpectations(t)
 	mockSearchClient.AssertExpectations(t)
 }
 
+func TestAccommodationService_Update_BookingFailureIsReturned(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationID := primitive.NewObjectID()
+	updatedAccommodation := &domain.Accommodation{Id: accommodationID, Name: "Updated Accommodation"}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	lokiMock.On("Errorf", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("RecordError", mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("SetStatus", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationID).Return(updatedAccommodation, nil)
+	mockStore.On("Update", accommodationID, updatedAccommodation).Return(nil)
+	mockBookingClient.On("EditAccommodation", mock.Anything, mock.Anything, mock.Anything).Return((*booking.EditAccommodationResponse)(nil), assert.AnError)
+
+	err := service.Update(context.Background(), accommodationID, updatedAccommodation, actor, spanMock, lokiMock)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	mockSearchClient.AssertNotCalled(t, "EditAccommodation", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestAccommodationService_Delete(t *testing.T) {
 	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
 	mockBookingClient := new(application.MockBookingServiceClient)
 	mockSearchClient := new(application.MockSearchServiceClient)
 	lokiMock := new(application.LokiMock)
 	spanMock := new(application.SpanMock)
-	service := application2.NewAccommodationService(mockStore, mockBookingClient, mockSearchClient, lokiMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
 
 	accommodationID := primitive.NewObjectID()
 
 	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
-	mockStore.On("Delete", accommodationID).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationID).Return(&domain.Accommodation{Id: accommodationID, Status: domain.Published}, nil)
+	mockStore.On("SoftDelete", accommodationID, mock.Anything).Return(nil)
 	mockBookingClient.On("CheckAccommodationHasReservation", mock.Anything, mock.Anything, mock.Anything).Return(&booking.CheckAccommodationHasReservationResponse{Success: true}, nil)
 	mockSearchClient.On("DeleteAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.DeleteAccommodationResponse{}, nil)
 
-	err := service.Delete(accommodationID, spanMock, lokiMock)
+	err := service.Delete(context.Background(), accommodationID, actor, spanMock, lokiMock)
 
 	assert.NoError(t, err)
 	mockStore.AssertExpectations(t)
@@ -132,43 +183,554 @@ func TestAccommodationService_Delete(t *testing.T) {
 
 func TestAccommodationService_Delete_Fail(t *testing.T) {
 	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
 	mockBookingClient := new(application.MockBookingServiceClient)
 	mockSearchClient := new(application.MockSearchServiceClient)
 	lokiMock := new(application.LokiMock)
 	spanMock := new(application.SpanMock)
-	service := application2.NewAccommodationService(mockStore, mockBookingClient, mockSearchClient, lokiMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
 
 	accommodationID := primitive.NewObjectID()
 
 	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
-	mockStore.On("Delete", accommodationID).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationID).Return(&domain.Accommodation{Id: accommodationID}, nil)
+	mockStore.On("SoftDelete", accommodationID, mock.Anything).Return(nil)
 	mockBookingClient.On("CheckAccommodationHasReservation", mock.Anything, mock.Anything, mock.Anything).Return(&booking.CheckAccommodationHasReservationResponse{Success: false}, nil)
 	mockSearchClient.On("DeleteAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.DeleteAccommodationResponse{}, nil)
 
-	err := service.Delete(accommodationID, spanMock, lokiMock)
+	err := service.Delete(context.Background(), accommodationID, actor, spanMock, lokiMock)
+
+	assert.ErrorIs(t, err, domain.ErrHasReservations)
+	mockStore.AssertNotCalled(t, "SoftDelete", accommodationID, mock.Anything)
+}
+
+func TestAccommodationService_Delete_AuditFailureDoesNotFailTheDelete(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationID := primitive.NewObjectID()
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	lokiMock.On("Errorf", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("RecordError", mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("SetStatus", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(assert.AnError)
+	mockStore.On("Get", accommodationID).Return(&domain.Accommodation{Id: accommodationID, Status: domain.Published}, nil)
+	mockStore.On("SoftDelete", accommodationID, mock.Anything).Return(nil)
+	mockBookingClient.On("CheckAccommodationHasReservation", mock.Anything, mock.Anything, mock.Anything).Return(&booking.CheckAccommodationHasReservationResponse{Success: true}, nil)
+	mockSearchClient.On("DeleteAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.DeleteAccommodationResponse{}, nil)
+
+	err := service.Delete(context.Background(), accommodationID, actor, spanMock, lokiMock)
 
-	assert.Error(t, err)
+	assert.NoError(t, err)
+	mockAuditStore.AssertNumberOfCalls(t, "Insert", 1)
+	mockSearchClient.AssertCalled(t, "DeleteAccommodation", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestAccommodationService_UpdateDefaultPrice(t *testing.T) {
 	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
 	mockBookingClient := new(application.MockBookingServiceClient)
 	mockSearchClient := new(application.MockSearchServiceClient)
 	lokiMock := new(application.LokiMock)
 	spanMock := new(application.SpanMock)
-	service := application2.NewAccommodationService(mockStore, mockBookingClient, mockSearchClient, lokiMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
 
 	accommodationID := primitive.NewObjectID()
 
 	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
 	mockStore.On("Get", accommodationID).Return(&domain.Accommodation{}, nil)
-	mockStore.On("UpdateDefaultPrice", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("UpdatePrices", accommodationID, mock.Anything, mock.Anything, int64(0)).Return(nil)
 	mockSearchClient.On("EditAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.EditAccommodationResponse{}, nil)
 
 	price := float32(500)
-	service.UpdatePrice(accommodationID, dto.UpdatePriceDto{Price: &price}, spanMock, lokiMock)
+	paymentType := "PerGuest"
+	service.UpdatePrice(context.Background(), accommodationID, dto.UpdatePriceDto{Price: &price, Type: &paymentType}, actor, spanMock, lokiMock)
+
+	mockStore.AssertNumberOfCalls(t, "UpdatePrices", 1)
+	mockStore.AssertCalled(t, "UpdatePrices", accommodationID, domain.DefaultPrice{Price: price, Type: domain.PerGuest}, []domain.SpecialPrice(nil), int64(0))
+}
+
+func TestAccommodationService_UploadPriceCalendar_DryRun(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationID := primitive.NewObjectID()
+	start := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	currentSpecialPrices := []domain.SpecialPrice{
+		{Price: 100, DateRange: domain.DateRange{Start: start, End: start.AddDate(0, 0, 5)}},
+	}
+	nights := []domain.SpecialPrice{
+		{Price: 150, DateRange: domain.DateRange{Start: start.AddDate(0, 0, 2), End: start.AddDate(0, 0, 3)}},
+		{Price: 150, DateRange: domain.DateRange{Start: start.AddDate(0, 0, 1), End: start.AddDate(0, 0, 2)}},
+	}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationID).Return(&domain.Accommodation{Id: accommodationID, SpecialPrice: currentSpecialPrices}, nil)
+
+	result, err := service.UploadPriceCalendar(context.Background(), accommodationID, nights, true, 0, actor, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Len(t, result.Removed, 1)
+	assert.Len(t, result.Added, 3)
+	assert.Len(t, result.SpecialPrice, 3)
+	mockStore.AssertNotCalled(t, "UpdateSpecialPrice", mock.Anything, mock.Anything, mock.Anything)
+	mockSearchClient.AssertNotCalled(t, "EditAccommodation", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccommodationService_GetPriceCalendar(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationID := primitive.NewObjectID()
+	start := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	accommodation := &domain.Accommodation{
+		Id:           accommodationID,
+		DefaultPrice: domain.DefaultPrice{Price: 100, Type: domain.PerGuest},
+		SpecialPrice: []domain.SpecialPrice{
+			{Price: 150, DateRange: domain.DateRange{Start: start.AddDate(0, 0, 1), End: start.AddDate(0, 0, 2)}},
+		},
+	}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationID).Return(accommodation, nil)
+
+	_, result, err := service.GetPriceCalendar(accommodationID, &dto.CalendarRequestDto{From: start, To: start.AddDate(0, 0, 3)}, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Nights, 3)
+	assert.Equal(t, dto.NightlyPriceDto{Date: "2024-05-10", Price: 100, Source: "default", PricingType: "PerGuest"}, result.Nights[0])
+	assert.Equal(t, dto.NightlyPriceDto{Date: "2024-05-11", Price: 150, Source: "special", PricingType: "PerGuest"}, result.Nights[1])
+	assert.Equal(t, "default", result.Nights[2].Source)
+}
+
+func TestMapCalendarResponse_NamesEveryPriceSource(t *testing.T) {
+	start := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	nights := []domain.NightlyPrice{
+		{Date: start, Source: domain.DefaultPriceSource},
+		{Date: start.AddDate(0, 0, 1), Source: domain.SpecialPriceSource},
+		{Date: start.AddDate(0, 0, 2), Source: domain.RulePriceSource},
+	}
+
+	response := dto.MapCalendarResponse(&domain.Accommodation{}, &dto.CalendarRequestDto{From: start, To: start.AddDate(0, 0, 3)}, nights)
+
+	sources := []string{}
+	for _, night := range response.Nights {
+		sources = append(sources, night.Source)
+	}
+	assert.Equal(t, []string{"default", "special", "rule"}, sources)
+}
+
+func TestAccommodationService_Update_VersionConflict(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationID := primitive.NewObjectID()
+	storedAccommodation := &domain.Accommodation{Id: accommodationID, Name: "Accommodation", Version: 3}
+	updatedAccommodation := &domain.Accommodation{Id: accommodationID, Name: "Updated Accommodation", Version: 2}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationID).Return(storedAccommodation, nil)
+
+	err := service.Update(context.Background(), accommodationID, updatedAccommodation, actor, spanMock, lokiMock)
+
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+	mockStore.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockSearchClient.AssertNotCalled(t, "EditAccommodation", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccommodationService_Patch(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationID := primitive.NewObjectID()
+	storedAccommodation := &domain.Accommodation{Id: accommodationID, Name: "Accommodation", Location: "Bali", Version: 2}
+	patchedAccommodation := &domain.Accommodation{Id: accommodationID, Name: "Accommodation", Location: "Hawaii", Version: 2}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationID).Return(storedAccommodation, nil)
+	mockStore.On("Update", accommodationID, mock.MatchedBy(func(accommodation *domain.Accommodation) bool {
+		return accommodation.Location == "Hawaii" && accommodation.Version == 2
+	})).Return(nil)
+	mockSearchClient.On("EditAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.EditAccommodationResponse{}, nil)
+
+	result, err := service.Patch(context.Background(), accommodationID, patchedAccommodation, actor, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Hawaii", result.Location)
+	assert.Equal(t, int64(3), result.Version)
+	mockStore.AssertExpectations(t)
+	mockSearchClient.AssertExpectations(t)
+	mockBookingClient.AssertNotCalled(t, "EditAccommodation", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccommodationService_Restore_RetentionExpired(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationID := primitive.NewObjectID()
+	deletedAt := time.Now().Add(-48 * time.Hour)
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockStore.On("GetDeleted", accommodationID).Return(&domain.Accommodation{Id: accommodationID, DeletedAt: &deletedAt}, nil)
+
+	_, err := service.Restore(context.Background(), accommodationID, 24*time.Hour, actor, spanMock, lokiMock)
+
+	assert.ErrorIs(t, err, domain.ErrRetentionExpired)
+	mockStore.AssertNotCalled(t, "Restore", mock.Anything)
+}
+
+func TestHostDeletionSagaService_Start_RejectedWhenReserved(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	mockSagaStore := new(application.MockHostDeletionSagaStore)
+	mockProducer := new(application.MockEventProducer)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+	sagaService := application2.NewHostDeletionSagaService(mockSagaStore, service, mockBookingClient, mockProducer, time.Minute, lokiMock)
+
+	hostId := "host-1"
+	accommodations := []*domain.Accommodation{{Id: primitive.NewObjectID()}, {Id: primitive.NewObjectID()}}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockSagaStore.On("GetUnfinishedByHostId", hostId).Return(nil, domain.ErrNotFound)
+	mockStore.On("GetByHostId", hostId).Return(accommodations, nil)
+	mockSagaStore.On("Insert", mock.Anything).Return(nil)
+	mockSagaStore.On("Update", mock.Anything).Return(nil)
+	mockSagaStore.On("Lease", mock.Anything, mock.Anything).Return(nil)
+	mockBookingClient.On("CheckAccommodationHasReservation", mock.Anything, mock.MatchedBy(func(request *booking.CheckAccommodationHasReservationRequest) bool {
+		return request.AccommodationId == accommodations[0].Id.Hex()
+	}), mock.Anything).Return(&booking.CheckAccommodationHasReservationResponse{Success: true}, nil)
+	mockBookingClient.On("CheckAccommodationHasReservation", mock.Anything, mock.Anything, mock.Anything).Return(&booking.CheckAccommodationHasReservationResponse{Success: false}, nil)
+	mockProducer.On("Produce", mock.Anything, mock.Anything).Return(nil)
+
+	saga, err := sagaService.Start(context.Background(), hostId, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.HostDeletionRejected, saga.Status)
+	mockStore.AssertNotCalled(t, "SoftDelete", mock.Anything, mock.Anything)
+	mockProducer.AssertExpectations(t)
+}
+
+func TestHostDeletionSagaService_Start_CompensatesOnFailure(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	mockSagaStore := new(application.MockHostDeletionSagaStore)
+	mockProducer := new(application.MockEventProducer)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+	sagaService := application2.NewHostDeletionSagaService(mockSagaStore, service, mockBookingClient, mockProducer, time.Minute, lokiMock)
+
+	hostId := "host-1"
+	first := &domain.Accommodation{Id: primitive.NewObjectID(), HostId: hostId}
+	second := &domain.Accommodation{Id: primitive.NewObjectID(), HostId: hostId}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	lokiMock.On("Errorf", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	spanMock.On("RecordError", mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("SetStatus", mock.Anything, mock.Anything).Return(nil)
+	mockSagaStore.On("GetUnfinishedByHostId", hostId).Return(nil, domain.ErrNotFound)
+	mockStore.On("GetByHostId", hostId).Return([]*domain.Accommodation{first, second}, nil)
+	mockSagaStore.On("Insert", mock.Anything).Return(nil)
+	mockSagaStore.On("Update", mock.Anything).Return(nil)
+	mockSagaStore.On("Lease", mock.Anything, mock.Anything).Return(nil)
+	mockBookingClient.On("CheckAccommodationHasReservation", mock.Anything, mock.Anything, mock.Anything).Return(&booking.CheckAccommodationHasReservationResponse{Success: true}, nil)
+	mockStore.On("SoftDelete", first.Id, mock.Anything).Return(nil)
+	mockStore.On("SoftDelete", second.Id, mock.Anything).Return(assert.AnError)
+	mockSearchClient.On("DeleteAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.DeleteAccommodationResponse{}, nil)
+	mockStore.On("Restore", first.Id).Return(nil)
+	mockStore.On("Restore", second.Id).Return(domain.ErrNotFound)
+	mockStore.On("Get", first.Id).Return(first, nil)
+	mockStore.On("Get", second.Id).Return(second, nil)
+	mockBookingClient.On("EditAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&booking.EditAccommodationResponse{}, nil)
+	mockSearchClient.On("AddAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.AddAccommodationResponse{}, nil)
+	mockProducer.On("Produce", mock.Anything, mock.Anything).Return(nil)
+
+	saga, err := sagaService.Start(context.Background(), hostId, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.HostDeletionCompensated, saga.Status)
+	assert.Empty(t, saga.DeletedIds)
+	mockStore.AssertCalled(t, "Restore", first.Id)
+	mockStore.AssertNotCalled(t, "Restore", second.Id)
+	mockBookingClient.AssertCalled(t, "EditAccommodation", mock.Anything, mock.Anything, mock.Anything)
+	mockSearchClient.AssertCalled(t, "AddAccommodation", mock.Anything, mock.Anything, mock.Anything)
+	mockProducer.AssertExpectations(t)
+}
+
+func TestAccommodationService_Update_RecordsAudit(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationID := primitive.NewObjectID()
+	currentAccommodation := &domain.Accommodation{Id: accommodationID, Name: "Accommodation", Location: "Bali"}
+	updatedAccommodation := &domain.Accommodation{Id: accommodationID, Name: "Updated Accommodation", Location: "Bali"}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.MatchedBy(func(audit *domain.AccommodationAudit) bool {
+		return audit.Action == domain.AuditUpdate &&
+			audit.Actor == actor &&
+			len(audit.Changes) == 1 &&
+			audit.Changes[0] == domain.FieldChange{Field: "name", Before: "Accommodation", After: "Updated Accommodation"}
+	})).Return(nil)
+	mockStore.On("Get", accommodationID).Return(currentAccommodation, nil)
+	mockStore.On("Update", accommodationID, updatedAccommodation).Return(nil)
+	mockBookingClient.On("EditAccommodation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&booking.EditAccommodationResponse{}, nil)
+	mockSearchClient.On("EditAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.EditAccommodationResponse{}, nil)
+
+	err := service.Update(context.Background(), accommodationID, updatedAccommodation, actor, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	mockAuditStore.AssertExpectations(t)
+}
+
+func TestAccommodationService_Add_DraftIsNotSynced(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	newAccommodation := &domain.Accommodation{Name: "Half finished", Status: domain.Draft}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockStore.On("Insert", newAccommodation).Return(nil)
+
+	err := service.Add(context.Background(), newAccommodation, actor, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	assert.True(t, newAccommodation.PendingBookingRegistration)
+	mockBookingClient.AssertNotCalled(t, "AddUnavailability", mock.Anything, mock.Anything, mock.Anything)
+	mockSearchClient.AssertNotCalled(t, "AddAccommodation", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccommodationService_Publish_Incomplete(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationID := primitive.NewObjectID()
+	draft := &domain.Accommodation{Id: accommodationID, Status: domain.Draft, Version: 1, GuestNumber: domain.GuestNumber{Min: 3, Max: 2}}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationID).Return(draft, nil)
+
+	_, err := service.Publish(context.Background(), accommodationID, 0, actor, spanMock, lokiMock)
+
+	var incompleteListing *domain.IncompleteListingError
+	assert.ErrorAs(t, err, &incompleteListing)
+	assert.Len(t, incompleteListing.Problems, 3)
+	mockStore.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccommodationService_Publish_RegistersDraftWithBooking(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationID := primitive.NewObjectID()
+	draft := &domain.Accommodation{
+		Id:                         accommodationID,
+		Photos:                     []string{"uploads/photo.jpg"},
+		GuestNumber:                domain.GuestNumber{Min: 1, Max: 2},
+		DefaultPrice:               domain.DefaultPrice{Price: 80},
+		Status:                     domain.Draft,
+		PendingBookingRegistration: true,
+		Version:                    2,
+	}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationID).Return(draft, nil)
+	mockStore.On("UpdateStatus", accommodationID, domain.Published, false, int64(2)).Return(nil)
+	mockBookingClient.On("AddUnavailability", mock.Anything, mock.Anything, mock.Anything).Return(&booking.AddUnavailabilityResponse{}, nil)
+	mockSearchClient.On("AddAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.AddAccommodationResponse{}, nil)
+
+	published, err := service.Publish(context.Background(), accommodationID, 2, actor, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Published, published.Status)
+	assert.Equal(t, int64(3), published.Version)
+	mockStore.AssertExpectations(t)
+	mockBookingClient.AssertExpectations(t)
+	mockSearchClient.AssertExpectations(t)
+}
+
+func TestAccommodationService_Search(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	query := &domain.AccommodationSearchQuery{Text: "pool bali", HostId: "host-1", Limit: 20}
+	accommodation := &domain.Accommodation{
+		Id:       primitive.NewObjectID(),
+		HostId:   "host-1",
+		Name:     "Villa Sunset",
+		Location: "Ubud, Bali",
+		Benefits: []string{"Swimming pools", "TV"},
+	}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("Search", *query).Return([]*domain.AccommodationSearchHit{{Accommodation: accommodation, Score: 2.5}}, int64(7), nil)
+
+	result, err := service.Search(query, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), result.Total)
+	assert.Len(t, result.Results, 1)
+	assert.Equal(t, map[string][]string{
+		"location": {"Ubud, <em>Bali</em>"},
+		"benefits": {"Swimming <em>pools</em>"},
+	}, result.Results[0].Highlights)
+}
+
+func TestAccommodationService_Add_GeocodesAddress(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	mockGeocoder := new(application.MockGeocoder)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, mockGeocoder, lokiMock)
+
+	address := &domain.Address{City: "Novi Sad", CountryCode: "RS"}
+	newAccommodation := &domain.Accommodation{Name: "Riverside", Address: address, Status: domain.Draft}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockGeocoder.On("Geocode", address).Return(domain.NewGeoPoint(45.2671, 19.8335), nil)
+	mockStore.On("Insert", newAccommodation).Return(nil)
+
+	err := service.Add(context.Background(), newAccommodation, actor, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 45.2671, newAccommodation.GeoLocation.Latitude())
+	assert.Equal(t, 19.8335, newAccommodation.GeoLocation.Longitude())
+	assert.False(t, newAccommodation.GeocodingNeedsReview)
+}
+
+func TestAccommodationService_Add_GeocodingFailureFlagsForReview(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	mockGeocoder := new(application.MockGeocoder)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, mockGeocoder, lokiMock)
+
+	address := &domain.Address{City: "Atlantis", CountryCode: "GR"}
+	newAccommodation := &domain.Accommodation{Name: "Lost city", Address: address, Status: domain.Draft}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockGeocoder.On("Geocode", address).Return(nil, domain.ErrAddressNotFound)
+	mockStore.On("Insert", newAccommodation).Return(nil)
+
+	err := service.Add(context.Background(), newAccommodation, actor, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	assert.Nil(t, newAccommodation.GeoLocation)
+	assert.True(t, newAccommodation.GeocodingNeedsReview)
+	mockStore.AssertCalled(t, "Insert", newAccommodation)
+}
+
+func TestOfflineGeocoder_Geocode(t *testing.T) {
+	geocoder, err := external.NewOfflineGeocoder()
+	assert.NoError(t, err)
+
+	point, err := geocoder.Geocode(&domain.Address{City: "belgrade", CountryCode: "rs"})
+	assert.NoError(t, err)
+	assert.Equal(t, 44.7866, point.Latitude())
+
+	point, err = geocoder.Geocode(&domain.Address{City: "Seminyak", Region: "Bali", CountryCode: "ID"})
+	assert.NoError(t, err)
+	assert.NotNil(t, point)
 
-	mockStore.AssertCalled(t, "UpdateDefaultPrice", mock.Anything, mock.Anything, mock.Anything)
+	_, err = geocoder.Geocode(&domain.Address{City: "Nowhere", CountryCode: "RS"})
+	assert.ErrorIs(t, err, domain.ErrAddressNotFound)
 }
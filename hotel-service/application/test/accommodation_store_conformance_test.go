@@ -0,0 +1,453 @@
+package application_test
+
+import (
+	"context"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+const testDBUriEnv = "HOTEL_TEST_DB_URI"
+
+func TestAccommodationMemoryStore_Conformance(t *testing.T) {
+	runAccommodationStoreConformance(t, func(t *testing.T) domain.AccommodationStore {
+		return persistence.NewAccommodationMemoryStore()
+	})
+}
+
+func TestAccommodationMongoDBStore_Conformance(t *testing.T) {
+	client := testMongoClient(t)
+
+	runAccommodationStoreConformance(t, func(t *testing.T) domain.AccommodationStore {
+		return newTestMongoStore(t, client)
+	})
+}
+
+// Ranking depends on the weights of the Mongo text index, which the memory store does not reproduce.
+func TestAccommodationMongoDBStore_SearchRanksNameAboveBenefits(t *testing.T) {
+	store := newTestMongoStore(t, testMongoClient(t))
+	byName := conformanceAccommodation("host-1", "Pool House")
+	byBenefit := conformanceAccommodation("host-1", "City Loft")
+	byBenefit.Benefits = []string{"pool"}
+	for _, accommodation := range []*domain.Accommodation{byBenefit, byName} {
+		require.NoError(t, store.Insert(accommodation))
+	}
+
+	hits, _, err := store.Search(domain.AccommodationSearchQuery{Text: "pool", Limit: 10})
+
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+	assert.Equal(t, byName.Id, hits[0].Accommodation.Id)
+	assert.Equal(t, byBenefit.Id, hits[1].Accommodation.Id)
+	assert.Greater(t, hits[0].Score, hits[1].Score)
+}
+
+func testMongoClient(t *testing.T) *mongo.Client {
+	uri := os.Getenv(testDBUriEnv)
+	if uri == "" {
+		t.Skipf("%s not set; start the local container with `docker compose --profile test up -d hotel_test_db` and set %s=mongodb://localhost:27018", testDBUriEnv, testDBUriEnv)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	require.NoError(t, client.Ping(ctx, nil))
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+	return client
+}
+
+func newTestMongoStore(t *testing.T, client *mongo.Client) domain.AccommodationStore {
+	store := persistence.NewAccommodationMongoDBStore(client)
+	store.DeleteAll()
+	t.Cleanup(store.DeleteAll)
+	return store
+}
+
+func runAccommodationStoreConformance(t *testing.T, newStore func(t *testing.T) domain.AccommodationStore) {
+	t.Run("InsertAndGet", func(t *testing.T) {
+		store := newStore(t)
+		accommodation := conformanceAccommodation("host-1", "Sea View Apartment")
+
+		require.NoError(t, store.Insert(accommodation))
+
+		assert.False(t, accommodation.Id.IsZero())
+		assert.Equal(t, int64(1), accommodation.Version)
+		result, err := store.Get(accommodation.Id)
+		require.NoError(t, err)
+		assert.Equal(t, accommodation, result)
+	})
+
+	t.Run("GetNotFound", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.Get(primitive.NewObjectID())
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("InsertWithIdKeepsIdAndRejectsDuplicates", func(t *testing.T) {
+		store := newStore(t)
+		accommodation := conformanceAccommodation("host-1", "Mountain Lodge")
+		accommodation.Id = primitive.NewObjectID()
+
+		require.NoError(t, store.InsertWithId(accommodation))
+		err := store.InsertWithId(accommodation)
+
+		assert.Equal(t, int64(1), accommodation.Version)
+		assert.ErrorIs(t, err, domain.ErrDuplicateId)
+	})
+
+	t.Run("ReturnedAccommodationsAreCopies", func(t *testing.T) {
+		store := newStore(t)
+		accommodation := conformanceAccommodation("host-1", "Sea View Apartment")
+		require.NoError(t, store.Insert(accommodation))
+
+		result, err := store.Get(accommodation.Id)
+		require.NoError(t, err)
+		result.Name = "Changed"
+		result.Benefits[0] = "changed"
+		accommodation.Name = "Changed too"
+
+		stored, err := store.Get(accommodation.Id)
+		require.NoError(t, err)
+		assert.Equal(t, "Sea View Apartment", stored.Name)
+		assert.Equal(t, "wifi", stored.Benefits[0])
+	})
+
+	t.Run("GetAllAndGetByHostIdSkipSoftDeleted", func(t *testing.T) {
+		store := newStore(t)
+		first := conformanceAccommodation("host-1", "First")
+		second := conformanceAccommodation("host-1", "Second")
+		other := conformanceAccommodation("host-2", "Other")
+		for _, accommodation := range []*domain.Accommodation{first, second, other} {
+			require.NoError(t, store.Insert(accommodation))
+		}
+		require.NoError(t, store.SoftDelete(second.Id, time.Now()))
+
+		all, err := store.GetAll()
+		require.NoError(t, err)
+		byHost, err := store.GetByHostId("host-1")
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []primitive.ObjectID{first.Id, other.Id}, conformanceIds(all))
+		assert.Equal(t, []primitive.ObjectID{first.Id}, conformanceIds(byHost))
+	})
+
+	t.Run("SoftDeleteAndRestore", func(t *testing.T) {
+		store := newStore(t)
+		accommodation := conformanceAccommodation("host-1", "Sea View Apartment")
+		require.NoError(t, store.Insert(accommodation))
+		deletedAt := time.Now().UTC().Truncate(time.Millisecond)
+
+		require.NoError(t, store.SoftDelete(accommodation.Id, deletedAt))
+		assert.ErrorIs(t, store.SoftDelete(accommodation.Id, deletedAt), domain.ErrNotFound)
+		_, err := store.Get(accommodation.Id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		deleted, err := store.GetDeleted(accommodation.Id)
+		require.NoError(t, err)
+		assert.Equal(t, deletedAt, *deleted.DeletedAt)
+		assert.Equal(t, int64(2), deleted.Version)
+
+		require.NoError(t, store.Restore(accommodation.Id))
+		assert.ErrorIs(t, store.Restore(accommodation.Id), domain.ErrNotFound)
+		restored, err := store.Get(accommodation.Id)
+		require.NoError(t, err)
+		assert.Nil(t, restored.DeletedAt)
+		assert.Equal(t, int64(3), restored.Version)
+		_, err = store.GetDeleted(accommodation.Id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("GetDeletedBefore", func(t *testing.T) {
+		store := newStore(t)
+		old := conformanceAccommodation("host-1", "Old")
+		recent := conformanceAccommodation("host-1", "Recent")
+		live := conformanceAccommodation("host-1", "Live")
+		for _, accommodation := range []*domain.Accommodation{old, recent, live} {
+			require.NoError(t, store.Insert(accommodation))
+		}
+		now := time.Now()
+		require.NoError(t, store.SoftDelete(old.Id, now.Add(-48*time.Hour)))
+		require.NoError(t, store.SoftDelete(recent.Id, now))
+
+		result, err := store.GetDeletedBefore(now.Add(-24 * time.Hour))
+
+		require.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{old.Id}, conformanceIds(result))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		store := newStore(t)
+		first := conformanceAccommodation("host-1", "First")
+		second := conformanceAccommodation("host-1", "Second")
+		other := conformanceAccommodation("host-2", "Other")
+		for _, accommodation := range []*domain.Accommodation{first, second, other} {
+			require.NoError(t, store.Insert(accommodation))
+		}
+		require.NoError(t, store.SoftDelete(second.Id, time.Now()))
+
+		for _, id := range []primitive.ObjectID{first.Id, second.Id, other.Id, primitive.NewObjectID()} {
+			require.NoError(t, store.Delete(id))
+		}
+
+		_, err := store.Get(other.Id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = store.GetDeleted(second.Id)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		all, err := store.GetAll()
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("UpdateChecksVersion", func(t *testing.T) {
+		store := newStore(t)
+		accommodation := conformanceAccommodation("host-1", "Sea View Apartment")
+		require.NoError(t, store.Insert(accommodation))
+		accommodation.Name = "Renamed"
+		accommodation.Benefits = []string{"parking"}
+		accommodation.Status = domain.Suspended
+
+		require.NoError(t, store.Update(accommodation.Id, accommodation))
+		assert.ErrorIs(t, store.Update(accommodation.Id, accommodation), domain.ErrVersionConflict)
+		assert.ErrorIs(t, store.Update(primitive.NewObjectID(), accommodation), domain.ErrNotFound)
+
+		result, err := store.Get(accommodation.Id)
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", result.Name)
+		assert.Equal(t, []string{"parking"}, result.Benefits)
+		assert.Equal(t, domain.Published, result.Status)
+		assert.Equal(t, int64(2), result.Version)
+	})
+
+	t.Run("UpdateRejectsSoftDeleted", func(t *testing.T) {
+		store := newStore(t)
+		accommodation := conformanceAccommodation("host-1", "Sea View Apartment")
+		require.NoError(t, store.Insert(accommodation))
+		require.NoError(t, store.SoftDelete(accommodation.Id, time.Now()))
+
+		err := store.UpdateStatus(accommodation.Id, domain.Draft, false, 2)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("UpdateStatus", func(t *testing.T) {
+		store := newStore(t)
+		accommodation := conformanceAccommodation("host-1", "Sea View Apartment")
+		accommodation.Status = domain.Draft
+		accommodation.PendingBookingRegistration = true
+		require.NoError(t, store.Insert(accommodation))
+
+		require.NoError(t, store.UpdateStatus(accommodation.Id, domain.Published, false, 1))
+
+		result, err := store.Get(accommodation.Id)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Published, result.Status)
+		assert.False(t, result.PendingBookingRegistration)
+		assert.Equal(t, "Sea View Apartment", result.Name)
+		assert.Equal(t, int64(2), result.Version)
+		assert.ErrorIs(t, store.UpdateStatus(accommodation.Id, domain.Draft, false, 1), domain.ErrVersionConflict)
+	})
+
+	t.Run("UpdatePrices", func(t *testing.T) {
+		store := newStore(t)
+		accommodation := conformanceAccommodation("host-1", "Sea View Apartment")
+		require.NoError(t, store.Insert(accommodation))
+		start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+		defaultPrice := domain.DefaultPrice{Price: 150, Type: domain.PerGuest}
+		specialPrices := []domain.SpecialPrice{
+			{Price: 200, DateRange: domain.DateRange{Start: start, End: start.AddDate(0, 0, 7)}},
+		}
+
+		require.NoError(t, store.UpdatePrices(accommodation.Id, defaultPrice, specialPrices, 1))
+		assert.ErrorIs(t, store.UpdatePrices(accommodation.Id, defaultPrice, specialPrices, 1), domain.ErrVersionConflict)
+
+		result, err := store.Get(accommodation.Id)
+		require.NoError(t, err)
+		assert.Equal(t, defaultPrice, result.DefaultPrice)
+		assert.Equal(t, specialPrices, result.SpecialPrice)
+		assert.Equal(t, int64(2), result.Version)
+	})
+
+	t.Run("UpdateRoomTypes", func(t *testing.T) {
+		store := newStore(t)
+		accommodation := conformanceAccommodation("host-1", "Grand Hotel")
+		require.NoError(t, store.Insert(accommodation))
+		roomTypes := []domain.RoomType{{
+			Id:           primitive.NewObjectID(),
+			Name:         "Family suite",
+			Photos:       []string{},
+			Capacity:     domain.GuestNumber{Min: 2, Max: 5},
+			DefaultPrice: domain.DefaultPrice{Price: 180, Type: domain.PerApartmentUnit},
+			SpecialPrice: []domain.SpecialPrice{},
+		}}
+
+		require.NoError(t, store.UpdateRoomTypes(accommodation.Id, roomTypes, 1))
+		roomTypes[0].Name = "Changed by the caller"
+
+		result, err := store.Get(accommodation.Id)
+		require.NoError(t, err)
+		require.Len(t, result.RoomTypes, 1)
+		assert.Equal(t, "Family suite", result.RoomTypes[0].Name)
+		assert.Equal(t, int64(2), result.Version)
+		assert.ErrorIs(t, store.UpdateRoomTypes(primitive.NewObjectID(), roomTypes, 1), domain.ErrNotFound)
+	})
+
+	t.Run("SpecialPrices", func(t *testing.T) {
+		store := newStore(t)
+		accommodation := conformanceAccommodation("host-1", "Sea View Apartment")
+		require.NoError(t, store.Insert(accommodation))
+		start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+		specialPrices := []domain.SpecialPrice{
+			{Price: 200, DateRange: domain.DateRange{Start: start, End: start.AddDate(0, 0, 7)}},
+		}
+
+		require.NoError(t, store.UpdateSpecialPrice(accommodation.Id, specialPrices, 1))
+		assert.ErrorIs(t, store.UpdateSpecialPrice(accommodation.Id, specialPrices, 1), domain.ErrVersionConflict)
+		assert.ErrorIs(t, store.UpdateSpecialPrice(primitive.NewObjectID(), specialPrices, 1), domain.ErrNotFound)
+
+		result, err := store.Get(accommodation.Id)
+		require.NoError(t, err)
+		assert.Equal(t, specialPrices, result.SpecialPrice)
+	})
+
+	t.Run("CountWithAmenity", func(t *testing.T) {
+		store := newStore(t)
+		withWifi := conformanceAccommodation("host-1", "First")
+		deletedWithWifi := conformanceAccommodation("host-1", "Second")
+		withoutWifi := conformanceAccommodation("host-1", "Third")
+		withoutWifi.Benefits = []string{"parking"}
+		for _, accommodation := range []*domain.Accommodation{withWifi, deletedWithWifi, withoutWifi} {
+			require.NoError(t, store.Insert(accommodation))
+		}
+		require.NoError(t, store.SoftDelete(deletedWithWifi.Id, time.Now()))
+
+		count, err := store.CountWithAmenity("wifi")
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("SearchMatchesNameLocationAndBenefits", func(t *testing.T) {
+		store := newStore(t)
+		byName := conformanceAccommodation("host-1", "Pool House")
+		byBenefit := conformanceAccommodation("host-1", "City Loft")
+		byBenefit.Benefits = []string{"pool"}
+		otherHost := conformanceAccommodation("host-2", "Pool Villa")
+		unrelated := conformanceAccommodation("host-1", "Mountain Lodge")
+		for _, accommodation := range []*domain.Accommodation{byBenefit, byName, otherHost, unrelated} {
+			require.NoError(t, store.Insert(accommodation))
+		}
+
+		hits, total, err := store.Search(domain.AccommodationSearchQuery{Text: "pool", HostId: "host-1", Limit: 10})
+		require.NoError(t, err)
+		limited, limitedTotal, err := store.Search(domain.AccommodationSearchQuery{Text: "pool", Limit: 1})
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []primitive.ObjectID{byName.Id, byBenefit.Id}, searchHitIds(hits))
+		assert.Equal(t, int64(2), total)
+		assert.Len(t, limited, 1)
+		assert.Equal(t, int64(3), limitedTotal)
+	})
+
+	t.Run("SearchPagesWithOffset", func(t *testing.T) {
+		store := newStore(t)
+		for _, name := range []string{"Pool House", "Pool Villa", "Pool Loft"} {
+			require.NoError(t, store.Insert(conformanceAccommodation("host-1", name)))
+		}
+
+		first, total, err := store.Search(domain.AccommodationSearchQuery{Text: "pool", Limit: 2})
+		require.NoError(t, err)
+		second, _, err := store.Search(domain.AccommodationSearchQuery{Text: "pool", Offset: 2, Limit: 2})
+		require.NoError(t, err)
+		past, pastTotal, err := store.Search(domain.AccommodationSearchQuery{Text: "pool", Offset: 3, Limit: 2})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, first, 2)
+		require.Len(t, second, 1)
+		assert.NotContains(t, searchHitIds(first), second[0].Accommodation.Id)
+		assert.Empty(t, past)
+		assert.Equal(t, int64(3), pastTotal)
+	})
+
+	t.Run("SearchMatchesDescriptionAndTranslations", func(t *testing.T) {
+		store := newStore(t)
+		byDescription := conformanceAccommodation("host-1", "City Loft")
+		byDescription.Description = "Quiet garden terrace"
+		byTranslation := conformanceAccommodation("host-1", "River House")
+		require.NoError(t, store.Insert(byDescription))
+		require.NoError(t, store.Insert(byTranslation))
+		byTranslation.Translations = map[string]domain.Translation{"sr": {Name: "Kuca na reci", Description: "Terasa uz reku"}}
+		require.NoError(t, store.Update(byTranslation.Id, byTranslation))
+
+		described, _, err := store.Search(domain.AccommodationSearchQuery{Text: "garden", Limit: 10})
+		require.NoError(t, err)
+		translated, _, err := store.Search(domain.AccommodationSearchQuery{Text: "terasa", Limit: 10})
+		require.NoError(t, err)
+
+		assert.Equal(t, []primitive.ObjectID{byDescription.Id}, searchHitIds(described))
+		assert.Equal(t, []primitive.ObjectID{byTranslation.Id}, searchHitIds(translated))
+	})
+
+	t.Run("NearbyOrdersByDistance", func(t *testing.T) {
+		store := newStore(t)
+		near := conformanceAccommodation("host-1", "Near")
+		near.GeoLocation = domain.NewGeoPoint(45.2551, 19.8452)
+		far := conformanceAccommodation("host-1", "Far")
+		far.GeoLocation = domain.NewGeoPoint(45.2671, 19.8335)
+		outside := conformanceAccommodation("host-1", "Outside")
+		outside.GeoLocation = domain.NewGeoPoint(44.7866, 20.4489)
+		draft := conformanceAccommodation("host-1", "Draft")
+		draft.GeoLocation = domain.NewGeoPoint(45.2551, 19.8452)
+		draft.Status = domain.Draft
+		for _, accommodation := range []*domain.Accommodation{far, outside, near, draft} {
+			require.NoError(t, store.Insert(accommodation))
+		}
+
+		hits, err := store.Nearby(domain.NearbyQuery{Latitude: 45.2550, Longitude: 19.8450, RadiusMeters: 5000, Limit: 10})
+
+		require.NoError(t, err)
+		require.Len(t, hits, 2)
+		assert.Equal(t, near.Id, hits[0].Accommodation.Id)
+		assert.Equal(t, far.Id, hits[1].Accommodation.Id)
+		assert.InDelta(t, 18, hits[0].DistanceMeters, 5)
+		assert.InDelta(t, 1620, hits[1].DistanceMeters, 50)
+	})
+}
+
+func conformanceAccommodation(hostId, name string) *domain.Accommodation {
+	return &domain.Accommodation{
+		HostId:       hostId,
+		Name:         name,
+		Location:     "Novi Sad",
+		Benefits:     []string{"wifi"},
+		Photos:       []string{},
+		GuestNumber:  domain.GuestNumber{Min: 1, Max: 4},
+		DefaultPrice: domain.DefaultPrice{Price: 100, Type: domain.PerApartmentUnit},
+		SpecialPrice: []domain.SpecialPrice{},
+		Status:       domain.Published,
+	}
+}
+
+func searchHitIds(hits []*domain.AccommodationSearchHit) []primitive.ObjectID {
+	ids := []primitive.ObjectID{}
+	for _, hit := range hits {
+		ids = append(ids, hit.Accommodation.Id)
+	}
+	return ids
+}
+
+func conformanceIds(accommodations []*domain.Accommodation) []primitive.ObjectID {
+	ids := []primitive.ObjectID{}
+	for _, accommodation := range accommodations {
+		ids = append(ids, accommodation.Id)
+	}
+	return ids
+}
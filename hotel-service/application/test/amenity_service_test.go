@@ -0,0 +1,84 @@
+package application_test
+
+import (
+	application2 "github.com/ZMS-DevOps/hotel-service/application"
+	"github.com/ZMS-DevOps/hotel-service/application/test"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestAmenityService_Delete_InUse(t *testing.T) {
+	mockAmenityStore := new(application.MockAmenityStore)
+	mockStore := new(application.MockAccommodationStore)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAmenityService(mockAmenityStore, mockStore, lokiMock)
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("CountWithAmenity", "wifi").Return(int64(3), nil)
+
+	err := service.Delete("wifi", spanMock, lokiMock)
+
+	assert.ErrorIs(t, err, domain.ErrAmenityInUse)
+	mockAmenityStore.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestAmenityService_KnownCodes_CachedUntilAmenitiesChange(t *testing.T) {
+	mockAmenityStore := new(application.MockAmenityStore)
+	mockStore := new(application.MockAccommodationStore)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAmenityService(mockAmenityStore, mockStore, lokiMock)
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAmenityStore.On("GetAll").Return([]*domain.Amenity{{Code: "wifi"}}, nil).Once()
+	mockAmenityStore.On("GetAll").Return([]*domain.Amenity{{Code: "wifi"}, {Code: "sauna"}}, nil).Once()
+	mockAmenityStore.On("Insert", mock.Anything).Return(nil)
+
+	first, err := service.KnownCodes(spanMock, lokiMock)
+	assert.NoError(t, err)
+	cached, err := service.KnownCodes(spanMock, lokiMock)
+	assert.NoError(t, err)
+	assert.NoError(t, service.Add(&domain.Amenity{Code: "sauna"}, spanMock, lokiMock))
+	reloaded, err := service.KnownCodes(spanMock, lokiMock)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{"wifi": true}, first)
+	assert.Equal(t, first, cached)
+	assert.Equal(t, map[string]bool{"wifi": true, "sauna": true}, reloaded)
+	mockAmenityStore.AssertNumberOfCalls(t, "GetAll", 2)
+}
+
+func TestAmenityMatcher_Match(t *testing.T) {
+	matcher := domain.NewAmenityMatcher([]*domain.Amenity{
+		{Code: "wifi", Labels: map[string]string{"en": "Free WiFi"}, Aliases: []string{"Wi-Fi"}},
+		{Code: "non_smoking", Labels: map[string]string{"en": "Non-smoking"}, Aliases: []string{"No smoke"}},
+	})
+
+	codes, unmapped := matcher.Match([]string{"free wifi", "WI-FI", "No  smoke", "non_smoking", "View"})
+
+	assert.Equal(t, []string{"wifi", "non_smoking"}, codes)
+	assert.Equal(t, []string{"View"}, unmapped)
+}
+
+func TestValidateAccommodationDto_RejectsUnknownAmenity(t *testing.T) {
+	accommodationDto := &dto.AccommodationDto{
+		HostId:       "host",
+		Name:         "Sea view",
+		Location:     "Split, HR",
+		Benefits:     []string{"wifi", "helipad"},
+		GuestNumber:  dto.GuestNumberDto{Min: 1, Max: 2},
+		DefaultPrice: dto.DefaultPriceDto{Price: 50, Type: "PerGuest"},
+	}
+	knownAmenities := map[string]bool{"wifi": true}
+
+	assert.Error(t, dto.ValidateAccommodationDto(accommodationDto, knownAmenities))
+
+	accommodationDto.Benefits = []string{"wifi"}
+	assert.NoError(t, dto.ValidateAccommodationDto(accommodationDto, knownAmenities))
+}
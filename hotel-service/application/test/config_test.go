@@ -0,0 +1,210 @@
+package application_test
+
+import (
+	"encoding/json"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/api"
+	"github.com/ZMS-DevOps/hotel-service/startup/config"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func lookupEnv(values map[string]string) config.LookupEnv {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func validEnv() map[string]string {
+	return map[string]string{
+		"DB_HOST":                 "hotel_db",
+		"BOOKING_HOST":            "booking",
+		"BOOKING_PORT":            "8001",
+		"SEARCH_HOST":             "search",
+		"SEARCH_PORT":             "8002",
+		"KAFKA_BOOTSTRAP_SERVERS": "kafka:9092",
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestConfig_Defaults(t *testing.T) {
+	loaded, err := config.Load(lookupEnv(validEnv()))
+
+	require.NoError(t, err)
+	assert.Equal(t, "8084", loaded.Port)
+	assert.Equal(t, "27017", loaded.HotelDBPort)
+	assert.Equal(t, "user1", loaded.KafkaAuthUsername)
+	assert.Equal(t, "hotel-service", loaded.KafkaGroupId)
+	assert.Equal(t, 3*time.Second, loaded.GrpcCallTimeout)
+	assert.Equal(t, config.MongoStore, loaded.AccommodationStore)
+	assert.True(t, loaded.KafkaEnabled)
+}
+
+func TestConfig_FileThenEnvironmentOverrides(t *testing.T) {
+	env := validEnv()
+	env[config.ConfigFileEnv] = writeConfigFile(t, `
+service_port: "9090"
+grpc_call_timeout: 750ms
+grpc_max_retries: 4
+kafka_auth_username: hotel
+trace_sample_ratio: 0.25
+purge_interval: 10m
+`)
+	env["GRPC_MAX_RETRIES"] = "1"
+	env["PURGE_INTERVAL"] = ""
+
+	loaded, err := config.Load(lookupEnv(env))
+
+	require.NoError(t, err)
+	assert.Equal(t, "9090", loaded.Port)
+	assert.Equal(t, 750*time.Millisecond, loaded.GrpcCallTimeout)
+	assert.Equal(t, 1, loaded.GrpcMaxRetries)
+	assert.Equal(t, "hotel", loaded.KafkaAuthUsername)
+	assert.Equal(t, 0.25, loaded.TraceSampleRatio)
+	assert.Equal(t, 10*time.Minute, loaded.PurgeInterval)
+}
+
+func TestConfig_AggregatesValidationErrors(t *testing.T) {
+	_, err := config.Load(lookupEnv(map[string]string{
+		"SERVICE_PORT":        "http",
+		"GRPC_CALL_TIMEOUT":   "soon",
+		"ACCOMMODATION_STORE": "postgres",
+		"GEOCODER":            "nominatim",
+		"NOMINATIM_URL":       "nominatim.local",
+		"GRPC_TRANSPORT":      "mtls",
+	}))
+
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.ElementsMatch(t, []string{
+		`GRPC_CALL_TIMEOUT: invalid duration "soon"`,
+		`SERVICE_PORT must be a port between 1 and 65535, got "http"`,
+		`ACCOMMODATION_STORE must be one of mongo, memory, got "postgres"`,
+		"BOOKING_HOST is required",
+		"SEARCH_HOST is required",
+		"BOOKING_PORT is required",
+		"SEARCH_PORT is required",
+		"KAFKA_BOOTSTRAP_SERVERS is required",
+		`NOMINATIM_URL must be an http(s) URL, got "nominatim.local"`,
+		`GRPC_TRANSPORT: transport "mtls" requires a certificate and key file`,
+	}, validationErr.Problems)
+	assert.Contains(t, err.Error(), "10 problems")
+}
+
+func TestConfig_PublicNominatimRequiresContactUserAgent(t *testing.T) {
+	env := validEnv()
+	env["GEOCODER"] = "nominatim"
+	env["NOMINATIM_URL"] = "https://nominatim.openstreetmap.org"
+
+	_, err := config.Load(lookupEnv(env))
+	assert.ErrorContains(t, err, "GEOCODER_USER_AGENT must include a contact email or URL")
+
+	env["GEOCODER_USER_AGENT"] = "hotel-service (ops@example.com)"
+	loaded, err := config.Load(lookupEnv(env))
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, loaded.GeocoderMinInterval)
+}
+
+func TestConfig_RetriesRequireBoundedBackoff(t *testing.T) {
+	env := validEnv()
+	env["GRPC_MAX_RETRY_BACKOFF"] = "0s"
+	env["GRPC_RETRY_DEADLINE"] = "1s"
+
+	_, err := config.Load(lookupEnv(env))
+
+	assert.ErrorContains(t, err, "GRPC_MAX_RETRY_BACKOFF must be positive")
+	assert.ErrorContains(t, err, "GRPC_RETRY_DEADLINE (1s) must not be shorter than GRPC_CALL_TIMEOUT (3s)")
+}
+
+func TestConfig_AdminPortMustDifferFromServicePort(t *testing.T) {
+	env := validEnv()
+	env["SERVICE_PORT"] = "8084"
+	env["ADMIN_PORT"] = "8084"
+
+	_, err := config.Load(lookupEnv(env))
+	assert.ErrorContains(t, err, "ADMIN_PORT must differ from SERVICE_PORT")
+
+	env["ADMIN_PORT"] = "9090"
+	loaded, err := config.Load(lookupEnv(env))
+	require.NoError(t, err)
+	assert.Equal(t, "9090", loaded.AdminPort)
+}
+
+func TestConfig_ConditionalRequirements(t *testing.T) {
+	loaded, err := config.Load(lookupEnv(map[string]string{
+		"ACCOMMODATION_STORE": config.MemoryStore,
+		"DEPENDENCIES":        config.LocalDependencies,
+		"KAFKA_ENABLED":       "false",
+	}))
+
+	require.NoError(t, err)
+	assert.False(t, loaded.KafkaEnabled)
+}
+
+func TestConfig_RejectsUnknownFileKeys(t *testing.T) {
+	env := validEnv()
+	env[config.ConfigFileEnv] = writeConfigFile(t, "servce_port: \"9090\"\n")
+
+	_, err := config.Load(lookupEnv(env))
+
+	assert.ErrorContains(t, err, "servce_port")
+}
+
+func TestConfig_MissingFile(t *testing.T) {
+	env := validEnv()
+	env[config.ConfigFileEnv] = filepath.Join(t.TempDir(), "missing.yml")
+
+	_, err := config.Load(lookupEnv(env))
+
+	assert.ErrorContains(t, err, "failed to read config file")
+}
+
+func TestConfigDebugHandler_RedactsSecrets(t *testing.T) {
+	env := validEnv()
+	env["MONGO_INITDB_ROOT_PASSWORD"] = "mongo-secret"
+	env["KAFKA_AUTH_PASSWORD"] = "kafka-secret"
+	loaded, err := config.Load(lookupEnv(env))
+	require.NoError(t, err)
+	router := mux.NewRouter()
+	api.NewConfigDebugHandler(loaded.Redacted).Init(router)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.NotContains(t, response.Body.String(), "mongo-secret")
+	assert.NotContains(t, response.Body.String(), "kafka-secret")
+	var view map[string]interface{}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &view))
+	assert.Equal(t, "[REDACTED]", view["db_password"])
+	assert.Equal(t, "[REDACTED]", view["kafka_auth_password"])
+	assert.Equal(t, "hotel_db", view["db_host"])
+	assert.Equal(t, "3s", view["grpc_call_timeout"])
+}
+
+func TestParseMethodTimeouts(t *testing.T) {
+	timeouts, err := config.ParseMethodTimeouts("booking.BookingService/CheckAccommodationHasReservation=500ms, /search.SearchService/AddAccommodation=2s")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"/booking.BookingService/CheckAccommodationHasReservation": 500 * time.Millisecond,
+		"/search.SearchService/AddAccommodation":                   2 * time.Second,
+	}, timeouts)
+
+	for _, value := range []string{"booking.BookingService/AddUnavailability", "booking.BookingService/AddUnavailability=soon"} {
+		_, err := config.ParseMethodTimeouts(value)
+		assert.Error(t, err, value)
+	}
+}
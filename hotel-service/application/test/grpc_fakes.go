@@ -0,0 +1,210 @@
+package application
+
+import (
+	"context"
+	booking "github.com/ZMS-DevOps/booking-service/proto"
+	search "github.com/ZMS-DevOps/search-service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+const bufconnSize = 1 << 20
+
+type failureQueue struct {
+	mutex    sync.Mutex
+	failures []codes.Code
+	delay    time.Duration
+}
+
+func (queue *failureQueue) FailNext(failures ...codes.Code) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.failures = append(queue.failures, failures...)
+}
+
+func (queue *failureQueue) SetDelay(delay time.Duration) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.delay = delay
+}
+
+func (queue *failureQueue) next(ctx context.Context) error {
+	queue.mutex.Lock()
+	delay := queue.delay
+	var failure codes.Code
+	if len(queue.failures) > 0 {
+		failure = queue.failures[0]
+		queue.failures = queue.failures[1:]
+	}
+	queue.mutex.Unlock()
+	if delay > 0 {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(delay):
+		}
+	}
+	if failure != codes.OK {
+		return status.Error(failure, "injected failure")
+	}
+	return nil
+}
+
+type FakeBookingServer struct {
+	booking.UnimplementedBookingServiceServer
+	failureQueue
+	mutex                     sync.Mutex
+	ReservedAccommodationIds  map[string]bool
+	Unavailable               bool
+	AddUnavailabilityRequests []*booking.AddUnavailabilityRequest
+	EditAccommodationRequests []*booking.EditAccommodationRequest
+	CheckReservationRequests  []*booking.CheckAccommodationHasReservationRequest
+}
+
+func NewFakeBookingServer() *FakeBookingServer {
+	return &FakeBookingServer{ReservedAccommodationIds: map[string]bool{}}
+}
+
+func (server *FakeBookingServer) AddUnavailability(ctx context.Context, in *booking.AddUnavailabilityRequest) (*booking.AddUnavailabilityResponse, error) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.AddUnavailabilityRequests = append(server.AddUnavailabilityRequests, in)
+	if server.Unavailable {
+		return nil, status.Error(codes.Unavailable, "booking service unavailable")
+	}
+	if err := server.next(ctx); err != nil {
+		return nil, err
+	}
+	return &booking.AddUnavailabilityResponse{}, nil
+}
+
+func (server *FakeBookingServer) EditAccommodation(ctx context.Context, in *booking.EditAccommodationRequest) (*booking.EditAccommodationResponse, error) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.EditAccommodationRequests = append(server.EditAccommodationRequests, in)
+	if server.Unavailable {
+		return nil, status.Error(codes.Unavailable, "booking service unavailable")
+	}
+	if err := server.next(ctx); err != nil {
+		return nil, err
+	}
+	return &booking.EditAccommodationResponse{}, nil
+}
+
+func (server *FakeBookingServer) CheckAccommodationHasReservation(ctx context.Context, in *booking.CheckAccommodationHasReservationRequest) (*booking.CheckAccommodationHasReservationResponse, error) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.CheckReservationRequests = append(server.CheckReservationRequests, in)
+	if server.Unavailable {
+		return nil, status.Error(codes.Unavailable, "booking service unavailable")
+	}
+	if err := server.next(ctx); err != nil {
+		return nil, err
+	}
+	return &booking.CheckAccommodationHasReservationResponse{Success: !server.ReservedAccommodationIds[in.AccommodationId]}, nil
+}
+
+func (server *FakeBookingServer) Reserve(accommodationId string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.ReservedAccommodationIds[accommodationId] = true
+}
+
+func (server *FakeBookingServer) AddUnavailabilityCalls() []*booking.AddUnavailabilityRequest {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]*booking.AddUnavailabilityRequest{}, server.AddUnavailabilityRequests...)
+}
+
+type FakeSearchServer struct {
+	search.UnimplementedSearchServiceServer
+	failureQueue
+	mutex                       sync.Mutex
+	Accommodations              map[string]*search.Accommodation
+	AddAccommodationRequests    []*search.AddAccommodationRequest
+	EditAccommodationRequests   []*search.EditAccommodationRequest
+	DeleteAccommodationRequests []*search.DeleteAccommodationRequest
+}
+
+func NewFakeSearchServer() *FakeSearchServer {
+	return &FakeSearchServer{Accommodations: map[string]*search.Accommodation{}}
+}
+
+func (server *FakeSearchServer) AddAccommodation(ctx context.Context, in *search.AddAccommodationRequest) (*search.AddAccommodationResponse, error) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.AddAccommodationRequests = append(server.AddAccommodationRequests, in)
+	if err := server.next(ctx); err != nil {
+		return nil, err
+	}
+	server.Accommodations[in.Accommodation.AccommodationId] = in.Accommodation
+	return &search.AddAccommodationResponse{}, nil
+}
+
+func (server *FakeSearchServer) EditAccommodation(ctx context.Context, in *search.EditAccommodationRequest) (*search.EditAccommodationResponse, error) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.EditAccommodationRequests = append(server.EditAccommodationRequests, in)
+	if err := server.next(ctx); err != nil {
+		return nil, err
+	}
+	server.Accommodations[in.Accommodation.AccommodationId] = in.Accommodation
+	return &search.EditAccommodationResponse{}, nil
+}
+
+func (server *FakeSearchServer) DeleteAccommodation(ctx context.Context, in *search.DeleteAccommodationRequest) (*search.DeleteAccommodationResponse, error) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.DeleteAccommodationRequests = append(server.DeleteAccommodationRequests, in)
+	if err := server.next(ctx); err != nil {
+		return nil, err
+	}
+	delete(server.Accommodations, in.AccommodationId)
+	return &search.DeleteAccommodationResponse{}, nil
+}
+
+func (server *FakeSearchServer) Indexed(accommodationId string) (*search.Accommodation, bool) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	accommodation, ok := server.Accommodations[accommodationId]
+	return accommodation, ok
+}
+
+func StartFakeGrpcServers(t *testing.T, bookingServer *FakeBookingServer, searchServer *FakeSearchServer, options ...grpc.DialOption) (booking.BookingServiceClient, search.SearchServiceClient) {
+	listener := StartFakeGrpcListener(t, bookingServer, searchServer)
+	conn := DialFakeGrpc(t, listener, append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, options...)...)
+	return booking.NewBookingServiceClient(conn), search.NewSearchServiceClient(conn)
+}
+
+func StartFakeGrpcListener(t *testing.T, bookingServer *FakeBookingServer, searchServer *FakeSearchServer, options ...grpc.ServerOption) *bufconn.Listener {
+	listener := bufconn.Listen(bufconnSize)
+	server := grpc.NewServer(options...)
+	booking.RegisterBookingServiceServer(server, bookingServer)
+	search.RegisterSearchServiceServer(server, searchServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener
+}
+
+func DialFakeGrpc(t *testing.T, listener *bufconn.Listener, options ...grpc.DialOption) *grpc.ClientConn {
+	options = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+	}, options...)
+	conn, err := grpc.Dial("bufnet", options...)
+	if err != nil {
+		t.Fatalf("failed to dial bufconn: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	return conn
+}
@@ -0,0 +1,197 @@
+package application_test
+
+import (
+	"context"
+	booking "github.com/ZMS-DevOps/booking-service/proto"
+	application2 "github.com/ZMS-DevOps/hotel-service/application"
+	"github.com/ZMS-DevOps/hotel-service/application/external"
+	"github.com/ZMS-DevOps/hotel-service/application/test"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	search "github.com/ZMS-DevOps/search-service/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+type sagaFixture struct {
+	store         *application.MockAccommodationStore
+	auditStore    *application.MockAccommodationAuditStore
+	sagaStore     *application.MockHostDeletionSagaStore
+	bookingClient *application.MockBookingServiceClient
+	searchClient  *application.MockSearchServiceClient
+	producer      *application.MockEventProducer
+	span          *application.SpanMock
+	loki          *application.LokiMock
+}
+
+func newSagaFixture(reserved bool, accommodations ...*domain.Accommodation) *sagaFixture {
+	fixture := &sagaFixture{
+		store:         new(application.MockAccommodationStore),
+		auditStore:    new(application.MockAccommodationAuditStore),
+		sagaStore:     new(application.MockHostDeletionSagaStore),
+		bookingClient: new(application.MockBookingServiceClient),
+		searchClient:  new(application.MockSearchServiceClient),
+		producer:      new(application.MockEventProducer),
+		span:          new(application.SpanMock),
+		loki:          new(application.LokiMock),
+	}
+	fixture.auditStore.On("Insert", mock.Anything).Return(nil)
+	fixture.loki.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	fixture.loki.On("Errorf", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	fixture.span.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	fixture.span.On("RecordError", mock.Anything, mock.Anything).Return(nil)
+	fixture.span.On("SetStatus", mock.Anything, mock.Anything).Return(nil)
+	fixture.sagaStore.On("Insert", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*domain.HostDeletionSaga).CreatedAt = time.Now()
+	}).Return(nil)
+	fixture.sagaStore.On("Update", mock.Anything).Return(nil)
+	fixture.sagaStore.On("Lease", mock.Anything, mock.Anything).Return(nil)
+	fixture.store.On("GetByHostId", "host-1").Return(accommodations, nil)
+	fixture.bookingClient.On("CheckAccommodationHasReservation", mock.Anything, mock.Anything, mock.Anything).Return(&booking.CheckAccommodationHasReservationResponse{Success: !reserved}, nil)
+	fixture.bookingClient.On("EditAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&booking.EditAccommodationResponse{}, nil)
+	fixture.searchClient.On("DeleteAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.DeleteAccommodationResponse{}, nil)
+	fixture.searchClient.On("AddAccommodation", mock.Anything, mock.Anything, mock.Anything).Return(&search.AddAccommodationResponse{}, nil)
+	return fixture
+}
+
+func (fixture *sagaFixture) service(producer external.EventProducer) *application2.HostDeletionSagaService {
+	accommodationService := application2.NewAccommodationService(fixture.store, fixture.auditStore, fixture.bookingClient, fixture.searchClient, nil, fixture.loki)
+	return application2.NewHostDeletionSagaService(fixture.sagaStore, accommodationService, fixture.bookingClient, producer, time.Minute, fixture.loki)
+}
+
+func TestHostDeletionSagaService_RejectionStaysResumableUntilPublished(t *testing.T) {
+	fixture := newSagaFixture(true, &domain.Accommodation{Id: primitive.NewObjectID()})
+	fixture.sagaStore.On("GetUnfinishedByHostId", "host-1").Return(nil, domain.ErrNotFound).Once()
+	fixture.producer.On("Produce", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+	fixture.producer.On("Produce", mock.Anything, mock.Anything).Return(nil).Once()
+	sagaService := fixture.service(fixture.producer)
+
+	saga, err := sagaService.Start(context.Background(), "host-1", fixture.span, fixture.loki)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, domain.HostDeletionRejecting, saga.Status)
+
+	fixture.sagaStore.On("GetUnfinished").Return([]*domain.HostDeletionSaga{saga}, nil)
+	require.NoError(t, sagaService.ResumeUnfinished(context.Background(), fixture.span, fixture.loki))
+
+	assert.Equal(t, domain.HostDeletionRejected, saga.Status)
+	fixture.producer.AssertNumberOfCalls(t, "Produce", 2)
+}
+
+func TestHostDeletionSagaService_RejectionWithoutProducerIsNotTerminal(t *testing.T) {
+	fixture := newSagaFixture(true, &domain.Accommodation{Id: primitive.NewObjectID()})
+	fixture.sagaStore.On("GetUnfinishedByHostId", "host-1").Return(nil, domain.ErrNotFound).Once()
+
+	saga, err := fixture.service(nil).Start(context.Background(), "host-1", fixture.span, fixture.loki)
+
+	assert.Error(t, err)
+	assert.Equal(t, domain.HostDeletionRejecting, saga.Status)
+	assert.Equal(t, domain.HostDeletionRejected, saga.Outcome)
+}
+
+func TestHostDeletionSagaService_RetriesTransientFailures(t *testing.T) {
+	accommodation := &domain.Accommodation{Id: primitive.NewObjectID(), HostId: "host-1"}
+	fixture := newSagaFixture(false, accommodation)
+	fixture.sagaStore.On("GetUnfinishedByHostId", "host-1").Return(nil, domain.ErrNotFound).Once()
+	fixture.store.On("Get", accommodation.Id).Return(accommodation, nil)
+	fixture.store.On("SoftDelete", accommodation.Id, mock.Anything).Return(status.Error(codes.Unavailable, "mongo proxy restarting")).Once()
+	fixture.store.On("SoftDelete", accommodation.Id, mock.Anything).Return(nil).Once()
+
+	saga, err := fixture.service(fixture.producer).Start(context.Background(), "host-1", fixture.span, fixture.loki)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.HostDeletionCompleted, saga.Status)
+	assert.Equal(t, []primitive.ObjectID{accommodation.Id}, saga.DeletedIds)
+	fixture.store.AssertNotCalled(t, "Restore", mock.Anything)
+	fixture.producer.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything)
+}
+
+func TestHostDeletionSagaService_PersistentTransientFailureLeavesSagaDeleting(t *testing.T) {
+	accommodation := &domain.Accommodation{Id: primitive.NewObjectID(), HostId: "host-1"}
+	fixture := newSagaFixture(false, accommodation)
+	fixture.sagaStore.On("GetUnfinishedByHostId", "host-1").Return(nil, domain.ErrNotFound).Once()
+	fixture.store.On("Get", accommodation.Id).Return(accommodation, nil)
+	fixture.store.On("SoftDelete", accommodation.Id, mock.Anything).Return(status.Error(codes.Unavailable, "unavailable"))
+
+	saga, err := fixture.service(fixture.producer).Start(context.Background(), "host-1", fixture.span, fixture.loki)
+
+	assert.Error(t, err)
+	assert.Equal(t, domain.HostDeletionDeleting, saga.Status)
+	fixture.store.AssertNumberOfCalls(t, "SoftDelete", 3)
+	fixture.store.AssertNotCalled(t, "Restore", mock.Anything)
+}
+
+func TestHostDeletionSagaService_CompensationSkipsListingsDeletedBeforeSaga(t *testing.T) {
+	deletedAt := time.Now().Add(-time.Hour)
+	previouslyDeleted := &domain.Accommodation{Id: primitive.NewObjectID(), HostId: "host-1", DeletedAt: &deletedAt}
+	first := &domain.Accommodation{Id: primitive.NewObjectID(), HostId: "host-1"}
+	failing := &domain.Accommodation{Id: primitive.NewObjectID(), HostId: "host-1"}
+	fixture := newSagaFixture(false, previouslyDeleted, first, failing)
+	fixture.sagaStore.On("GetUnfinishedByHostId", "host-1").Return(nil, domain.ErrNotFound).Once()
+	fixture.store.On("Get", previouslyDeleted.Id).Return((*domain.Accommodation)(nil), domain.ErrNotFound)
+	fixture.store.On("GetDeleted", previouslyDeleted.Id).Return(previouslyDeleted, nil)
+	fixture.store.On("Get", first.Id).Return(first, nil)
+	fixture.store.On("Get", failing.Id).Return(failing, nil)
+	fixture.store.On("SoftDelete", first.Id, mock.Anything).Return(nil)
+	fixture.store.On("SoftDelete", failing.Id, mock.Anything).Return(assert.AnError)
+	fixture.store.On("Restore", first.Id).Return(nil)
+	fixture.producer.On("Produce", mock.Anything, mock.Anything).Return(nil)
+
+	saga, err := fixture.service(fixture.producer).Start(context.Background(), "host-1", fixture.span, fixture.loki)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.HostDeletionCompensated, saga.Status)
+	assert.Empty(t, saga.DeletedIds)
+	fixture.store.AssertCalled(t, "Restore", first.Id)
+	fixture.store.AssertNotCalled(t, "Restore", previouslyDeleted.Id)
+	fixture.store.AssertNotCalled(t, "SoftDelete", previouslyDeleted.Id, mock.Anything)
+}
+
+func TestHostDeletionSagaService_ResumesCompensationFromSavedProgress(t *testing.T) {
+	restored := primitive.NewObjectID()
+	pending := &domain.Accommodation{Id: primitive.NewObjectID(), HostId: "host-1"}
+	fixture := newSagaFixture(false)
+	saga := &domain.HostDeletionSaga{
+		HostId:           "host-1",
+		Status:           domain.HostDeletionCompensating,
+		Outcome:          domain.HostDeletionCompensated,
+		AccommodationIds: []primitive.ObjectID{restored, pending.Id},
+		DeletedIds:       []primitive.ObjectID{pending.Id},
+	}
+	fixture.sagaStore.On("GetUnfinished").Return([]*domain.HostDeletionSaga{saga}, nil)
+	fixture.store.On("Restore", pending.Id).Return(assert.AnError).Once()
+	fixture.store.On("Restore", pending.Id).Return(nil).Once()
+	fixture.store.On("Get", pending.Id).Return(pending, nil)
+	fixture.producer.On("Produce", mock.Anything, mock.Anything).Return(nil)
+	sagaService := fixture.service(fixture.producer)
+
+	require.NoError(t, sagaService.ResumeUnfinished(context.Background(), fixture.span, fixture.loki))
+	assert.Equal(t, domain.HostDeletionCompensating, saga.Status)
+	assert.Equal(t, []primitive.ObjectID{pending.Id}, saga.DeletedIds)
+
+	require.NoError(t, sagaService.ResumeUnfinished(context.Background(), fixture.span, fixture.loki))
+	assert.Equal(t, domain.HostDeletionCompensated, saga.Status)
+	assert.Empty(t, saga.DeletedIds)
+	fixture.store.AssertNotCalled(t, "Restore", restored)
+	fixture.producer.AssertNumberOfCalls(t, "Produce", 1)
+}
+
+func TestHostDeletionSagaService_SkipsSagaLeasedByAnotherRunner(t *testing.T) {
+	fixture := newSagaFixture(false)
+	saga := &domain.HostDeletionSaga{HostId: "host-1", Status: domain.HostDeletionChecking, Version: 3}
+	leased := new(application.MockHostDeletionSagaStore)
+	leased.On("GetUnfinished").Return([]*domain.HostDeletionSaga{saga}, nil)
+	leased.On("Lease", saga, mock.Anything).Return(domain.ErrVersionConflict)
+	fixture.sagaStore = leased
+
+	require.NoError(t, fixture.service(fixture.producer).ResumeUnfinished(context.Background(), fixture.span, fixture.loki))
+
+	leased.AssertNotCalled(t, "Update", mock.Anything)
+	fixture.bookingClient.AssertNotCalled(t, "CheckAccommodationHasReservation", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, domain.HostDeletionChecking, saga.Status)
+}
@@ -0,0 +1,55 @@
+package application_test
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/dto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCancellationPolicy_RefundPercent(t *testing.T) {
+	assert.Equal(t, 100, domain.FlexibleCancellation.RefundPercent(1))
+	assert.Equal(t, 0, domain.FlexibleCancellation.RefundPercent(0))
+	assert.Equal(t, 100, domain.ModerateCancellation.RefundPercent(5))
+	assert.Equal(t, 50, domain.ModerateCancellation.RefundPercent(4))
+	assert.Equal(t, 100, domain.StrictCancellation.RefundPercent(30))
+	assert.Equal(t, 50, domain.StrictCancellation.RefundPercent(7))
+	assert.Equal(t, 0, domain.StrictCancellation.RefundPercent(6))
+	assert.Equal(t, 0, domain.NonRefundableCancellation.RefundPercent(60))
+	assert.Nil(t, dto.MapCancellationPolicyResponse(""))
+}
+
+func TestValidateAccommodationDto_HouseRules(t *testing.T) {
+	accommodationDto := &dto.AccommodationDto{
+		HostId:       "host",
+		Name:         "Mountain lodge",
+		Location:     "Zermatt, CH",
+		GuestNumber:  dto.GuestNumberDto{Min: 1, Max: 4},
+		DefaultPrice: dto.DefaultPriceDto{Price: 200, Type: "PerApartmentUnit"},
+		HouseRules: &dto.HouseRulesDto{
+			CheckInFrom:   "15:00",
+			CheckInUntil:  "22:00",
+			CheckOutUntil: "11:00",
+			PetsAllowed:   true,
+			QuietHours:    &dto.TimeWindowDto{From: "22:00", Until: "07:00"},
+		},
+		CancellationPolicy: "moderate",
+	}
+	assert.NoError(t, dto.ValidateAccommodationDto(accommodationDto, nil))
+
+	accommodationDto.HouseRules.CheckInUntil = "02:00"
+	assert.NoError(t, dto.ValidateAccommodationDto(accommodationDto, nil))
+
+	accommodationDto.HouseRules.CheckInUntil = "15:00"
+	assert.Error(t, dto.ValidateAccommodationDto(accommodationDto, nil))
+
+	accommodationDto.HouseRules.CheckInUntil = "25:00"
+	assert.Error(t, dto.ValidateAccommodationDto(accommodationDto, nil))
+
+	accommodationDto.HouseRules.CheckInUntil = "22:00"
+	accommodationDto.CancellationPolicy = "lenient"
+	assert.Error(t, dto.ValidateAccommodationDto(accommodationDto, nil))
+
+	accommodation := dto.MapAccommodation(accommodationDto)
+	assert.Equal(t, "07:00", accommodation.HouseRules.QuietHours.Until)
+}
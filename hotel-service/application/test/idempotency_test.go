@@ -0,0 +1,277 @@
+package application_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/api"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyMemoryStore_Conformance(t *testing.T) {
+	runIdempotencyStoreConformance(t, func(t *testing.T) domain.IdempotencyStore {
+		return persistence.NewIdempotencyMemoryStore(time.Hour)
+	})
+}
+
+func TestIdempotencyMongoDBStore_Conformance(t *testing.T) {
+	uri := os.Getenv(testDBUriEnv)
+	if uri == "" {
+		t.Skipf("%s not set", testDBUriEnv)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	require.NoError(t, client.Ping(ctx, nil))
+	defer client.Disconnect(context.Background())
+
+	runIdempotencyStoreConformance(t, func(t *testing.T) domain.IdempotencyStore {
+		records := client.Database(persistence.DATABASE).Collection(persistence.IDEMPOTENCY_COLLECTION)
+		_, err := records.DeleteMany(context.TODO(), bson.M{})
+		require.NoError(t, err)
+		t.Cleanup(func() { records.DeleteMany(context.TODO(), bson.M{}) })
+		return persistence.NewIdempotencyMongoDBStore(client, time.Hour)
+	})
+}
+
+func runIdempotencyStoreConformance(t *testing.T, newStore func(t *testing.T) domain.IdempotencyStore) {
+	newRecord := func(id string) *domain.IdempotencyRecord {
+		now := time.Now().UTC()
+		return &domain.IdempotencyRecord{Id: id, RequestHash: "hash", Method: http.MethodPost, Path: "/accommodation", CreatedAt: now, LeaseExpiresAt: now.Add(time.Minute)}
+	}
+
+	t.Run("ReserveThenComplete", func(t *testing.T) {
+		store := newStore(t)
+
+		existing, err := store.Reserve(newRecord("user-1:key"))
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+		existing, err = store.Reserve(newRecord("user-1:key"))
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, domain.IdempotencyPending, existing.Status)
+
+		require.NoError(t, store.Complete("user-1:key", http.StatusCreated, http.Header{"Etag": {`"1"`}}, []byte(`{"id":"1"}`)))
+		existing, err = store.Reserve(newRecord("user-1:key"))
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, domain.IdempotencyCompleted, existing.Status)
+		assert.Equal(t, http.StatusCreated, existing.StatusCode)
+		assert.Equal(t, `"1"`, existing.Header.Get("ETag"))
+		assert.Equal(t, `{"id":"1"}`, string(existing.Body))
+		assert.Equal(t, "hash", existing.RequestHash)
+	})
+
+	t.Run("ReleaseFreesPendingKey", func(t *testing.T) {
+		store := newStore(t)
+		_, err := store.Reserve(newRecord("user-1:key"))
+		require.NoError(t, err)
+
+		require.NoError(t, store.Release("user-1:key"))
+
+		existing, err := store.Reserve(newRecord("user-1:key"))
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+	})
+
+	t.Run("ReleaseKeepsCompletedKey", func(t *testing.T) {
+		store := newStore(t)
+		_, err := store.Reserve(newRecord("user-1:key"))
+		require.NoError(t, err)
+		require.NoError(t, store.Complete("user-1:key", http.StatusOK, http.Header{}, nil))
+
+		require.NoError(t, store.Release("user-1:key"))
+
+		existing, err := store.Reserve(newRecord("user-1:key"))
+		require.NoError(t, err)
+		assert.NotNil(t, existing)
+	})
+
+	t.Run("ReclaimsExpiredLease", func(t *testing.T) {
+		store := newStore(t)
+		abandoned := newRecord("user-1:key")
+		abandoned.LeaseExpiresAt = time.Now().UTC().Add(-time.Second)
+		_, err := store.Reserve(abandoned)
+		require.NoError(t, err)
+
+		existing, err := store.Reserve(newRecord("user-1:key"))
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+		existing, err = store.Reserve(newRecord("user-1:key"))
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, domain.IdempotencyPending, existing.Status)
+	})
+
+	t.Run("CompletedKeyOutlivesLease", func(t *testing.T) {
+		store := newStore(t)
+		record := newRecord("user-1:key")
+		record.LeaseExpiresAt = time.Now().UTC().Add(-time.Second)
+		_, err := store.Reserve(record)
+		require.NoError(t, err)
+		require.NoError(t, store.Complete("user-1:key", http.StatusCreated, http.Header{}, nil))
+
+		existing, err := store.Reserve(newRecord("user-1:key"))
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, domain.IdempotencyCompleted, existing.Status)
+	})
+
+	t.Run("CompleteUnknownKey", func(t *testing.T) {
+		store := newStore(t)
+
+		assert.Error(t, store.Complete("user-1:missing", http.StatusOK, http.Header{}, nil))
+	})
+}
+
+func TestIdempotencyMemoryStore_ExpiresRecords(t *testing.T) {
+	store := persistence.NewIdempotencyMemoryStore(time.Minute)
+	_, err := store.Reserve(&domain.IdempotencyRecord{Id: "user-1:key", CreatedAt: time.Now().Add(-2 * time.Minute), LeaseExpiresAt: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+
+	existing, err := store.Reserve(&domain.IdempotencyRecord{Id: "user-1:key", CreatedAt: time.Now()})
+
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+}
+
+func withIdempotencyKey(request *http.Request, key string) *http.Request {
+	request.Header.Set("Idempotency-Key", key)
+	return asSubject(request, "host-1")
+}
+
+func TestIdempotency_ReplaysAdd(t *testing.T) {
+	harness := newHandlerHarness(t)
+	photos := map[string]string{"front.jpg": "jpeg-bytes"}
+
+	first := harness.serve(withIdempotencyKey(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, photos), "create-1"))
+	second := harness.serve(withIdempotencyKey(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, photos), "create-1"))
+
+	require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+	assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+	accommodations, err := harness.store.GetByHostId("host-1")
+	require.NoError(t, err)
+	assert.Len(t, accommodations, 1)
+	assert.Len(t, harness.bookingServer.AddUnavailabilityCalls(), 1)
+}
+
+func TestIdempotency_RejectsDifferentPayload(t *testing.T) {
+	harness := newHandlerHarness(t)
+	photos := map[string]string{"front.jpg": "jpeg-bytes"}
+	changed := strings.Replace(validAccommodationJson, "Sea View Apartment", "Garden Studio", 1)
+
+	first := harness.serve(withIdempotencyKey(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, photos), "create-1"))
+	second := harness.serve(withIdempotencyKey(multipartRequest(t, http.MethodPost, "/accommodation", changed, photos), "create-1"))
+
+	require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+	assert.Equal(t, http.StatusUnprocessableEntity, second.Code)
+	accommodations, err := harness.store.GetByHostId("host-1")
+	require.NoError(t, err)
+	assert.Len(t, accommodations, 1)
+}
+
+func TestIdempotency_ReplaysPriceUpdate(t *testing.T) {
+	harness := newHandlerHarness(t)
+	accommodation := harness.insertPublished(t)
+	updatePrice := func() *http.Request {
+		request := jsonRequest(http.MethodPut, "/accommodation/price/"+accommodation.Id.Hex(), `{"price": 150, "type": "PerGuest"}`)
+		request.Header.Set("If-Match", `"1"`)
+		return withIdempotencyKey(request, "price-1")
+	}
+
+	first := harness.serve(updatePrice())
+	second := harness.serve(updatePrice())
+
+	require.Equal(t, http.StatusOK, first.Code, first.Body.String())
+	assert.Equal(t, http.StatusOK, second.Code, second.Body.String())
+	assert.Equal(t, `"2"`, first.Header().Get("ETag"))
+	assert.Equal(t, `"2"`, second.Header().Get("ETag"))
+	assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotency_ReleasesKeyOnServerError(t *testing.T) {
+	harness := newHandlerHarness(t)
+	photos := map[string]string{"front.jpg": "jpeg-bytes"}
+	harness.bookingServer.Unavailable = true
+
+	failed := harness.serve(withIdempotencyKey(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, photos), "create-1"))
+	harness.bookingServer.Unavailable = false
+	retried := harness.serve(withIdempotencyKey(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, photos), "create-1"))
+
+	assert.GreaterOrEqual(t, failed.Code, http.StatusInternalServerError)
+	assert.Equal(t, http.StatusCreated, retried.Code, retried.Body.String())
+	assert.Empty(t, retried.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotency_RejectsOverlongKey(t *testing.T) {
+	harness := newHandlerHarness(t)
+
+	response := harness.serve(withIdempotencyKey(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, nil), strings.Repeat("k", 256)))
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestIdempotency_RequiresVerifiedCaller(t *testing.T) {
+	harness := newHandlerHarness(t)
+	request := multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, nil)
+	request.Header.Set("Idempotency-Key", "create-1")
+	request.Header.Set("x-jwt-payload", base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"host-1"}`)))
+
+	response := harness.serve(request)
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	accommodations, err := harness.store.GetByHostId("host-1")
+	require.NoError(t, err)
+	assert.Empty(t, accommodations)
+}
+
+func TestIdempotency_ScopesKeysPerCaller(t *testing.T) {
+	harness := newHandlerHarness(t)
+	photos := map[string]string{"front.jpg": "jpeg-bytes"}
+
+	first := harness.serve(withIdempotencyKey(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, photos), "create-1"))
+	other := harness.serve(asModerator(withIdempotencyKey(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, photos), "create-1")))
+
+	require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+	assert.Equal(t, http.StatusCreated, other.Code, other.Body.String())
+	assert.Empty(t, other.Header().Get("Idempotent-Replayed"))
+}
+
+type failingCompleteStore struct {
+	domain.IdempotencyStore
+}
+
+func (store failingCompleteStore) Complete(string, int, http.Header, []byte) error {
+	return errors.New("write failed")
+}
+
+func TestIdempotency_ReleasesKeyWhenResponseCannotBeStored(t *testing.T) {
+	store := persistence.NewIdempotencyMemoryStore(time.Hour)
+	handler := api.NewIdempotency(failingCompleteStore{store}, time.Minute).Wrap(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	response := httptest.NewRecorder()
+	handler(response, withIdempotencyKey(jsonRequest(http.MethodPost, "/accommodation", `{}`), "create-1"))
+
+	require.Equal(t, http.StatusCreated, response.Code)
+	existing, err := store.Reserve(&domain.IdempotencyRecord{Id: "host-1:create-1", CreatedAt: time.Now(), LeaseExpiresAt: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+}
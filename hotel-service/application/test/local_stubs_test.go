@@ -0,0 +1,102 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	booking "github.com/ZMS-DevOps/booking-service/proto"
+	"github.com/ZMS-DevOps/hotel-service/application/external"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/api"
+	search "github.com/ZMS-DevOps/search-service/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLocalStubs_RecordCalls(t *testing.T) {
+	recorder, err := external.NewStubRecorder("")
+	require.NoError(t, err)
+	bookingClient := external.NewLocalBookingClient(recorder)
+	searchClient := external.NewLocalSearchClient(recorder)
+
+	reservation, err := bookingClient.CheckAccommodationHasReservation(context.TODO(), &booking.CheckAccommodationHasReservationRequest{AccommodationId: "acc-1"})
+	require.NoError(t, err)
+	_, err = searchClient.DeleteAccommodation(context.TODO(), &search.DeleteAccommodationRequest{AccommodationId: "acc-1"})
+	require.NoError(t, err)
+
+	assert.True(t, reservation.Success)
+	calls := recorder.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "booking.CheckAccommodationHasReservation", calls[0].Method)
+	assert.Equal(t, "search.DeleteAccommodation", calls[1].Method)
+	assert.Equal(t, "acc-1", calls[1].Request.(*search.DeleteAccommodationRequest).AccommodationId)
+}
+
+func TestLocalStubs_ConfiguredFailures(t *testing.T) {
+	recorder, err := external.NewStubRecorder("booking.AddUnavailability=unavailable, search.AddAccommodation=DEADLINE_EXCEEDED")
+	require.NoError(t, err)
+	bookingClient := external.NewLocalBookingClient(recorder)
+	searchClient := external.NewLocalSearchClient(recorder)
+
+	_, bookingErr := bookingClient.AddUnavailability(context.TODO(), &booking.AddUnavailabilityRequest{Id: "acc-1"})
+	_, searchErr := searchClient.AddAccommodation(context.TODO(), &search.AddAccommodationRequest{Accommodation: &search.Accommodation{AccommodationId: "acc-1"}})
+	_, editErr := bookingClient.EditAccommodation(context.TODO(), &booking.EditAccommodationRequest{Id: "acc-1"})
+
+	assert.Equal(t, codes.Unavailable, status.Code(bookingErr))
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(searchErr))
+	assert.NoError(t, editErr)
+	assert.NotEmpty(t, recorder.Calls()[0].Error)
+}
+
+func TestLocalStubs_InvalidFailureSpec(t *testing.T) {
+	for _, spec := range []string{"booking.AddUnavailability", "booking.AddUnavailability=teapot", "booking.AddUnavailability=ok"} {
+		_, err := external.NewStubRecorder(spec)
+
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestStubDebugHandler(t *testing.T) {
+	recorder, err := external.NewStubRecorder("")
+	require.NoError(t, err)
+	router := mux.NewRouter()
+	api.NewStubDebugHandler(recorder).Init(router)
+	bookingClient := external.NewLocalBookingClient(recorder)
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return response
+	}
+
+	response := serve(http.MethodPut, "/debug/stubs/failures/booking.CheckAccommodationHasReservation", `{"code": "unavailable"}`)
+	require.Equal(t, http.StatusOK, response.Code)
+	_, err = bookingClient.CheckAccommodationHasReservation(context.TODO(), &booking.CheckAccommodationHasReservationRequest{AccommodationId: "acc-1"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = bookingClient.AddUnavailability(context.TODO(), &booking.AddUnavailabilityRequest{Id: "acc-1"})
+	assert.NoError(t, err)
+
+	response = serve(http.MethodGet, "/debug/stubs?method=booking.AddUnavailability", "")
+	require.Equal(t, http.StatusOK, response.Code)
+	var debug struct {
+		Calls []struct {
+			Method  string                 `json:"method"`
+			Request map[string]interface{} `json:"request"`
+		} `json:"calls"`
+		Failures map[string]string `json:"failures"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &debug))
+	require.Len(t, debug.Calls, 1)
+	assert.Equal(t, "acc-1", debug.Calls[0].Request["id"])
+	assert.Equal(t, map[string]string{"booking.CheckAccommodationHasReservation": "Unavailable"}, debug.Failures)
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, "/debug/stubs/failures/search.AddAccommodation", `{"code": "teapot"}`).Code)
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodDelete, "/debug/stubs/failures/booking.CheckAccommodationHasReservation", "").Code)
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodDelete, "/debug/stubs", "").Code)
+	assert.Empty(t, recorder.Calls())
+	assert.Empty(t, recorder.Failures())
+}
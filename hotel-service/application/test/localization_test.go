@@ -0,0 +1,58 @@
+package application_test
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/dto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMapLocalizedAccommodationResponse(t *testing.T) {
+	accommodation := domain.Accommodation{
+		Name:          "Vila na obali",
+		Description:   "Kamena kuća pored mora",
+		DefaultLocale: "sr",
+		Translations: map[string]domain.Translation{
+			"en": {Name: "Seaside villa", Description: "Stone house by the sea"},
+			"de": {Name: "Villa am Meer"},
+		},
+	}
+
+	response := dto.MapLocalizedAccommodationResponse(accommodation, "en-GB,en;q=0.9")
+	assert.Equal(t, "en", response.Locale)
+	assert.Equal(t, "Seaside villa", response.Name)
+	assert.Equal(t, "Stone house by the sea", response.Description)
+
+	response = dto.MapLocalizedAccommodationResponse(accommodation, "de-AT")
+	assert.Equal(t, "de", response.Locale)
+	assert.Equal(t, "Villa am Meer", response.Name)
+	assert.Equal(t, "Kamena kuća pored mora", response.Description)
+
+	response = dto.MapLocalizedAccommodationResponse(accommodation, "ja")
+	assert.Equal(t, "sr", response.Locale)
+	assert.Equal(t, "Vila na obali", response.Name)
+
+	response = dto.MapLocalizedAccommodationResponse(accommodation, "")
+	assert.Equal(t, "sr", response.Locale)
+	assert.Equal(t, "sr", response.DefaultLocale)
+}
+
+func TestValidateAccommodationDto_RejectsDefaultLocaleTranslation(t *testing.T) {
+	accommodationDto := &dto.AccommodationDto{
+		HostId:        "host",
+		Name:          "Seaside villa",
+		Location:      "Split, HR",
+		DefaultLocale: "en",
+		Translations:  map[string]dto.TranslationDto{"en": {Name: "Seaside villa"}},
+		GuestNumber:   dto.GuestNumberDto{Min: 1, Max: 2},
+		DefaultPrice:  dto.DefaultPriceDto{Price: 50, Type: "PerGuest"},
+	}
+
+	assert.Error(t, dto.ValidateAccommodationDto(accommodationDto, nil))
+
+	accommodationDto.Translations = map[string]dto.TranslationDto{"hr": {Name: "Vila uz more"}}
+	assert.NoError(t, dto.ValidateAccommodationDto(accommodationDto, nil))
+
+	accommodationDto.Translations = map[string]dto.TranslationDto{"not a locale!": {Name: "Vila uz more"}}
+	assert.Error(t, dto.ValidateAccommodationDto(accommodationDto, nil))
+}
@@ -0,0 +1,104 @@
+package application_test
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+	"time"
+)
+
+func TestAmenityMemoryStore_MatchesMongoSemantics(t *testing.T) {
+	store := persistence.NewAmenityMemoryStore()
+	require.NoError(t, store.Insert(&domain.Amenity{Code: "wifi", Category: "general"}))
+	require.NoError(t, store.Insert(&domain.Amenity{Code: "bbq", Category: "outdoor"}))
+	require.NoError(t, store.Insert(&domain.Amenity{Code: "ac", Category: "general"}))
+
+	assert.ErrorIs(t, store.Insert(&domain.Amenity{Code: "wifi"}), domain.ErrAmenityExists)
+	require.NoError(t, store.InsertIfMissing(&domain.Amenity{Code: "wifi", Category: "changed"}))
+	assert.ErrorIs(t, store.Update(&domain.Amenity{Code: "pool"}), domain.ErrNotFound)
+	assert.ErrorIs(t, store.Delete("pool"), domain.ErrNotFound)
+
+	all, err := store.GetAll()
+	require.NoError(t, err)
+	codes := []string{}
+	for _, amenity := range all {
+		codes = append(codes, amenity.Code)
+	}
+	assert.Equal(t, []string{"ac", "wifi", "bbq"}, codes)
+	assert.Equal(t, "general", all[1].Category)
+}
+
+func TestAccommodationAuditMemoryStore_PagesNewestFirst(t *testing.T) {
+	store := persistence.NewAccommodationAuditMemoryStore()
+	accommodationId := primitive.NewObjectID()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.Insert(&domain.AccommodationAudit{AccommodationId: accommodationId, Timestamp: start.Add(time.Duration(i) * time.Hour)}))
+	}
+	require.NoError(t, store.Insert(&domain.AccommodationAudit{AccommodationId: primitive.NewObjectID(), Timestamp: start}))
+
+	audits, total, err := store.GetByAccommodationId(accommodationId, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, audits, 2)
+	assert.Equal(t, start.Add(2*time.Hour), audits[0].Timestamp)
+
+	audits, _, err = store.GetByAccommodationId(accommodationId, 3, 2)
+	require.NoError(t, err)
+	assert.Empty(t, audits)
+}
+
+func TestHostDeletionSagaMemoryStore_ReturnsOnlyUnfinished(t *testing.T) {
+	store := persistence.NewHostDeletionSagaMemoryStore()
+	saga := &domain.HostDeletionSaga{HostId: "host-1", Status: domain.HostDeletionChecking}
+	require.NoError(t, store.Insert(saga))
+
+	found, err := store.GetUnfinishedByHostId("host-1")
+	require.NoError(t, err)
+	assert.Equal(t, saga.Id, found.Id)
+
+	saga.Status = domain.HostDeletionCompleted
+	require.NoError(t, store.Update(saga))
+	_, err = store.GetUnfinishedByHostId("host-1")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	unfinished, err := store.GetUnfinished()
+	require.NoError(t, err)
+	assert.Empty(t, unfinished)
+}
+
+func TestHostDeletionSagaMemoryStore_LeaseExcludesConcurrentRunners(t *testing.T) {
+	store := persistence.NewHostDeletionSagaMemoryStore()
+	require.NoError(t, store.Insert(&domain.HostDeletionSaga{HostId: "host-1", Status: domain.HostDeletionChecking}))
+	first, err := store.GetUnfinishedByHostId("host-1")
+	require.NoError(t, err)
+	second, err := store.GetUnfinishedByHostId("host-1")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Lease(first, time.Now().Add(time.Minute)))
+	assert.ErrorIs(t, store.Lease(second, time.Now().Add(time.Minute)), domain.ErrVersionConflict)
+	second.Status = domain.HostDeletionRejected
+	assert.ErrorIs(t, store.Update(second), domain.ErrVersionConflict)
+
+	current, err := store.GetUnfinishedByHostId("host-1")
+	require.NoError(t, err)
+	assert.ErrorIs(t, store.Lease(current, time.Now().Add(time.Minute)), domain.ErrVersionConflict)
+
+	first.LeaseExpiresAt = time.Time{}
+	require.NoError(t, store.Update(first))
+	current, err = store.GetUnfinishedByHostId("host-1")
+	require.NoError(t, err)
+	assert.NoError(t, store.Lease(current, time.Now().Add(time.Minute)))
+}
+
+func TestGeocodeCacheMemoryStore_MissesAfterTTL(t *testing.T) {
+	store := persistence.NewGeocodeCacheMemoryStore(time.Nanosecond)
+	require.NoError(t, store.Put("belgrade", &domain.GeoPoint{}))
+	time.Sleep(time.Millisecond)
+
+	_, err := store.Get("belgrade")
+	assert.ErrorIs(t, err, domain.ErrGeocodeNotCached)
+}
@@ -6,12 +6,14 @@ import (
 	booking "github.com/ZMS-DevOps/booking-service/proto"
 	"github.com/ZMS-DevOps/hotel-service/domain"
 	search "github.com/ZMS-DevOps/search-service/proto"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"time"
 )
 
 // Mock dependencies
@@ -53,34 +55,64 @@ func (m *MockAccommodationStore) Delete(id primitive.ObjectID) error {
 	return args.Error(0)
 }
 
+func (m *MockAccommodationStore) SoftDelete(id primitive.ObjectID, deletedAt time.Time) error {
+	args := m.Called(id, deletedAt)
+	return args.Error(0)
+}
+
+func (m *MockAccommodationStore) Restore(id primitive.ObjectID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAccommodationStore) GetDeleted(id primitive.ObjectID) (*domain.Accommodation, error) {
+	args := m.Called(id)
+	return args.Get(0).(*domain.Accommodation), args.Error(1)
+}
+
+func (m *MockAccommodationStore) GetDeletedBefore(cutoff time.Time) ([]*domain.Accommodation, error) {
+	args := m.Called(cutoff)
+	return args.Get(0).([]*domain.Accommodation), args.Error(1)
+}
+
 func (m *MockAccommodationStore) Update(id primitive.ObjectID, accommodation *domain.Accommodation) error {
 	args := m.Called(id, accommodation)
 	return args.Error(0)
 }
 
-func (m *MockAccommodationStore) UpdateDefaultPrice(id primitive.ObjectID, price *float32) error {
-	args := m.Called(id, price)
+func (m *MockAccommodationStore) UpdateStatus(id primitive.ObjectID, status domain.AccommodationStatus, pendingBookingRegistration bool, version int64) error {
+	args := m.Called(id, status, pendingBookingRegistration, version)
 	return args.Error(0)
 }
 
-func (m *MockAccommodationStore) UpdateSpecialPrice(id primitive.ObjectID, newSpecialPrices []domain.SpecialPrice) error {
-	args := m.Called(id, newSpecialPrices)
+func (m *MockAccommodationStore) UpdatePrices(id primitive.ObjectID, defaultPrice domain.DefaultPrice, specialPrices []domain.SpecialPrice, version int64) error {
+	args := m.Called(id, defaultPrice, specialPrices, version)
 	return args.Error(0)
 }
 
-func (m *MockAccommodationStore) UpdateTypeOfPayment(id primitive.ObjectID, typeOfPayment *string) error {
-	args := m.Called(id, typeOfPayment)
+func (m *MockAccommodationStore) UpdateSpecialPrice(id primitive.ObjectID, newSpecialPrices []domain.SpecialPrice, version int64) error {
+	args := m.Called(id, newSpecialPrices, version)
 	return args.Error(0)
 }
 
-func (m *MockAccommodationStore) GetSpecialPrices(id primitive.ObjectID) ([]domain.SpecialPrice, error) {
-	args := m.Called(id)
-	return args.Get(0).([]domain.SpecialPrice), args.Error(1)
+func (m *MockAccommodationStore) UpdateRoomTypes(id primitive.ObjectID, roomTypes []domain.RoomType, version int64) error {
+	args := m.Called(id, roomTypes, version)
+	return args.Error(0)
 }
 
-func (m *MockAccommodationStore) DeleteByHostId(hostId string) error {
-	args := m.Called(hostId)
-	return args.Error(0)
+func (m *MockAccommodationStore) Search(query domain.AccommodationSearchQuery) ([]*domain.AccommodationSearchHit, int64, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*domain.AccommodationSearchHit), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAccommodationStore) Nearby(query domain.NearbyQuery) ([]*domain.NearbyHit, error) {
+	args := m.Called(query)
+	return args.Get(0).([]*domain.NearbyHit), args.Error(1)
+}
+
+func (m *MockAccommodationStore) CountWithAmenity(code string) (int64, error) {
+	args := m.Called(code)
+	return args.Get(0).(int64), args.Error(1)
 }
 
 type MockBookingServiceClient struct {
@@ -146,6 +178,61 @@ func (m *MockSearchServiceClient) DeleteAccommodation(ctx context.Context, in *s
 	return args.Get(0).(*search.DeleteAccommodationResponse), args.Error(1)
 }
 
+type MockAccommodationAuditStore struct {
+	mock.Mock
+}
+
+func (m *MockAccommodationAuditStore) Insert(audit *domain.AccommodationAudit) error {
+	args := m.Called(audit)
+	return args.Error(0)
+}
+
+func (m *MockAccommodationAuditStore) GetByAccommodationId(accommodationId primitive.ObjectID, page, size int) ([]*domain.AccommodationAudit, int64, error) {
+	args := m.Called(accommodationId, page, size)
+	return args.Get(0).([]*domain.AccommodationAudit), args.Get(1).(int64), args.Error(2)
+}
+
+type MockHostDeletionSagaStore struct {
+	mock.Mock
+}
+
+func (m *MockHostDeletionSagaStore) Insert(saga *domain.HostDeletionSaga) error {
+	args := m.Called(saga)
+	return args.Error(0)
+}
+
+func (m *MockHostDeletionSagaStore) Update(saga *domain.HostDeletionSaga) error {
+	args := m.Called(saga)
+	return args.Error(0)
+}
+
+func (m *MockHostDeletionSagaStore) Lease(saga *domain.HostDeletionSaga, until time.Time) error {
+	args := m.Called(saga, until)
+	return args.Error(0)
+}
+
+func (m *MockHostDeletionSagaStore) GetUnfinishedByHostId(hostId string) (*domain.HostDeletionSaga, error) {
+	args := m.Called(hostId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.HostDeletionSaga), args.Error(1)
+}
+
+func (m *MockHostDeletionSagaStore) GetUnfinished() ([]*domain.HostDeletionSaga, error) {
+	args := m.Called()
+	return args.Get(0).([]*domain.HostDeletionSaga), args.Error(1)
+}
+
+type MockEventProducer struct {
+	mock.Mock
+}
+
+func (m *MockEventProducer) Produce(msg *kafka.Message, deliveryChan chan kafka.Event) error {
+	args := m.Called(msg, deliveryChan)
+	return args.Error(0)
+}
+
 type LokiMock struct {
 	mock.Mock
 }
@@ -217,3 +304,52 @@ func (s *SpanMock) TracerProvider() trace.TracerProvider {
 	args := s.Called()
 	return args.Get(0).(trace.TracerProvider)
 }
+
+type MockGeocoder struct {
+	mock.Mock
+}
+
+func (m *MockGeocoder) Geocode(address *domain.Address) (*domain.GeoPoint, error) {
+	args := m.Called(address)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.GeoPoint), args.Error(1)
+}
+
+type MockAmenityStore struct {
+	mock.Mock
+}
+
+func (m *MockAmenityStore) Get(code string) (*domain.Amenity, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Amenity), args.Error(1)
+}
+
+func (m *MockAmenityStore) GetAll() ([]*domain.Amenity, error) {
+	args := m.Called()
+	return args.Get(0).([]*domain.Amenity), args.Error(1)
+}
+
+func (m *MockAmenityStore) Insert(amenity *domain.Amenity) error {
+	args := m.Called(amenity)
+	return args.Error(0)
+}
+
+func (m *MockAmenityStore) InsertIfMissing(amenity *domain.Amenity) error {
+	args := m.Called(amenity)
+	return args.Error(0)
+}
+
+func (m *MockAmenityStore) Update(amenity *domain.Amenity) error {
+	args := m.Called(amenity)
+	return args.Error(0)
+}
+
+func (m *MockAmenityStore) Delete(code string) error {
+	args := m.Called(code)
+	return args.Error(0)
+}
@@ -0,0 +1,256 @@
+package application_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/api"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/persistence"
+	"github.com/ZMS-DevOps/hotel-service/startup/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRedisAddressEnv = "HOTEL_TEST_REDIS_ADDRESS"
+
+func TestRateLimitMemoryStore_Conformance(t *testing.T) {
+	runRateLimiterConformance(t, func(t *testing.T) domain.RateLimiter {
+		return persistence.NewRateLimitMemoryStore()
+	})
+}
+
+func TestRateLimitRedisStore_Conformance(t *testing.T) {
+	address := os.Getenv(testRedisAddressEnv)
+	if address == "" {
+		t.Skipf("%s not set", testRedisAddressEnv)
+	}
+	client := redis.NewClient(&redis.Options{Addr: address})
+	require.NoError(t, client.Ping(context.Background()).Err())
+	defer client.Close()
+
+	runRateLimiterConformance(t, func(t *testing.T) domain.RateLimiter {
+		return persistence.NewRateLimitRedisStore(client, time.Second)
+	})
+}
+
+func runRateLimiterConformance(t *testing.T, newLimiter func(t *testing.T) domain.RateLimiter) {
+	t.Run("AllowsBurstThenRejects", func(t *testing.T) {
+		limiter := newLimiter(t)
+		key := t.Name() + strconv.FormatInt(time.Now().UnixNano(), 10)
+		limit := domain.RateLimit{PerMinute: 60, Burst: 2}
+
+		first, _, err := limiter.Allow(key, limit)
+		require.NoError(t, err)
+		second, _, err := limiter.Allow(key, limit)
+		require.NoError(t, err)
+		third, retryAfter, err := limiter.Allow(key, limit)
+		require.NoError(t, err)
+
+		assert.True(t, first)
+		assert.True(t, second)
+		assert.False(t, third)
+		assert.Greater(t, retryAfter, time.Duration(0))
+		assert.LessOrEqual(t, retryAfter, time.Second)
+	})
+
+	t.Run("RefillsOverTime", func(t *testing.T) {
+		limiter := newLimiter(t)
+		key := t.Name() + strconv.FormatInt(time.Now().UnixNano(), 10)
+		limit := domain.RateLimit{PerMinute: 1200, Burst: 1}
+
+		first, _, err := limiter.Allow(key, limit)
+		require.NoError(t, err)
+		second, _, err := limiter.Allow(key, limit)
+		require.NoError(t, err)
+		time.Sleep(60 * time.Millisecond)
+		third, _, err := limiter.Allow(key, limit)
+		require.NoError(t, err)
+
+		assert.True(t, first)
+		assert.False(t, second)
+		assert.True(t, third)
+	})
+
+	t.Run("KeysAreIndependent", func(t *testing.T) {
+		limiter := newLimiter(t)
+		suffix := strconv.FormatInt(time.Now().UnixNano(), 10)
+		limit := domain.RateLimit{PerMinute: 60, Burst: 1}
+
+		first, _, err := limiter.Allow("user:a"+suffix, limit)
+		require.NoError(t, err)
+		second, _, err := limiter.Allow("user:b"+suffix, limit)
+		require.NoError(t, err)
+
+		assert.True(t, first)
+		assert.True(t, second)
+	})
+}
+
+type failingRateLimiter struct{}
+
+func (failingRateLimiter) Allow(string, domain.RateLimit) (bool, time.Duration, error) {
+	return false, 0, errors.New("connection refused")
+}
+
+func rateLimitBudgets(reads, uploads domain.RateLimit) map[string]api.RequestBudget {
+	return map[string]api.RequestBudget{
+		api.ReadBudget:   {Limit: reads, MaxBodyBytes: 1 << 10},
+		api.WriteBudget:  {Limit: domain.RateLimit{PerMinute: 60, Burst: 10}, MaxBodyBytes: 1 << 10},
+		api.UploadBudget: {Limit: uploads, MaxBodyBytes: 1 << 12},
+	}
+}
+
+func newRateLimitedHarness(t *testing.T, limiter domain.RateLimiter, reads, uploads domain.RateLimit, trustedHops int) *handlerHarness {
+	harness := newHandlerHarness(t)
+	harness.router.Use(api.NewRateLimits(limiter, rateLimitBudgets(reads, uploads), trustedHops).Middleware)
+	return harness
+}
+
+func TestRateLimits_SeparateBudgetsForUploadsAndReads(t *testing.T) {
+	harness := newRateLimitedHarness(t, persistence.NewRateLimitMemoryStore(), domain.RateLimit{PerMinute: 60, Burst: 5}, domain.RateLimit{PerMinute: 1, Burst: 1}, 0)
+	photos := map[string]string{"front.jpg": "jpeg-bytes"}
+
+	first := harness.serve(asSubject(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, photos), "host-1"))
+	second := harness.serve(asSubject(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, photos), "host-1"))
+	read := harness.serve(asSubject(httptest.NewRequest(http.MethodGet, "/accommodation", nil), "host-1"))
+
+	require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	retryAfter, err := strconv.Atoi(second.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 60, retryAfter, 1)
+	assert.Equal(t, http.StatusOK, read.Code)
+	accommodations, err := harness.store.GetByHostId("host-1")
+	require.NoError(t, err)
+	assert.Len(t, accommodations, 1)
+}
+
+func TestRateLimits_KeyedBySubjectThenClientIP(t *testing.T) {
+	harness := newRateLimitedHarness(t, persistence.NewRateLimitMemoryStore(), domain.RateLimit{PerMinute: 1, Burst: 1}, domain.RateLimit{PerMinute: 1, Burst: 1}, 0)
+	fromAddress := func(address string) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, "/accommodation", nil)
+		request.RemoteAddr = address
+		return request
+	}
+
+	assert.Equal(t, http.StatusOK, harness.serve(asSubject(fromAddress("10.0.0.1:1000"), "user-1")).Code)
+	assert.Equal(t, http.StatusTooManyRequests, harness.serve(asSubject(fromAddress("10.0.0.2:1000"), "user-1")).Code)
+	assert.Equal(t, http.StatusOK, harness.serve(asSubject(fromAddress("10.0.0.1:1000"), "user-2")).Code)
+	assert.Equal(t, http.StatusOK, harness.serve(fromAddress("10.0.0.1:1000")).Code)
+	assert.Equal(t, http.StatusTooManyRequests, harness.serve(fromAddress("10.0.0.1:2000")).Code)
+	assert.Equal(t, http.StatusOK, harness.serve(fromAddress("10.0.0.2:1000")).Code)
+}
+
+func TestRateLimits_TrustsLastForwardedAddress(t *testing.T) {
+	harness := newRateLimitedHarness(t, persistence.NewRateLimitMemoryStore(), domain.RateLimit{PerMinute: 1, Burst: 1}, domain.RateLimit{PerMinute: 1, Burst: 1}, 1)
+	forwardedFor := func(addresses string) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, "/accommodation", nil)
+		request.Header.Set("X-Forwarded-For", addresses)
+		return request
+	}
+
+	assert.Equal(t, http.StatusOK, harness.serve(forwardedFor("1.1.1.1, 203.0.113.7")).Code)
+	assert.Equal(t, http.StatusTooManyRequests, harness.serve(forwardedFor("2.2.2.2, 203.0.113.7")).Code)
+	assert.Equal(t, http.StatusOK, harness.serve(forwardedFor("203.0.113.8")).Code)
+}
+
+func TestRateLimits_SkipsHopsAddedBeforeTrustedProxies(t *testing.T) {
+	harness := newRateLimitedHarness(t, persistence.NewRateLimitMemoryStore(), domain.RateLimit{PerMinute: 1, Burst: 1}, domain.RateLimit{PerMinute: 1, Burst: 1}, 2)
+	forwardedFor := func(addresses string) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, "/accommodation", nil)
+		request.Header.Set("X-Forwarded-For", addresses)
+		return request
+	}
+
+	assert.Equal(t, http.StatusOK, harness.serve(forwardedFor("1.1.1.1, 203.0.113.7, 10.0.0.5")).Code)
+	assert.Equal(t, http.StatusTooManyRequests, harness.serve(forwardedFor("203.0.113.7, 10.0.0.6")).Code)
+	assert.Equal(t, http.StatusOK, harness.serve(forwardedFor("203.0.113.8, 10.0.0.5")).Code)
+}
+
+func TestRateLimits_IgnoresSubjectWithoutMatchingToken(t *testing.T) {
+	harness := newRateLimitedHarness(t, persistence.NewRateLimitMemoryStore(), domain.RateLimit{PerMinute: 1, Burst: 1}, domain.RateLimit{PerMinute: 1, Burst: 1}, 0)
+	spoofed := func(subject string) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, "/accommodation", nil)
+		request.RemoteAddr = "10.0.0.1:1000"
+		request.Header.Set("x-jwt-payload", base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"`+subject+`"}`)))
+		return request
+	}
+	mismatched := asSubject(spoofed("user-3"), "user-3")
+	mismatched.Header.Set("x-jwt-payload", base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user-4"}`)))
+
+	assert.Equal(t, http.StatusOK, harness.serve(spoofed("user-1")).Code)
+	assert.Equal(t, http.StatusTooManyRequests, harness.serve(spoofed("user-2")).Code)
+	assert.Equal(t, http.StatusTooManyRequests, harness.serve(mismatched).Code)
+}
+
+func TestRateLimits_HealthCheckIsUnlimited(t *testing.T) {
+	harness := newRateLimitedHarness(t, persistence.NewRateLimitMemoryStore(), domain.RateLimit{PerMinute: 1, Burst: 1}, domain.RateLimit{PerMinute: 1, Burst: 1}, 0)
+
+	for i := 0; i < 3; i++ {
+		assert.NotEqual(t, http.StatusTooManyRequests, harness.serve(httptest.NewRequest(http.MethodGet, "/accommodation/health", nil)).Code)
+	}
+}
+
+func TestRateLimits_AllowsRequestsWhenLimiterFails(t *testing.T) {
+	harness := newRateLimitedHarness(t, failingRateLimiter{}, domain.RateLimit{PerMinute: 1, Burst: 1}, domain.RateLimit{PerMinute: 1, Burst: 1}, 0)
+
+	assert.Equal(t, http.StatusOK, harness.serve(httptest.NewRequest(http.MethodGet, "/accommodation", nil)).Code)
+}
+
+func TestRateLimits_RejectsOversizedBodies(t *testing.T) {
+	harness := newRateLimitedHarness(t, persistence.NewRateLimitMemoryStore(), domain.RateLimit{PerMinute: 60, Burst: 10}, domain.RateLimit{PerMinute: 60, Burst: 10}, 0)
+	accommodation := harness.insertPublished(t)
+	largePhoto := map[string]string{"front.jpg": strings.Repeat("x", 1<<13)}
+
+	declared := harness.serve(asSubject(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, largePhoto), "host-1"))
+	streamed := asSubject(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, largePhoto), "host-1")
+	streamed.ContentLength = -1
+	streamedResponse := harness.serve(streamed)
+	price := asSubject(jsonRequest(http.MethodPut, "/accommodation/price/"+accommodation.Id.Hex(), `{"price": 150, "type": "PerGuest", "padding": "`+strings.Repeat("x", 1<<11)+`"}`), "host-1")
+	price.ContentLength = -1
+	price.Header.Set("If-Match", `"1"`)
+	priceResponse := harness.serve(price)
+	smallUpload := harness.serve(asSubject(multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, map[string]string{"front.jpg": strings.Repeat("x", 1<<11)}), "host-1"))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, declared.Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, streamedResponse.Code, streamedResponse.Body.String())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, priceResponse.Code, priceResponse.Body.String())
+	assert.Equal(t, http.StatusCreated, smallUpload.Code, smallUpload.Body.String())
+	assert.Len(t, harness.bookingServer.AddUnavailabilityCalls(), 1)
+}
+
+func TestRateLimits_IdempotentRequestBodyLimit(t *testing.T) {
+	harness := newRateLimitedHarness(t, persistence.NewRateLimitMemoryStore(), domain.RateLimit{PerMinute: 60, Burst: 10}, domain.RateLimit{PerMinute: 60, Burst: 10}, 0)
+	request := multipartRequest(t, http.MethodPost, "/accommodation", validAccommodationJson, map[string]string{"front.jpg": strings.Repeat("x", 1<<13)})
+	request.ContentLength = -1
+
+	response := harness.serve(withIdempotencyKey(request, "create-1"))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+}
+
+func TestConfig_RateLimitValidation(t *testing.T) {
+	env := validEnv()
+	env["RATE_LIMITER"] = config.RedisRateLimiter
+	env["RATE_LIMIT_UPLOADS_BURST"] = "0"
+	env["MAX_UPLOAD_BODY_BYTES"] = "-1"
+
+	_, err := config.Load(lookupEnv(env))
+
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.ElementsMatch(t, []string{
+		"REDIS_ADDRESS is required",
+		"RATE_LIMIT_UPLOADS_BURST must be at least 1 when RATE_LIMIT_UPLOADS_PER_MINUTE is set, got 0",
+		"MAX_UPLOAD_BODY_BYTES must not be negative, got -1",
+	}, validationErr.Problems)
+}
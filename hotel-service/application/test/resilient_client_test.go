@@ -0,0 +1,223 @@
+package application_test
+
+import (
+	"context"
+	booking "github.com/ZMS-DevOps/booking-service/proto"
+	"github.com/ZMS-DevOps/hotel-service/application/external"
+	application "github.com/ZMS-DevOps/hotel-service/application/test"
+	search "github.com/ZMS-DevOps/search-service/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testResilienceConfig() external.ResilienceConfig {
+	return external.ResilienceConfig{
+		Timeout:          time.Second,
+		MaxRetries:       2,
+		BaseBackoff:      time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		FailureThreshold: 5,
+		OpenTimeout:      time.Minute,
+	}
+}
+
+func startResilientClients(t *testing.T, config external.ResilienceConfig) (*application.FakeBookingServer, *application.FakeSearchServer, booking.BookingServiceClient, search.SearchServiceClient) {
+	bookingServer := application.NewFakeBookingServer()
+	searchServer := application.NewFakeSearchServer()
+	bookingClient, searchClient := application.StartFakeGrpcServers(t, bookingServer, searchServer,
+		grpc.WithUnaryInterceptor(external.NewResilienceInterceptor(t.Name(), config)))
+	return bookingServer, searchServer, bookingClient, searchClient
+}
+
+func TestResilientClient_RetriesIdempotentCall(t *testing.T) {
+	_, searchServer, _, searchClient := startResilientClients(t, testResilienceConfig())
+	searchServer.FailNext(codes.Unavailable, codes.Unavailable)
+
+	_, err := searchClient.EditAccommodation(context.Background(), &search.EditAccommodationRequest{Accommodation: &search.Accommodation{AccommodationId: "acc-1"}})
+
+	require.NoError(t, err)
+	assert.Len(t, searchServer.EditAccommodationRequests, 3)
+}
+
+func TestResilientClient_GivesUpAfterMaxRetries(t *testing.T) {
+	_, searchServer, _, searchClient := startResilientClients(t, testResilienceConfig())
+	searchServer.FailNext(codes.Unavailable, codes.Unavailable, codes.Unavailable, codes.Unavailable)
+
+	_, err := searchClient.DeleteAccommodation(context.Background(), &search.DeleteAccommodationRequest{AccommodationId: "acc-1"})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Len(t, searchServer.DeleteAccommodationRequests, 3)
+}
+
+func TestResilientClient_DoesNotRetryNonIdempotentCall(t *testing.T) {
+	bookingServer, searchServer, bookingClient, searchClient := startResilientClients(t, testResilienceConfig())
+	bookingServer.FailNext(codes.Unavailable)
+	searchServer.FailNext(codes.Unavailable)
+
+	_, bookingErr := bookingClient.AddUnavailability(context.Background(), &booking.AddUnavailabilityRequest{Id: "acc-1"})
+	_, searchErr := searchClient.AddAccommodation(context.Background(), &search.AddAccommodationRequest{Accommodation: &search.Accommodation{AccommodationId: "acc-1"}})
+
+	assert.Equal(t, codes.Unavailable, status.Code(bookingErr))
+	assert.Equal(t, codes.Unavailable, status.Code(searchErr))
+	assert.Len(t, bookingServer.AddUnavailabilityCalls(), 1)
+	assert.Len(t, searchServer.AddAccommodationRequests, 1)
+}
+
+func TestResilientClient_DoesNotRetryPermanentErrors(t *testing.T) {
+	_, searchServer, _, searchClient := startResilientClients(t, testResilienceConfig())
+	searchServer.FailNext(codes.InvalidArgument)
+
+	_, err := searchClient.EditAccommodation(context.Background(), &search.EditAccommodationRequest{Accommodation: &search.Accommodation{AccommodationId: "acc-1"}})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Len(t, searchServer.EditAccommodationRequests, 1)
+}
+
+func TestResilientClient_MethodTimeout(t *testing.T) {
+	config := testResilienceConfig()
+	config.MaxRetries = 0
+	config.MethodTimeouts = map[string]time.Duration{"/search.SearchService/AddAccommodation": 20 * time.Millisecond}
+	_, searchServer, _, searchClient := startResilientClients(t, config)
+	searchServer.SetDelay(200 * time.Millisecond)
+
+	start := time.Now()
+	_, err := searchClient.AddAccommodation(context.Background(), &search.AddAccommodationRequest{Accommodation: &search.Accommodation{AccommodationId: "acc-1"}})
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestResilientClient_RetryDeadlineBoundsAllAttempts(t *testing.T) {
+	config := testResilienceConfig()
+	config.MaxRetries = 5
+	config.BaseBackoff = 100 * time.Millisecond
+	config.MaxBackoff = 100 * time.Millisecond
+	config.RetryDeadline = 60 * time.Millisecond
+	_, searchServer, _, searchClient := startResilientClients(t, config)
+	searchServer.FailNext(codes.Unavailable, codes.Unavailable, codes.Unavailable, codes.Unavailable, codes.Unavailable, codes.Unavailable)
+
+	start := time.Now()
+	_, err := searchClient.EditAccommodation(context.Background(), &search.EditAccommodationRequest{Accommodation: &search.Accommodation{AccommodationId: "acc-1"}})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+	assert.Less(t, len(searchServer.EditAccommodationRequests), 6)
+}
+
+func TestResilientClient_StopsRetryingWhenCallerCancels(t *testing.T) {
+	config := testResilienceConfig()
+	config.MaxRetries = 5
+	config.BaseBackoff = 200 * time.Millisecond
+	config.MaxBackoff = 200 * time.Millisecond
+	_, searchServer, _, searchClient := startResilientClients(t, config)
+	searchServer.FailNext(codes.Unavailable, codes.Unavailable, codes.Unavailable, codes.Unavailable, codes.Unavailable, codes.Unavailable)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := searchClient.EditAccommodation(ctx, &search.EditAccommodationRequest{Accommodation: &search.Accommodation{AccommodationId: "acc-1"}})
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+	assert.Less(t, len(searchServer.EditAccommodationRequests), 6)
+}
+
+func TestResilientClient_BackoffDoesNotOverflow(t *testing.T) {
+	config := testResilienceConfig()
+	config.MaxRetries = 70
+	config.FailureThreshold = 100
+	config.BaseBackoff = time.Nanosecond
+	config.MaxBackoff = time.Microsecond
+	_, searchServer, _, searchClient := startResilientClients(t, config)
+	failures := make([]codes.Code, 70)
+	for i := range failures {
+		failures[i] = codes.Unavailable
+	}
+	searchServer.FailNext(failures...)
+
+	_, err := searchClient.EditAccommodation(context.Background(), &search.EditAccommodationRequest{Accommodation: &search.Accommodation{AccommodationId: "acc-1"}})
+
+	require.NoError(t, err)
+	assert.Len(t, searchServer.EditAccommodationRequests, 71)
+}
+
+func TestResilientClient_CircuitBreakerOpensAndRecovers(t *testing.T) {
+	config := testResilienceConfig()
+	config.MaxRetries = 0
+	config.FailureThreshold = 2
+	config.OpenTimeout = 50 * time.Millisecond
+	bookingServer, _, bookingClient, _ := startResilientClients(t, config)
+	bookingServer.FailNext(codes.Unavailable, codes.Unavailable)
+	request := &booking.AddUnavailabilityRequest{Id: "acc-1"}
+
+	for i := 0; i < 2; i++ {
+		_, err := bookingClient.AddUnavailability(context.Background(), request)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	}
+	_, err := bookingClient.AddUnavailability(context.Background(), request)
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Contains(t, err.Error(), external.ErrCircuitOpen.Error())
+	assert.Len(t, bookingServer.AddUnavailabilityCalls(), 2)
+
+	time.Sleep(config.OpenTimeout)
+	_, err = bookingClient.AddUnavailability(context.Background(), request)
+	require.NoError(t, err)
+	_, err = bookingClient.AddUnavailability(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Len(t, bookingServer.AddUnavailabilityCalls(), 4)
+}
+
+func TestResilientClient_RecordsTraceEvents(t *testing.T) {
+	_, searchServer, _, searchClient := startResilientClients(t, testResilienceConfig())
+	searchServer.FailNext(codes.Unavailable)
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	lokiMock := &application.LokiMock{}
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	_, span := provider.Tracer("test").Start(context.Background(), "edit")
+
+	_, err := external.EditSearchAccommodation(context.Background(), searchClient, &search.Accommodation{AccommodationId: "acc-1"}, span, lokiMock)
+	span.End()
+
+	require.NoError(t, err)
+	require.Len(t, recorder.Ended(), 1)
+	var names []string
+	for _, event := range recorder.Ended()[0].Events() {
+		if strings.HasPrefix(event.Name, "grpc.") {
+			names = append(names, event.Name)
+		}
+	}
+	assert.Equal(t, []string{"grpc.attempt", "grpc.retry", "grpc.attempt"}, names)
+}
+
+func TestCircuitBreaker_HalfOpenAllowsSingleProbe(t *testing.T) {
+	breaker := external.NewCircuitBreaker(1, 10*time.Millisecond)
+
+	require.NoError(t, breaker.Allow())
+	breaker.Record(false)
+	assert.Equal(t, external.CircuitOpen, breaker.State())
+	assert.ErrorIs(t, breaker.Allow(), external.ErrCircuitOpen)
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, external.CircuitHalfOpen, breaker.State())
+	require.NoError(t, breaker.Allow())
+	assert.ErrorIs(t, breaker.Allow(), external.ErrCircuitOpen)
+	breaker.Record(false)
+	assert.Equal(t, external.CircuitOpen, breaker.State())
+
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, breaker.Allow())
+	breaker.Record(true)
+	assert.Equal(t, external.CircuitClosed, breaker.State())
+	assert.NoError(t, breaker.Allow())
+}
@@ -0,0 +1,171 @@
+package application_test
+
+import (
+	"context"
+	booking "github.com/ZMS-DevOps/booking-service/proto"
+	application2 "github.com/ZMS-DevOps/hotel-service/application"
+	"github.com/ZMS-DevOps/hotel-service/application/test"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	search "github.com/ZMS-DevOps/search-service/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+)
+
+func TestAccommodationService_AddRoomType_RegistersRoomWithBooking(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationId := primitive.NewObjectID()
+	accommodation := &domain.Accommodation{
+		Id:           accommodationId,
+		Name:         "Grand hotel",
+		HostId:       "host",
+		Status:       domain.Published,
+		GuestNumber:  domain.GuestNumber{Min: 1, Max: 2},
+		DefaultPrice: domain.DefaultPrice{Price: 120, Type: domain.PerApartmentUnit},
+		Version:      4,
+	}
+	roomType := &domain.RoomType{
+		Name:         "Family suite",
+		Capacity:     domain.GuestNumber{Min: 2, Max: 5},
+		Beds:         []domain.BedConfiguration{{Type: "king", Count: 1}, {Type: "sofa", Count: 1}},
+		UnitCount:    3,
+		DefaultPrice: domain.DefaultPrice{Price: 90, Type: domain.PerGuest},
+	}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockAuditStore.On("Insert", mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationId).Return(accommodation, nil)
+	mockStore.On("UpdateRoomTypes", accommodationId, mock.Anything, int64(4)).Return(nil)
+	mockBookingClient.On("AddUnavailability", mock.Anything, mock.MatchedBy(func(request *booking.AddUnavailabilityRequest) bool {
+		return request.Id == roomType.Id.Hex() && request.AccommodationName == "Grand hotel - Family suite"
+	}), mock.Anything).Return(&booking.AddUnavailabilityResponse{}, nil)
+	mockSearchClient.On("EditAccommodation", mock.Anything, mock.MatchedBy(func(request *search.EditAccommodationRequest) bool {
+		return request.Accommodation.DefaultPrice == 90 && request.Accommodation.MinGuestNumber == 2 && request.Accommodation.MaxGuestNumber == 5
+	}), mock.Anything).Return(&search.EditAccommodationResponse{}, nil)
+
+	updated, err := service.AddRoomType(context.Background(), accommodationId, roomType, 4, actor, spanMock, lokiMock)
+
+	assert.NoError(t, err)
+	assert.False(t, roomType.Id.IsZero())
+	assert.Len(t, updated.RoomTypes, 1)
+	assert.Equal(t, int64(5), updated.Version)
+	mockBookingClient.AssertExpectations(t)
+	mockSearchClient.AssertExpectations(t)
+}
+
+func TestAccommodationService_DeleteRoomType_WithReservations(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationId, roomTypeId := primitive.NewObjectID(), primitive.NewObjectID()
+	accommodation := &domain.Accommodation{
+		Id:        accommodationId,
+		Status:    domain.Published,
+		RoomTypes: []domain.RoomType{{Id: roomTypeId, Name: "Double room"}},
+		Version:   2,
+	}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationId).Return(accommodation, nil)
+	mockBookingClient.On("CheckAccommodationHasReservation", mock.Anything, &booking.CheckAccommodationHasReservationRequest{AccommodationId: roomTypeId.Hex()}, mock.Anything).Return(&booking.CheckAccommodationHasReservationResponse{Success: false}, nil)
+
+	_, err := service.DeleteRoomType(context.Background(), accommodationId, roomTypeId, 0, actor, spanMock, lokiMock)
+
+	assert.ErrorIs(t, err, domain.ErrHasReservations)
+	mockStore.AssertNotCalled(t, "UpdateRoomTypes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccommodationService_UpdateRoomType_NotFound(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationId := primitive.NewObjectID()
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationId).Return(&domain.Accommodation{Id: accommodationId}, nil)
+
+	_, err := service.UpdateRoomType(context.Background(), accommodationId, primitive.NewObjectID(), &domain.RoomType{Name: "Ghost room"}, 0, actor, spanMock, lokiMock)
+
+	assert.ErrorIs(t, err, domain.ErrRoomTypeNotFound)
+}
+
+func TestAccommodationService_AddRoomType_BookingFailureSavesNothing(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationId := primitive.NewObjectID()
+	accommodation := &domain.Accommodation{Id: accommodationId, Name: "Grand hotel", Status: domain.Published, Version: 4}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	lokiMock.On("Errorf", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("RecordError", mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("SetStatus", mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationId).Return(accommodation, nil)
+	mockBookingClient.On("AddUnavailability", mock.Anything, mock.Anything, mock.Anything).Return((*booking.AddUnavailabilityResponse)(nil), assert.AnError)
+
+	_, err := service.AddRoomType(context.Background(), accommodationId, &domain.RoomType{Name: "Family suite"}, 4, actor, spanMock, lokiMock)
+
+	assert.Error(t, err)
+	mockStore.AssertNotCalled(t, "UpdateRoomTypes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccommodationService_UpdateRoomType_RollsBackBookingWhenSaveFails(t *testing.T) {
+	mockStore := new(application.MockAccommodationStore)
+	mockAuditStore := new(application.MockAccommodationAuditStore)
+	mockBookingClient := new(application.MockBookingServiceClient)
+	mockSearchClient := new(application.MockSearchServiceClient)
+	lokiMock := new(application.LokiMock)
+	spanMock := new(application.SpanMock)
+	service := application2.NewAccommodationService(mockStore, mockAuditStore, mockBookingClient, mockSearchClient, nil, lokiMock)
+
+	accommodationId, roomTypeId := primitive.NewObjectID(), primitive.NewObjectID()
+	accommodation := &domain.Accommodation{
+		Id:        accommodationId,
+		Name:      "Grand hotel",
+		Status:    domain.Published,
+		RoomTypes: []domain.RoomType{{Id: roomTypeId, Name: "Double room"}},
+		Version:   2,
+	}
+
+	lokiMock.On("Infof", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	spanMock.On("AddEvent", mock.Anything, mock.Anything).Return(nil)
+	mockStore.On("Get", accommodationId).Return(accommodation, nil)
+	mockStore.On("UpdateRoomTypes", accommodationId, mock.Anything, int64(2)).Return(domain.ErrVersionConflict)
+	var bookingNames []string
+	mockBookingClient.On("EditAccommodation", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		bookingNames = append(bookingNames, args.Get(1).(*booking.EditAccommodationRequest).AccommodationName)
+	}).Return(&booking.EditAccommodationResponse{}, nil)
+
+	_, err := service.UpdateRoomType(context.Background(), accommodationId, roomTypeId, &domain.RoomType{Name: "Twin room"}, 2, actor, spanMock, lokiMock)
+
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+	assert.Equal(t, []string{"Grand hotel - Twin room", "Grand hotel - Double room"}, bookingNames)
+	mockSearchClient.AssertNotCalled(t, "EditAccommodation", mock.Anything, mock.Anything, mock.Anything)
+}
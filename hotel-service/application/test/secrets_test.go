@@ -0,0 +1,139 @@
+package application_test
+
+import (
+	"errors"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/persistence"
+	"github.com/ZMS-DevOps/hotel-service/startup/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSecretFile(t *testing.T, path string, value string) {
+	require.NoError(t, os.WriteFile(path, []byte(value), 0600))
+}
+
+func secretEnv(t *testing.T) (map[string]string, string, string) {
+	dir := t.TempDir()
+	mongoPassword := filepath.Join(dir, "mongo-password")
+	kafkaPassword := filepath.Join(dir, "kafka-password")
+	writeSecretFile(t, mongoPassword, "mongo-secret\n")
+	writeSecretFile(t, kafkaPassword, "kafka-secret")
+	env := validEnv()
+	env["MONGO_INITDB_ROOT_PASSWORD_FILE"] = mongoPassword
+	env["KAFKA_AUTH_PASSWORD_FILE"] = kafkaPassword
+	return env, mongoPassword, kafkaPassword
+}
+
+func TestConfig_LoadsSecretsFromFiles(t *testing.T) {
+	env, mongoPassword, kafkaPassword := secretEnv(t)
+
+	loaded, err := config.Load(lookupEnv(env))
+
+	require.NoError(t, err)
+	assert.Equal(t, "mongo-secret", loaded.HotelDBPassword)
+	assert.Equal(t, "kafka-secret", loaded.KafkaAuthPassword)
+	assert.Equal(t, map[string]string{"MONGO_INITDB_ROOT_PASSWORD": mongoPassword, "KAFKA_AUTH_PASSWORD": kafkaPassword}, loaded.Files())
+	assert.Equal(t, "[REDACTED]", loaded.Redacted()["db_password"])
+}
+
+func TestConfig_SecretFileProblems(t *testing.T) {
+	env, _, _ := secretEnv(t)
+	env["KAFKA_AUTH_PASSWORD"] = "inline"
+	env["MONGO_INITDB_ROOT_PASSWORD_FILE"] = filepath.Join(t.TempDir(), "missing")
+
+	_, err := config.Load(lookupEnv(env))
+
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Len(t, validationErr.Problems, 2)
+	assert.Contains(t, validationErr.Problems[0], "MONGO_INITDB_ROOT_PASSWORD_FILE")
+	assert.Equal(t, "KAFKA_AUTH_PASSWORD: set either KAFKA_AUTH_PASSWORD or KAFKA_AUTH_PASSWORD_FILE, not both", validationErr.Problems[1])
+}
+
+func TestSecretWatcher_NotifiesListenersOfRotatedKeys(t *testing.T) {
+	env, mongoPassword, _ := secretEnv(t)
+	loaded, err := config.Load(lookupEnv(env))
+	require.NoError(t, err)
+	watcher := config.NewSecretWatcher(loaded)
+	var mongoRotations, kafkaRotations []string
+	watcher.OnRotation("mongo", func(updated *config.Config) error {
+		mongoRotations = append(mongoRotations, updated.HotelDBPassword)
+		return nil
+	}, "MONGO_INITDB_ROOT_USERNAME", "MONGO_INITDB_ROOT_PASSWORD")
+	watcher.OnRotation("kafka", func(updated *config.Config) error {
+		kafkaRotations = append(kafkaRotations, updated.KafkaAuthPassword)
+		return nil
+	}, "KAFKA_AUTH_PASSWORD")
+
+	require.NoError(t, watcher.Poll())
+	writeSecretFile(t, mongoPassword, "rotated-secret\n")
+	require.NoError(t, watcher.Poll())
+	require.NoError(t, watcher.Poll())
+
+	assert.Equal(t, []string{"rotated-secret"}, mongoRotations)
+	assert.Empty(t, kafkaRotations)
+	assert.Equal(t, "rotated-secret", watcher.Current().HotelDBPassword)
+	assert.Equal(t, "mongo-secret", loaded.HotelDBPassword)
+}
+
+func TestSecretWatcher_RetriesFailedListeners(t *testing.T) {
+	env, _, kafkaPassword := secretEnv(t)
+	loaded, err := config.Load(lookupEnv(env))
+	require.NoError(t, err)
+	watcher := config.NewSecretWatcher(loaded)
+	failures := 1
+	var consumerCalls, producerCalls int
+	watcher.OnRotation("kafka consumer", func(updated *config.Config) error {
+		consumerCalls++
+		if failures > 0 {
+			failures--
+			return errors.New("broker unreachable")
+		}
+		return nil
+	}, "KAFKA_AUTH_PASSWORD")
+	watcher.OnRotation("kafka producer", func(updated *config.Config) error {
+		producerCalls++
+		return nil
+	}, "KAFKA_AUTH_PASSWORD")
+
+	writeSecretFile(t, kafkaPassword, "rotated-secret")
+	err = watcher.Poll()
+	assert.ErrorContains(t, err, "kafka consumer: broker unreachable")
+	require.NoError(t, watcher.Poll())
+	require.NoError(t, watcher.Poll())
+
+	assert.Equal(t, 2, consumerCalls)
+	assert.Equal(t, 1, producerCalls)
+}
+
+func TestSecretWatcher_KeepsCurrentValuesWhenFileIsUnreadable(t *testing.T) {
+	env, mongoPassword, _ := secretEnv(t)
+	loaded, err := config.Load(lookupEnv(env))
+	require.NoError(t, err)
+	watcher := config.NewSecretWatcher(loaded)
+	require.NoError(t, os.Remove(mongoPassword))
+
+	assert.Error(t, watcher.Poll())
+	assert.Equal(t, "mongo-secret", watcher.Current().HotelDBPassword)
+}
+
+func TestReloadableClient_SwapsClient(t *testing.T) {
+	first, err := mongo.NewClient(options.Client().ApplyURI("mongodb://first:27017"))
+	require.NoError(t, err)
+	second, err := mongo.NewClient(options.Client().ApplyURI("mongodb://second:27017"))
+	require.NoError(t, err)
+	reloadable := persistence.NewReloadableClient(first, time.Second)
+
+	database := reloadable.Database(persistence.DATABASE)
+	reloadable.Swap(second)
+
+	assert.Same(t, first, database.Client())
+	assert.Same(t, second, reloadable.Client())
+	assert.Same(t, second, reloadable.Database(persistence.DATABASE).Client())
+}
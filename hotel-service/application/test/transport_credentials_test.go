@@ -0,0 +1,195 @@
+package application_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/ZMS-DevOps/hotel-service/application/external"
+	application "github.com/ZMS-DevOps/hotel-service/application/test"
+	search "github.com/ZMS-DevOps/search-service/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/test/bufconn"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testServerName = "search.test"
+
+type testAuthority struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pem         []byte
+}
+
+func newTestAuthority(t *testing.T, name string) *testAuthority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testAuthority{certificate: certificate, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (authority *testAuthority) issue(t *testing.T, name string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, authority.certificate, &key.PublicKey, authority.key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func writeTestFile(t *testing.T, path string, content []byte, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, content, 0600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+type transportFiles struct {
+	ca   string
+	cert string
+	key  string
+}
+
+func writeTransportFiles(t *testing.T, dir string, authority *testAuthority, name string, usage x509.ExtKeyUsage, modTime time.Time) transportFiles {
+	files := transportFiles{ca: filepath.Join(dir, "ca.pem"), cert: filepath.Join(dir, "tls.crt"), key: filepath.Join(dir, "tls.key")}
+	certificate, key := authority.issue(t, name, usage)
+	writeTestFile(t, files.ca, authority.pem, modTime)
+	writeTestFile(t, files.cert, certificate, modTime)
+	writeTestFile(t, files.key, key, modTime)
+	return files
+}
+
+// startSecureSearchServer reads the server certificate on every handshake so tests can rotate it.
+func startSecureSearchServer(t *testing.T, files transportFiles, requireClientCertificate bool) *bufconn.Listener {
+	config := &tls.Config{GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		certificate, err := tls.LoadX509KeyPair(files.cert, files.key)
+		if err != nil {
+			return nil, err
+		}
+		serverConfig := &tls.Config{MinVersion: tls.VersionTLS12, NextProtos: []string{"h2"}, Certificates: []tls.Certificate{certificate}}
+		if requireClientCertificate {
+			authority, err := os.ReadFile(files.ca)
+			if err != nil {
+				return nil, err
+			}
+			serverConfig.ClientCAs = x509.NewCertPool()
+			serverConfig.ClientCAs.AppendCertsFromPEM(authority)
+			serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		return serverConfig, nil
+	}}
+	return application.StartFakeGrpcListener(t, application.NewFakeBookingServer(), application.NewFakeSearchServer(), grpc.Creds(credentials.NewTLS(config)))
+}
+
+func callSecureSearch(t *testing.T, listener *bufconn.Listener, clientCredentials credentials.TransportCredentials) error {
+	conn := application.DialFakeGrpc(t, listener, grpc.WithTransportCredentials(clientCredentials))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err := search.NewSearchServiceClient(conn).DeleteAccommodation(ctx, &search.DeleteAccommodationRequest{AccommodationId: "acc-1"})
+	return err
+}
+
+func TestTransportCredentials_TLSWithCustomCA(t *testing.T) {
+	authority := newTestAuthority(t, "hotel-ca")
+	serverFiles := writeTransportFiles(t, t.TempDir(), authority, testServerName, x509.ExtKeyUsageServerAuth, time.Now())
+	listener := startSecureSearchServer(t, serverFiles, false)
+
+	trusted, err := external.NewClientCredentials(external.TransportConfig{Mode: external.TransportTLS, CAFile: serverFiles.ca, ServerName: testServerName})
+	require.NoError(t, err)
+	otherFiles := writeTransportFiles(t, t.TempDir(), newTestAuthority(t, "other-ca"), testServerName, x509.ExtKeyUsageServerAuth, time.Now())
+	untrusted, err := external.NewClientCredentials(external.TransportConfig{Mode: external.TransportTLS, CAFile: otherFiles.ca, ServerName: testServerName})
+	require.NoError(t, err)
+
+	assert.NoError(t, callSecureSearch(t, listener, trusted))
+	assert.Error(t, callSecureSearch(t, listener, untrusted))
+}
+
+func TestTransportCredentials_MutualTLS(t *testing.T) {
+	authority := newTestAuthority(t, "hotel-ca")
+	serverFiles := writeTransportFiles(t, t.TempDir(), authority, testServerName, x509.ExtKeyUsageServerAuth, time.Now())
+	clientFiles := writeTransportFiles(t, t.TempDir(), authority, "hotel-service", x509.ExtKeyUsageClientAuth, time.Now())
+	listener := startSecureSearchServer(t, serverFiles, true)
+
+	withCertificate, err := external.NewClientCredentials(external.TransportConfig{Mode: external.TransportMTLS, CAFile: clientFiles.ca, CertFile: clientFiles.cert, KeyFile: clientFiles.key, ServerName: testServerName})
+	require.NoError(t, err)
+	withoutCertificate, err := external.NewClientCredentials(external.TransportConfig{Mode: external.TransportTLS, CAFile: clientFiles.ca, ServerName: testServerName})
+	require.NoError(t, err)
+
+	assert.NoError(t, callSecureSearch(t, listener, withCertificate))
+	assert.Error(t, callSecureSearch(t, listener, withoutCertificate))
+}
+
+func TestTransportCredentials_ReloadsRotatedCertificates(t *testing.T) {
+	oldAuthority := newTestAuthority(t, "old-ca")
+	newAuthority := newTestAuthority(t, "new-ca")
+	serverDir, clientDir := t.TempDir(), t.TempDir()
+	serverFiles := writeTransportFiles(t, serverDir, oldAuthority, testServerName, x509.ExtKeyUsageServerAuth, time.Now().Add(-time.Minute))
+	clientFiles := writeTransportFiles(t, clientDir, oldAuthority, "hotel-service", x509.ExtKeyUsageClientAuth, time.Now().Add(-time.Minute))
+	listener := startSecureSearchServer(t, serverFiles, true)
+	clientCredentials, err := external.NewClientCredentials(external.TransportConfig{Mode: external.TransportMTLS, CAFile: clientFiles.ca, CertFile: clientFiles.cert, KeyFile: clientFiles.key, ServerName: testServerName})
+	require.NoError(t, err)
+	require.NoError(t, callSecureSearch(t, listener, clientCredentials))
+
+	writeTransportFiles(t, serverDir, newAuthority, testServerName, x509.ExtKeyUsageServerAuth, time.Now())
+	assert.Error(t, callSecureSearch(t, listener, clientCredentials))
+
+	writeTransportFiles(t, clientDir, newAuthority, "hotel-service", x509.ExtKeyUsageClientAuth, time.Now())
+	assert.NoError(t, callSecureSearch(t, listener, clientCredentials))
+}
+
+func TestTransportCredentials_KeepsPreviousCertificatesOnBrokenRotation(t *testing.T) {
+	authority := newTestAuthority(t, "hotel-ca")
+	serverFiles := writeTransportFiles(t, t.TempDir(), authority, testServerName, x509.ExtKeyUsageServerAuth, time.Now().Add(-time.Minute))
+	listener := startSecureSearchServer(t, serverFiles, false)
+	clientCredentials, err := external.NewClientCredentials(external.TransportConfig{Mode: external.TransportTLS, CAFile: serverFiles.ca, ServerName: testServerName})
+	require.NoError(t, err)
+	require.NoError(t, callSecureSearch(t, listener, clientCredentials))
+
+	writeTestFile(t, serverFiles.ca, []byte("not a certificate"), time.Now())
+
+	assert.NoError(t, callSecureSearch(t, listener, clientCredentials))
+}
+
+func TestTransportCredentials_InvalidConfig(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	for _, config := range []external.TransportConfig{
+		{Mode: "plaintext"},
+		{Mode: external.TransportMTLS, CAFile: missing},
+		{Mode: external.TransportTLS, CAFile: missing},
+	} {
+		_, err := external.NewClientCredentials(config)
+
+		assert.Error(t, err, config.Mode)
+	}
+}
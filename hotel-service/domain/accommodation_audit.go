@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate              AuditAction = "create"
+	AuditUpdate              AuditAction = "update"
+	AuditPatch               AuditAction = "patch"
+	AuditUpdatePrice         AuditAction = "update_price"
+	AuditUploadPriceCalendar AuditAction = "upload_price_calendar"
+	AuditDelete              AuditAction = "delete"
+	AuditRestore             AuditAction = "restore"
+	AuditPurge               AuditAction = "purge"
+	AuditPublish             AuditAction = "publish"
+	AuditUnpublish           AuditAction = "unpublish"
+	AuditSuspend             AuditAction = "suspend"
+	AuditReinstate           AuditAction = "reinstate"
+	AuditAddRoomType         AuditAction = "add_room_type"
+	AuditUpdateRoomType      AuditAction = "update_room_type"
+	AuditDeleteRoomType      AuditAction = "delete_room_type"
+)
+
+type Actor struct {
+	Id       string `bson:"id"`
+	Username string `bson:"username,omitempty"`
+}
+
+type FieldChange struct {
+	Field  string      `bson:"field"`
+	Before interface{} `bson:"before"`
+	After  interface{} `bson:"after"`
+}
+
+type AccommodationAudit struct {
+	Id              primitive.ObjectID `bson:"_id"`
+	AccommodationId primitive.ObjectID `bson:"accommodation_id"`
+	Actor           Actor              `bson:"actor"`
+	Action          AuditAction        `bson:"action"`
+	Timestamp       time.Time          `bson:"timestamp"`
+	Changes         []FieldChange      `bson:"changes"`
+}
+
+type AccommodationAuditStore interface {
+	Insert(audit *AccommodationAudit) error
+	GetByAccommodationId(accommodationId primitive.ObjectID, page, size int) ([]*AccommodationAudit, int64, error)
+}
+
+func SystemActor(name string) Actor {
+	return Actor{Id: "system", Username: name}
+}
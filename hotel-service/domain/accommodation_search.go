@@ -0,0 +1,14 @@
+package domain
+
+type AccommodationSearchQuery struct {
+	Text   string
+	HostId string
+	Offset int
+	Limit  int
+}
+
+type AccommodationSearchHit struct {
+	Accommodation *Accommodation
+	Score         float64
+	Highlights    map[string][]string
+}
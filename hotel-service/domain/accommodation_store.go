@@ -2,6 +2,7 @@ package domain
 
 import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
 )
 
 type AccommodationStore interface {
@@ -12,10 +13,16 @@ type AccommodationStore interface {
 	InsertWithId(accommodation *Accommodation) error
 	DeleteAll()
 	Delete(id primitive.ObjectID) error
+	SoftDelete(id primitive.ObjectID, deletedAt time.Time) error
+	Restore(id primitive.ObjectID) error
+	GetDeleted(id primitive.ObjectID) (*Accommodation, error)
+	GetDeletedBefore(cutoff time.Time) ([]*Accommodation, error)
 	Update(id primitive.ObjectID, accommodation *Accommodation) error
-	UpdateDefaultPrice(id primitive.ObjectID, price *float32) error
-	UpdateSpecialPrice(id primitive.ObjectID, newSpecialPrices []SpecialPrice) error
-	UpdateTypeOfPayment(id primitive.ObjectID, typeOfPayment *string) error
-	GetSpecialPrices(id primitive.ObjectID) ([]SpecialPrice, error)
-	DeleteByHostId(hostId string) error
+	UpdateStatus(id primitive.ObjectID, status AccommodationStatus, pendingBookingRegistration bool, version int64) error
+	UpdatePrices(id primitive.ObjectID, defaultPrice DefaultPrice, specialPrices []SpecialPrice, version int64) error
+	UpdateSpecialPrice(id primitive.ObjectID, newSpecialPrices []SpecialPrice, version int64) error
+	UpdateRoomTypes(id primitive.ObjectID, roomTypes []RoomType, version int64) error
+	Search(query AccommodationSearchQuery) ([]*AccommodationSearchHit, int64, error)
+	Nearby(query NearbyQuery) ([]*NearbyHit, error)
+	CountWithAmenity(code string) (int64, error)
 }
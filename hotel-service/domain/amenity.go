@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+type Amenity struct {
+	Code     string            `bson:"_id"`
+	Category string            `bson:"category"`
+	Labels   map[string]string `bson:"labels"`
+	Icon     string            `bson:"icon"`
+	Aliases  []string          `bson:"aliases,omitempty"`
+}
+
+type AmenityStore interface {
+	Get(code string) (*Amenity, error)
+	GetAll() ([]*Amenity, error)
+	Insert(amenity *Amenity) error
+	InsertIfMissing(amenity *Amenity) error
+	Update(amenity *Amenity) error
+	Delete(code string) error
+}
+
+func (amenity *Amenity) Terms() []string {
+	terms := []string{amenity.Code}
+	for _, label := range amenity.Labels {
+		terms = append(terms, label)
+	}
+	return append(terms, amenity.Aliases...)
+}
+
+func NormalizeAmenityTerm(term string) string {
+	words := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+type AmenityMatcher map[string]string
+
+func NewAmenityMatcher(amenities []*Amenity) AmenityMatcher {
+	matcher := AmenityMatcher{}
+	for _, amenity := range amenities {
+		for _, term := range amenity.Terms() {
+			if key := NormalizeAmenityTerm(term); key != "" {
+				if _, taken := matcher[key]; !taken {
+					matcher[key] = amenity.Code
+				}
+			}
+		}
+	}
+	for _, amenity := range amenities {
+		matcher[NormalizeAmenityTerm(amenity.Code)] = amenity.Code
+	}
+	return matcher
+}
+
+func (matcher AmenityMatcher) Match(benefits []string) ([]string, []string) {
+	codes, unmapped := []string{}, []string{}
+	seen := map[string]bool{}
+	for _, benefit := range benefits {
+		code, ok := matcher[NormalizeAmenityTerm(benefit)]
+		switch {
+		case !ok:
+			unmapped = append(unmapped, benefit)
+		case !seen[code]:
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return codes, unmapped
+}
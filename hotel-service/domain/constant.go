@@ -1,5 +1,6 @@
 package domain
 
 const (
-	ServiceName string = "hotel-service"
+	ServiceName     string = "hotel-service"
+	UploadDirectory string = "./uploads"
 )
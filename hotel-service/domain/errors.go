@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrNotFound         = errors.New("record not found")
+	ErrDuplicateId      = errors.New("record with this id already exists")
+	ErrStoreUnavailable = errors.New("store is unavailable")
+	ErrVersionConflict  = errors.New("accommodation version conflict")
+	ErrRetentionExpired = errors.New("accommodation retention window expired")
+	ErrListingSuspended = errors.New("accommodation listing is suspended")
+	ErrAmenityExists    = errors.New("amenity already exists")
+	ErrAmenityInUse     = errors.New("amenity is used by accommodations")
+	ErrRoomTypeNotFound = errors.New("room type not found")
+	ErrHasReservations  = errors.New("accommodation has active reservations")
+)
+
+type IncompleteListingError struct {
+	Problems []string
+}
+
+func (e *IncompleteListingError) Error() string {
+	return "accommodation listing is incomplete: " + strings.Join(e.Problems, ", ")
+}
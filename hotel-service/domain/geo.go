@@ -0,0 +1,52 @@
+package domain
+
+import "strings"
+
+const GeoPointType = "Point"
+
+type Address struct {
+	Street      string `bson:"street,omitempty"`
+	City        string `bson:"city,omitempty"`
+	Region      string `bson:"region,omitempty"`
+	CountryCode string `bson:"country_code,omitempty"`
+	PostalCode  string `bson:"postal_code,omitempty"`
+}
+
+type GeoPoint struct {
+	Type        string    `bson:"type"`
+	Coordinates []float64 `bson:"coordinates"`
+}
+
+type NearbyQuery struct {
+	Latitude     float64
+	Longitude    float64
+	RadiusMeters float64
+	Limit        int
+}
+
+type NearbyHit struct {
+	Accommodation  *Accommodation
+	DistanceMeters float64
+}
+
+func NewGeoPoint(latitude, longitude float64) *GeoPoint {
+	return &GeoPoint{Type: GeoPointType, Coordinates: []float64{longitude, latitude}}
+}
+
+func (point *GeoPoint) Latitude() float64 {
+	return point.Coordinates[1]
+}
+
+func (point *GeoPoint) Longitude() float64 {
+	return point.Coordinates[0]
+}
+
+func (address *Address) Format() string {
+	var parts []string
+	for _, part := range []string{address.City, address.Region, address.CountryCode} {
+		if strings.TrimSpace(part) != "" {
+			parts = append(parts, strings.TrimSpace(part))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
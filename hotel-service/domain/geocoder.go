@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrAddressNotFound  = errors.New("address could not be geocoded")
+	ErrGeocodeNotCached = errors.New("address is not in the geocode cache")
+)
+
+type Geocoder interface {
+	Geocode(address *Address) (*GeoPoint, error)
+}
+
+type GeocodeCacheStore interface {
+	Get(key string) (*GeoPoint, error)
+	Put(key string, point *GeoPoint) error
+}
+
+func (address *Address) GeocodeKey() string {
+	parts := []string{address.Street, address.PostalCode, address.City, address.Region, address.CountryCode}
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.Join(strings.Fields(part), " "))
+	}
+	return strings.Join(parts, "|")
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type HostDeletionStatus int
+
+const (
+	HostDeletionChecking HostDeletionStatus = iota
+	HostDeletionDeleting
+	HostDeletionCompleted
+	HostDeletionRejected
+	HostDeletionCompensated
+	HostDeletionCompensating
+	HostDeletionRejecting
+)
+
+type HostDeletionSaga struct {
+	Id               primitive.ObjectID   `bson:"_id"`
+	HostId           string               `bson:"host_id"`
+	Status           HostDeletionStatus   `bson:"status"`
+	AccommodationIds []primitive.ObjectID `bson:"accommodation_ids"`
+	RoomTypeIds      []primitive.ObjectID `bson:"room_type_ids,omitempty"`
+	DeletedIds       []primitive.ObjectID `bson:"deleted_ids"`
+	Reason           string               `bson:"reason,omitempty"`
+	Outcome          HostDeletionStatus   `bson:"outcome,omitempty"`
+	CreatedAt        time.Time            `bson:"created_at"`
+	UpdatedAt        time.Time            `bson:"updated_at"`
+	Version          int64                `bson:"version"`
+	LeaseExpiresAt   time.Time            `bson:"lease_expires_at"`
+}
+
+// HostDeletionSagaStore writes are guarded by the saga version, so a runner working from a stale
+// copy gets ErrVersionConflict instead of overwriting another runner's progress.
+type HostDeletionSagaStore interface {
+	Insert(saga *HostDeletionSaga) error
+	Update(saga *HostDeletionSaga) error
+	// Lease claims the saga until the given time, failing with ErrVersionConflict while another runner holds it.
+	Lease(saga *HostDeletionSaga, until time.Time) error
+	GetUnfinishedByHostId(hostId string) (*HostDeletionSaga, error)
+	GetUnfinished() ([]*HostDeletionSaga, error)
+}
+
+func (s HostDeletionStatus) String() string {
+	switch s {
+	case HostDeletionChecking:
+		return "Checking"
+	case HostDeletionDeleting:
+		return "Deleting"
+	case HostDeletionCompleted:
+		return "Completed"
+	case HostDeletionRejected:
+		return "Rejected"
+	case HostDeletionCompensated:
+		return "Compensated"
+	case HostDeletionCompensating:
+		return "Compensating"
+	case HostDeletionRejecting:
+		return "Rejecting"
+	default:
+		return "Unknown"
+	}
+}
+
+func (saga *HostDeletionSaga) IsDeleted(id primitive.ObjectID) bool {
+	for _, deletedId := range saga.DeletedIds {
+		if deletedId == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package domain
+
+type HouseRules struct {
+	CheckInFrom    string      `bson:"check_in_from"`
+	CheckInUntil   string      `bson:"check_in_until"`
+	CheckOutUntil  string      `bson:"check_out_until"`
+	PetsAllowed    bool        `bson:"pets_allowed"`
+	SmokingAllowed bool        `bson:"smoking_allowed"`
+	PartiesAllowed bool        `bson:"parties_allowed"`
+	QuietHours     *TimeWindow `bson:"quiet_hours,omitempty"`
+}
+
+type TimeWindow struct {
+	From  string `bson:"from"`
+	Until string `bson:"until"`
+}
+
+type CancellationPolicy string
+
+const (
+	FlexibleCancellation      CancellationPolicy = "flexible"
+	ModerateCancellation      CancellationPolicy = "moderate"
+	StrictCancellation        CancellationPolicy = "strict"
+	NonRefundableCancellation CancellationPolicy = "non_refundable"
+)
+
+var CancellationPolicies = []CancellationPolicy{FlexibleCancellation, ModerateCancellation, StrictCancellation, NonRefundableCancellation}
+
+type RefundRule struct {
+	DaysBeforeCheckIn int
+	RefundPercent     int
+}
+
+var refundSchedules = map[CancellationPolicy][]RefundRule{
+	FlexibleCancellation:      {{DaysBeforeCheckIn: 1, RefundPercent: 100}, {DaysBeforeCheckIn: 0, RefundPercent: 0}},
+	ModerateCancellation:      {{DaysBeforeCheckIn: 5, RefundPercent: 100}, {DaysBeforeCheckIn: 0, RefundPercent: 50}},
+	StrictCancellation:        {{DaysBeforeCheckIn: 14, RefundPercent: 100}, {DaysBeforeCheckIn: 7, RefundPercent: 50}, {DaysBeforeCheckIn: 0, RefundPercent: 0}},
+	NonRefundableCancellation: {{DaysBeforeCheckIn: 0, RefundPercent: 0}},
+}
+
+func (policy CancellationPolicy) IsValid() bool {
+	_, ok := refundSchedules[policy]
+	return ok
+}
+
+func (policy CancellationPolicy) RefundSchedule() []RefundRule {
+	return refundSchedules[policy]
+}
+
+func (policy CancellationPolicy) RefundPercent(daysBeforeCheckIn int) int {
+	for _, rule := range policy.RefundSchedule() {
+		if daysBeforeCheckIn >= rule.DaysBeforeCheckIn {
+			return rule.RefundPercent
+		}
+	}
+	return 0
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	IdempotencyPending   = "pending"
+	IdempotencyCompleted = "completed"
+)
+
+type IdempotencyRecord struct {
+	Id             string      `bson:"_id"`
+	RequestHash    string      `bson:"request_hash"`
+	Method         string      `bson:"method"`
+	Path           string      `bson:"path"`
+	Status         string      `bson:"status"`
+	StatusCode     int         `bson:"status_code,omitempty"`
+	Header         http.Header `bson:"header,omitempty"`
+	Body           []byte      `bson:"body,omitempty"`
+	CreatedAt      time.Time   `bson:"created_at"`
+	LeaseExpiresAt time.Time   `bson:"lease_expires_at"`
+}
+
+func (record *IdempotencyRecord) LeaseExpired(now time.Time) bool {
+	return record.Status == IdempotencyPending && !now.Before(record.LeaseExpiresAt)
+}
+
+type IdempotencyStore interface {
+	Reserve(record *IdempotencyRecord) (*IdempotencyRecord, error)
+	Complete(id string, statusCode int, header http.Header, body []byte) error
+	Release(id string) error
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"github.com/go-playground/validator/v10"
+	"strings"
+)
+
+var countryCodeValidator = validator.New()
+
+func ParseLegacyLocation(location string) (*Address, bool) {
+	var parts []string
+	for _, part := range strings.Split(location, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			parts = append(parts, trimmed)
+		}
+	}
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, false
+	}
+
+	countryCode := strings.ToUpper(parts[len(parts)-1])
+	if countryCodeValidator.Var(countryCode, "iso3166_1_alpha2") != nil {
+		return nil, false
+	}
+	address := &Address{City: parts[0], CountryCode: countryCode}
+	if len(parts) == 3 {
+		address.Region = parts[1]
+	}
+	return address, true
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "sort"
+
+const DefaultLocale = "en"
+
+type Translation struct {
+	Name        string `bson:"name,omitempty"`
+	Description string `bson:"description,omitempty"`
+}
+
+func (accommodation *Accommodation) Locale() string {
+	if accommodation.DefaultLocale != "" {
+		return accommodation.DefaultLocale
+	}
+	return DefaultLocale
+}
+
+// TranslatedText lists the translated names and descriptions ordered by locale, for full-text search.
+func (accommodation *Accommodation) TranslatedText() []string {
+	locales := make([]string, 0, len(accommodation.Translations))
+	for locale := range accommodation.Translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	text := []string{}
+	for _, locale := range locales {
+		translation := accommodation.Translations[locale]
+		for _, value := range []string{translation.Name, translation.Description} {
+			if value != "" {
+				text = append(text, value)
+			}
+		}
+	}
+	return text
+}
+
+func (accommodation *Accommodation) Localize(locale string) (string, string) {
+	name, description := accommodation.Name, accommodation.Description
+	translation, ok := accommodation.Translations[locale]
+	if !ok || locale == accommodation.Locale() {
+		return name, description
+	}
+	if translation.Name != "" {
+		name = translation.Name
+	}
+	if translation.Description != "" {
+		description = translation.Description
+	}
+	return name, description
+}
@@ -1,23 +1,46 @@
 package domain
 
 import (
+	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"time"
 )
 
 type Accommodation struct {
-	Id                                    primitive.ObjectID `bson:"_id"`
-	HostId                                string             `bson:"host_id"`
-	Name                                  string             `bson:"name"`
-	Location                              string             `bson:"location"`
-	Benefits                              []string           `bson:"benefits"`
-	Photos                                []string           `bson:"photos"`
-	GuestNumber                           GuestNumber        `bson:"guest_number"`
-	DefaultPrice                          DefaultPrice       `bson:"default_price"`
-	SpecialPrice                          []SpecialPrice     `bson:"special_price"`
-	ReviewReservationRequestAutomatically bool               `bson:"review_reservation_request_automatically"`
+	Id                                    primitive.ObjectID     `bson:"_id"`
+	HostId                                string                 `bson:"host_id"`
+	Name                                  string                 `bson:"name"`
+	Description                           string                 `bson:"description,omitempty"`
+	DefaultLocale                         string                 `bson:"default_locale,omitempty"`
+	Translations                          map[string]Translation `bson:"translations,omitempty"`
+	Location                              string                 `bson:"location"`
+	Address                               *Address               `bson:"address,omitempty"`
+	GeoLocation                           *GeoPoint              `bson:"geo_location,omitempty"`
+	GeocodingNeedsReview                  bool                   `bson:"geocoding_needs_review,omitempty"`
+	Benefits                              []string               `bson:"benefits"`
+	UnmappedBenefits                      []string               `bson:"unmapped_benefits,omitempty"`
+	Photos                                []string               `bson:"photos"`
+	GuestNumber                           GuestNumber            `bson:"guest_number"`
+	DefaultPrice                          DefaultPrice           `bson:"default_price"`
+	SpecialPrice                          []SpecialPrice         `bson:"special_price"`
+	RoomTypes                             []RoomType             `bson:"room_types,omitempty"`
+	HouseRules                            *HouseRules            `bson:"house_rules,omitempty"`
+	CancellationPolicy                    CancellationPolicy     `bson:"cancellation_policy,omitempty"`
+	ReviewReservationRequestAutomatically bool                   `bson:"review_reservation_request_automatically"`
+	Status                                AccommodationStatus    `bson:"status"`
+	PendingBookingRegistration            bool                   `bson:"pending_booking_registration,omitempty"`
+	Version                               int64                  `bson:"version"`
+	DeletedAt                             *time.Time             `bson:"deleted_at,omitempty"`
 }
 
+type AccommodationStatus int
+
+const (
+	Published AccommodationStatus = iota
+	Draft
+	Suspended
+)
+
 type GuestNumber struct {
 	Min int `bson:"min"`
 	Max int `bson:"max"`
@@ -45,6 +68,23 @@ type DateRange struct {
 	End   time.Time `bson:"end"`
 }
 
+type PriceSource int
+
+// PriceSource tells where a nightly price came from; RulePriceSource marks prices derived from a
+// pricing rule rather than set directly on the accommodation.
+const (
+	DefaultPriceSource PriceSource = iota
+	SpecialPriceSource
+	RulePriceSource
+)
+
+type NightlyPrice struct {
+	Date   time.Time
+	Price  float32
+	Source PriceSource
+	Type   PricingType
+}
+
 func (p PricingType) String() string {
 	switch p {
 	case PerApartmentUnit:
@@ -55,3 +95,41 @@ func (p PricingType) String() string {
 		return "Unknown"
 	}
 }
+
+func ParsePricingType(typeOfPayment *string) *PricingType {
+	var pricingType PricingType
+	switch *typeOfPayment {
+	case "PerApartmentUnit":
+		pricingType = PerApartmentUnit
+	case "PerGuest":
+		pricingType = PerGuest
+	default:
+		return nil
+	}
+	return &pricingType
+}
+
+func (s AccommodationStatus) String() string {
+	switch s {
+	case Published:
+		return "published"
+	case Draft:
+		return "draft"
+	case Suspended:
+		return "suspended"
+	default:
+		return "unknown"
+	}
+}
+
+func (s PriceSource) String() string {
+	switch s {
+	case DefaultPriceSource:
+		return "default"
+	case SpecialPriceSource:
+		return "special"
+	case RulePriceSource:
+		return "rule"
+	}
+	return fmt.Sprintf("PriceSource(%d)", int(s))
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+type RateLimit struct {
+	PerMinute int
+	Burst     int
+}
+
+func (limit RateLimit) Interval() time.Duration {
+	return time.Minute / time.Duration(limit.PerMinute)
+}
+
+type RateLimiter interface {
+	Allow(key string, limit RateLimit) (bool, time.Duration, error)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type RoomType struct {
+	Id           primitive.ObjectID `bson:"_id"`
+	Name         string             `bson:"name"`
+	Capacity     GuestNumber        `bson:"capacity"`
+	Beds         []BedConfiguration `bson:"beds"`
+	UnitCount    int                `bson:"unit_count"`
+	DefaultPrice DefaultPrice       `bson:"default_price"`
+	SpecialPrice []SpecialPrice     `bson:"special_price"`
+	Photos       []string           `bson:"photos"`
+}
+
+type BedConfiguration struct {
+	Type  string `bson:"type"`
+	Count int    `bson:"count"`
+}
+
+func (accommodation *Accommodation) RoomType(id primitive.ObjectID) (*RoomType, int) {
+	for i := range accommodation.RoomTypes {
+		if accommodation.RoomTypes[i].Id == id {
+			return &accommodation.RoomTypes[i], i
+		}
+	}
+	return nil, -1
+}
+
+func (accommodation *Accommodation) BookableUnitIds() []primitive.ObjectID {
+	ids := []primitive.ObjectID{accommodation.Id}
+	for _, roomType := range accommodation.RoomTypes {
+		ids = append(ids, roomType.Id)
+	}
+	return ids
+}
+
+func (accommodation *Accommodation) RoomTypeBookingName(roomType *RoomType) string {
+	return accommodation.Name + " - " + roomType.Name
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/ZMS-DevOps/hotel-service/application"
 	"github.com/ZMS-DevOps/hotel-service/domain"
@@ -16,44 +17,83 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"io"
 	"log"
+	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type AccommodationHandler struct {
-	service       *application.AccommodationService
-	traceProvider *sdktrace.TracerProvider
-	loki          promtail.Client
+	service          *application.AccommodationService
+	amenityService   *application.AmenityService
+	hostDeletionSaga *application.HostDeletionSagaService
+	deletedRetention time.Duration
+	traceProvider    *sdktrace.TracerProvider
+	loki             promtail.Client
+	idempotency      *Idempotency
+}
+
+var immutablePatchFields = []struct {
+	name    string
+	message string
+}{
+	{"photos", "photos can only be changed with PUT"},
+	{"status", "status can only be changed with the publish endpoints"},
+	{"host_id", "host_id can't be changed"},
+	{"version", "version can only be set with the If-Match header"},
 }
 
 type HealthCheckResponse struct {
 	Size string `json:"size"`
 }
 
-func NewAccommodationHandler(service *application.AccommodationService, traceProvider *sdktrace.TracerProvider, loki promtail.Client) *AccommodationHandler {
+func NewAccommodationHandler(service *application.AccommodationService, amenityService *application.AmenityService, hostDeletionSaga *application.HostDeletionSagaService, deletedRetention time.Duration, traceProvider *sdktrace.TracerProvider, loki promtail.Client, idempotency *Idempotency) *AccommodationHandler {
 	server := &AccommodationHandler{
-		service:       service,
-		traceProvider: traceProvider,
-		loki:          loki,
+		service:          service,
+		amenityService:   amenityService,
+		hostDeletionSaga: hostDeletionSaga,
+		deletedRetention: deletedRetention,
+		traceProvider:    traceProvider,
+		loki:             loki,
+		idempotency:      idempotency,
 	}
 	return server
 }
 
 func (handler *AccommodationHandler) Init(router *mux.Router) {
 	router.HandleFunc(`/accommodation`, handler.GetAll).Methods("GET")
+	router.HandleFunc("/accommodation/search", handler.Search).Methods("GET")
+	router.HandleFunc("/accommodation/nearby", handler.Nearby).Methods("GET")
+	router.HandleFunc("/accommodation/cancellation-policies", handler.GetCancellationPolicies).Methods("GET")
 	router.HandleFunc("/accommodation/{id}", handler.GetById).Methods("GET")
 	router.HandleFunc("/accommodation/host/{id}", handler.GetByHostId).Methods("GET")
-	router.HandleFunc("/accommodation", handler.Add).Methods("POST")
-	router.HandleFunc("/accommodation/{id}", handler.Update).Methods("PUT")
+	router.HandleFunc("/accommodation", handler.idempotency.Wrap(handler.Add)).Methods("POST")
+	router.HandleFunc("/accommodation/{id}", handler.idempotency.Wrap(handler.Update)).Methods("PUT")
+	router.HandleFunc("/accommodation/{id}", handler.idempotency.Wrap(handler.Patch)).Methods("PATCH")
 	router.HandleFunc("/accommodation/{id}", handler.Delete).Methods("DELETE")
-	router.HandleFunc("/accommodation/price/{id}", handler.UpdatePrice).Methods("PUT")
+	router.HandleFunc("/accommodation/{id}/restore", handler.idempotency.Wrap(handler.Restore)).Methods("POST")
+	router.HandleFunc("/accommodation/{id}/publish", handler.idempotency.Wrap(handler.Publish)).Methods("POST")
+	router.HandleFunc("/accommodation/{id}/unpublish", handler.idempotency.Wrap(handler.Unpublish)).Methods("POST")
+	router.HandleFunc("/accommodation/{id}/suspend", handler.idempotency.Wrap(handler.Suspend)).Methods("POST")
+	router.HandleFunc("/accommodation/{id}/reinstate", handler.idempotency.Wrap(handler.Reinstate)).Methods("POST")
+	router.HandleFunc("/accommodation/price/{id}", handler.idempotency.Wrap(handler.UpdatePrice)).Methods("PUT")
+	router.HandleFunc("/accommodation/{id}/price-calendar", handler.idempotency.Wrap(handler.UploadPriceCalendar)).Methods("POST")
+	router.HandleFunc("/accommodation/{id}/calendar", handler.GetPriceCalendar).Methods("GET")
+	router.HandleFunc("/accommodation/{id}/history", handler.GetHistory).Methods("GET")
+	router.HandleFunc("/accommodation/{id}/rooms", handler.GetRoomTypes).Methods("GET")
+	router.HandleFunc("/accommodation/{id}/rooms", handler.idempotency.Wrap(handler.AddRoomType)).Methods("POST")
+	router.HandleFunc("/accommodation/{id}/rooms/{roomId}", handler.GetRoomType).Methods("GET")
+	router.HandleFunc("/accommodation/{id}/rooms/{roomId}", handler.idempotency.Wrap(handler.UpdateRoomType)).Methods("PUT")
+	router.HandleFunc("/accommodation/{id}/rooms/{roomId}", handler.DeleteRoomType).Methods("DELETE")
 	router.HandleFunc("/accommodation/health", handler.GetHealthCheck).Methods("GET")
 	router.HandleFunc("/accommodation/images", handler.GetImagesForAccommodations).Methods("POST")
 }
 
 func (handler *AccommodationHandler) Update(w http.ResponseWriter, r *http.Request) {
-	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "update-put")
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "update-put")
 	defer func() { span.End() }()
 
 	vars := mux.Vars(r)
@@ -63,11 +103,21 @@ func (handler *AccommodationHandler) Update(w http.ResponseWriter, r *http.Reque
 		handleError(w, http.StatusBadRequest, "Invalid accommodation ID")
 		return
 	}
+	if !handler.authorizeOwner(w, r, accommodationId, span, "Update") {
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		util.HttpTraceError(err, "invalid If-Match header", span, handler.loki, "Update", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, "Invalid If-Match header")
+		return
+	}
 
 	err = r.ParseMultipartForm(10 << 20)
 	if err != nil {
 		util.HttpTraceError(err, "failed to parse form data", span, handler.loki, "Update", "")
-		handleError(w, http.StatusBadRequest, "failed to parse form data")
+		handleBodyError(w, err, "failed to parse form data")
 		return
 	}
 	var createAccommodationDto dto.AccommodationDto
@@ -78,9 +128,16 @@ func (handler *AccommodationHandler) Update(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if err := dto.ValidateAccommodationDto(&createAccommodationDto); err != nil {
-		util.HttpTraceError(err, "failed to validate json data", span, handler.loki, "Update", "")
-		handleError(w, http.StatusBadRequest, err.Error())
+	if !handler.validateAccommodationDto(w, &createAccommodationDto, span, "Update") {
+		return
+	}
+	if !authorizeHost(w, r, createAccommodationDto.HostId) {
+		return
+	}
+
+	if err := handler.service.CheckVersion(accommodationId, version); err != nil {
+		util.HttpTraceError(err, "failed to check accommodation version", span, handler.loki, "Update", accommodationId.Hex())
+		handleVersionError(w, err)
 		return
 	}
 
@@ -95,18 +152,132 @@ func (handler *AccommodationHandler) Update(w http.ResponseWriter, r *http.Reque
 	updatedAccommodation.Photos = photos
 
 	updatedAccommodation.Id = accommodationId
+	updatedAccommodation.Version = version
 
-	if err := handler.service.Update(accommodationId, updatedAccommodation, span, handler.loki); err != nil {
+	if err := handler.service.Update(ctx, accommodationId, updatedAccommodation, requestActor(r), span, handler.loki); err != nil {
 		util.HttpTraceError(err, "failed to update accommodation", span, handler.loki, "Update", "")
+		if errors.Is(err, domain.ErrVersionConflict) {
+			handleError(w, http.StatusPreconditionFailed, "accommodation was modified by another request")
+			return
+		}
 		handleError(w, http.StatusInternalServerError, "failed to update accommodation")
 		return
 	}
 	util.HttpTraceInfo("Accommodation updated successfully", span, handler.loki, "Update", accommodationId.Hex())
+	setETag(w, updatedAccommodation.Version)
 	w.WriteHeader(http.StatusOK)
 }
 
+func (handler *AccommodationHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "patch-patch")
+	defer func() { span.End() }()
+
+	vars := mux.Vars(r)
+	accommodationId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		util.HttpTraceError(err, "invalid accommodation id", span, handler.loki, "Patch", "id: "+vars["id"])
+		handleError(w, http.StatusBadRequest, "Invalid accommodation ID")
+		return
+	}
+	if !handler.authorizeOwner(w, r, accommodationId, span, "Patch") {
+		return
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != dto.MergePatchContentType {
+		util.HttpTraceError(fmt.Errorf("unsupported content type %q", r.Header.Get("Content-Type")), "unsupported content type", span, handler.loki, "Patch", accommodationId.Hex())
+		handleError(w, http.StatusUnsupportedMediaType, "Content-Type must be "+dto.MergePatchContentType)
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		util.HttpTraceError(err, "invalid If-Match header", span, handler.loki, "Patch", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, "Invalid If-Match header")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		util.HttpTraceError(err, "failed to read request body", span, handler.loki, "Patch", accommodationId.Hex())
+		handleBodyError(w, err, "Invalid request payload")
+		return
+	}
+	patch, err := dto.ParseMergePatch(body)
+	if err != nil {
+		util.HttpTraceError(err, "invalid merge patch", span, handler.loki, "Patch", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	for _, field := range immutablePatchFields {
+		if _, ok := patch[field.name]; ok {
+			util.HttpTraceError(fmt.Errorf("%s can't be patched", field.name), field.name+" can't be patched", span, handler.loki, "Patch", accommodationId.Hex())
+			handleError(w, http.StatusBadRequest, field.message)
+			return
+		}
+	}
+
+	accommodation, err := handler.service.Get(accommodationId, span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to get accommodation by id", span, handler.loki, "Patch", accommodationId.Hex())
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if version != 0 && version != accommodation.Version {
+		util.HttpTraceError(domain.ErrVersionConflict, "stale If-Match header", span, handler.loki, "Patch", accommodationId.Hex())
+		handleError(w, http.StatusPreconditionFailed, "accommodation was modified by another request")
+		return
+	}
+
+	document, err := json.Marshal(dto.MapAccommodationDto(accommodation))
+	if err != nil {
+		util.HttpTraceError(err, "failed to marshal data", span, handler.loki, "Patch", accommodationId.Hex())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	patchedDocument, err := dto.ApplyMergePatch(document, patch)
+	if err != nil {
+		util.HttpTraceError(err, "failed to apply merge patch", span, handler.loki, "Patch", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	var patchedAccommodationDto dto.AccommodationDto
+	if err := json.Unmarshal(patchedDocument, &patchedAccommodationDto); err != nil {
+		util.HttpTraceError(err, "failed to parse patched data", span, handler.loki, "Patch", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, addressPatched := patch["address"]; addressPatched && patchedAccommodationDto.Address != nil {
+		if _, locationPatched := patch["location"]; !locationPatched {
+			patchedAccommodationDto.Location = ""
+		}
+	}
+
+	if !handler.validateAccommodationDto(w, &patchedAccommodationDto, span, "Patch") {
+		return
+	}
+
+	patchedAccommodation := dto.MapAccommodation(&patchedAccommodationDto)
+	patchedAccommodation.Id = accommodationId
+	patchedAccommodation.Version = accommodation.Version
+
+	updatedAccommodation, err := handler.service.Patch(ctx, accommodationId, patchedAccommodation, requestActor(r), span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to patch accommodation", span, handler.loki, "Patch", accommodationId.Hex())
+		if errors.Is(err, domain.ErrVersionConflict) {
+			handleError(w, http.StatusPreconditionFailed, "accommodation was modified by another request")
+			return
+		}
+		handleError(w, http.StatusInternalServerError, "failed to patch accommodation")
+		return
+	}
+	util.HttpTraceInfo("Accommodation patched successfully", span, handler.loki, "Patch", accommodationId.Hex())
+
+	setETag(w, updatedAccommodation.Version)
+	writeJson(w, http.StatusOK, dto.MapAccommodationResponse(*updatedAccommodation))
+}
+
 func (handler *AccommodationHandler) UpdatePrice(w http.ResponseWriter, r *http.Request) {
-	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "update-price-put")
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "update-price-put")
 	defer func() { span.End() }()
 
 	vars := mux.Vars(r)
@@ -116,11 +287,21 @@ func (handler *AccommodationHandler) UpdatePrice(w http.ResponseWriter, r *http.
 		handleError(w, http.StatusBadRequest, "Invalid accommodation ID")
 		return
 	}
+	if !handler.authorizeOwner(w, r, accommodationId, span, "UpdatePrice") {
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		util.HttpTraceError(err, "invalid If-Match header", span, handler.loki, "UpdatePrice", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, "Invalid If-Match header")
+		return
+	}
 
 	var updatePriceDto dto.UpdatePriceDto
 	if err := json.NewDecoder(r.Body).Decode(&updatePriceDto); err != nil {
 		util.HttpTraceError(err, "invalid request payload", span, handler.loki, "UpdatePrice", accommodationId.Hex())
-		handleError(w, http.StatusBadRequest, "Invalid request payload")
+		handleBodyError(w, err, "Invalid request payload")
 		return
 	}
 
@@ -130,18 +311,227 @@ func (handler *AccommodationHandler) UpdatePrice(w http.ResponseWriter, r *http.
 		return
 	}
 
-	if err := handler.service.UpdatePrice(accommodationId, updatePriceDto, span, handler.loki); err != nil {
+	updatePriceDto.Version = version
+	updatedAccommodation, err := handler.service.UpdatePrice(ctx, accommodationId, updatePriceDto, requestActor(r), span, handler.loki)
+	if err != nil {
 		util.HttpTraceError(err, "failed to update accommodation price", span, handler.loki, "UpdatePrice", accommodationId.Hex())
+		if errors.Is(err, domain.ErrVersionConflict) {
+			handleError(w, http.StatusPreconditionFailed, "accommodation was modified by another request")
+			return
+		}
 		handleError(w, http.StatusInternalServerError, "failed to update accommodation price")
 		return
 	}
 	util.HttpTraceInfo("Accommodation price updated successfully", span, handler.loki, "Add", "")
 
+	setETag(w, updatedAccommodation.Version)
 	w.WriteHeader(http.StatusOK)
 }
 
+func (handler *AccommodationHandler) UploadPriceCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "upload-price-calendar-post")
+	defer func() { span.End() }()
+
+	vars := mux.Vars(r)
+	accommodationId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		util.HttpTraceError(err, "invalid accommodation id", span, handler.loki, "UploadPriceCalendar", "")
+		handleError(w, http.StatusBadRequest, "Invalid accommodation ID")
+		return
+	}
+	if !handler.authorizeOwner(w, r, accommodationId, span, "UploadPriceCalendar") {
+		return
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			util.HttpTraceError(err, "invalid dry_run parameter", span, handler.loki, "UploadPriceCalendar", accommodationId.Hex())
+			handleError(w, http.StatusBadRequest, "Invalid dry_run parameter")
+			return
+		}
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		util.HttpTraceError(err, "invalid If-Match header", span, handler.loki, "UploadPriceCalendar", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, "Invalid If-Match header")
+		return
+	}
+
+	var rows []dto.PriceCalendarRowDto
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		rows, err = dto.ParsePriceCalendarCsv(r.Body)
+	} else {
+		rows, err = dto.ParsePriceCalendarJson(r.Body)
+	}
+	if err != nil {
+		util.HttpTraceError(err, "invalid request payload", span, handler.loki, "UploadPriceCalendar", accommodationId.Hex())
+		handleBodyError(w, err, "Invalid request payload")
+		return
+	}
+
+	if rowErrors := dto.ValidatePriceCalendarRows(rows); len(rowErrors) != 0 {
+		util.HttpTraceError(fmt.Errorf("%d invalid rows", len(rowErrors)), "failed to validate price calendar", span, handler.loki, "UploadPriceCalendar", accommodationId.Hex())
+		writeJson(w, http.StatusBadRequest, dto.PriceCalendarErrorResponse{Errors: rowErrors})
+		return
+	}
+
+	response, err := handler.service.UploadPriceCalendar(ctx, accommodationId, dto.MapPriceCalendarRows(rows), dryRun, version, requestActor(r), span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to upload price calendar", span, handler.loki, "UploadPriceCalendar", accommodationId.Hex())
+		if errors.Is(err, domain.ErrVersionConflict) {
+			handleError(w, http.StatusPreconditionFailed, "accommodation was modified by another request")
+			return
+		}
+		handleError(w, http.StatusInternalServerError, "failed to upload price calendar")
+		return
+	}
+	util.HttpTraceInfo("Price calendar uploaded successfully", span, handler.loki, "UploadPriceCalendar", accommodationId.Hex())
+
+	setETag(w, response.Version)
+	writeJson(w, http.StatusOK, response)
+}
+
+func (handler *AccommodationHandler) GetPriceCalendar(w http.ResponseWriter, r *http.Request) {
+	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "get-price-calendar-get")
+	defer func() { span.End() }()
+
+	vars := mux.Vars(r)
+	accommodationId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		util.HttpTraceError(err, "invalid accommodation id", span, handler.loki, "GetPriceCalendar", "")
+		handleError(w, http.StatusBadRequest, "Invalid accommodation ID")
+		return
+	}
+
+	calendarRequest, err := dto.ParseCalendarRequest(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		util.HttpTraceError(err, "invalid calendar window", span, handler.loki, "GetPriceCalendar", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	accommodation, response, err := handler.service.GetPriceCalendar(accommodationId, calendarRequest, span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to get price calendar", span, handler.loki, "GetPriceCalendar", accommodationId.Hex())
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if !canView(r, accommodation) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	util.HttpTraceInfo("Price calendar retrieved successfully", span, handler.loki, "GetPriceCalendar", accommodationId.Hex())
+
+	writeJson(w, http.StatusOK, response)
+}
+
+func (handler *AccommodationHandler) Search(w http.ResponseWriter, r *http.Request) {
+	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "search-get")
+	defer func() { span.End() }()
+
+	query, err := dto.ParseSearchRequest(r.URL.Query().Get("q"), r.URL.Query().Get("offset"), r.URL.Query().Get("limit"))
+	if err != nil {
+		util.HttpTraceError(err, "invalid search request", span, handler.loki, "Search", "")
+		handleError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	caller, err := meshVerifiedIdentity(r)
+	switch {
+	case err != nil:
+		handleError(w, http.StatusUnauthorized, "caller identity is required")
+		return
+	case caller.hasRole(moderationRoles...):
+		query.HostId = r.URL.Query().Get("host_id")
+	case caller.hasRole("host"):
+		query.HostId = caller.Subject
+	default:
+		handleError(w, http.StatusForbidden, "not allowed to search accommodations")
+		return
+	}
+
+	response, err := handler.service.Search(query, span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to search accommodations", span, handler.loki, "Search", query.Text)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	util.HttpTraceInfo("Accommodations searched successfully", span, handler.loki, "Search", query.Text)
+
+	writeJson(w, http.StatusOK, response)
+}
+
+func (handler *AccommodationHandler) Nearby(w http.ResponseWriter, r *http.Request) {
+	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "nearby-get")
+	defer func() { span.End() }()
+
+	values := r.URL.Query()
+	query, err := dto.ParseNearbyRequest(values.Get("lat"), values.Get("lng"), values.Get("radius"), values.Get("limit"))
+	if err != nil {
+		util.HttpTraceError(err, "invalid nearby request", span, handler.loki, "Nearby", "")
+		handleError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	hits, err := handler.service.Nearby(query, span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to get nearby accommodations", span, handler.loki, "Nearby", "")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	util.HttpTraceInfo("Nearby accommodations retrieved successfully", span, handler.loki, "Nearby", "")
+
+	w.Header().Set("Vary", "Accept-Language")
+	writeJson(w, http.StatusOK, dto.MapNearbyResponse(hits, r.Header.Get("Accept-Language")))
+}
+
+func (handler *AccommodationHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "get-history-get")
+	defer func() { span.End() }()
+
+	vars := mux.Vars(r)
+	accommodationId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		util.HttpTraceError(err, "invalid accommodation id", span, handler.loki, "GetHistory", "")
+		handleError(w, http.StatusBadRequest, "Invalid accommodation ID")
+		return
+	}
+
+	pageRequest, err := dto.ParsePageRequest(r.URL.Query().Get("page"), r.URL.Query().Get("size"))
+	if err != nil {
+		util.HttpTraceError(err, "invalid page request", span, handler.loki, "GetHistory", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	accommodation, err := handler.service.GetIncludingDeleted(accommodationId, span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to get accommodation", span, handler.loki, "GetHistory", accommodationId.Hex())
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	caller, err := meshVerifiedIdentity(r)
+	if err != nil || !(caller.Subject == accommodation.HostId || caller.hasRole(historyRoles...)) {
+		handleError(w, http.StatusForbidden, "not allowed to view accommodation history")
+		return
+	}
+
+	audits, total, err := handler.service.GetHistory(accommodationId, pageRequest.Page, pageRequest.Size, span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to get accommodation history", span, handler.loki, "GetHistory", accommodationId.Hex())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	util.HttpTraceInfo("Accommodation history retrieved successfully", span, handler.loki, "GetHistory", accommodationId.Hex())
+
+	writeJson(w, http.StatusOK, dto.MapAuditHistoryResponse(accommodationId.Hex(), pageRequest, total, audits))
+}
+
 func (handler *AccommodationHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "delete-delete")
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "delete-delete")
 	defer func() { span.End() }()
 	vars := mux.Vars(r)
 	accommodationId, err := primitive.ObjectIDFromHex(vars["id"])
@@ -152,8 +542,12 @@ func (handler *AccommodationHandler) Delete(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if err := handler.service.Delete(accommodationId, span, handler.loki); err != nil {
-		if err.Error() == "accommodation could not be deleted" {
+	if !handler.authorizeOwner(w, r, accommodationId, span, "Delete") {
+		return
+	}
+
+	if err := handler.service.Delete(ctx, accommodationId, requestActor(r), span, handler.loki); err != nil {
+		if errors.Is(err, domain.ErrHasReservations) {
 			util.HttpTraceError(err, "Accommodation could not be deleted", span, handler.loki, "Delete", accommodationId.Hex())
 			handleError(w, http.StatusPreconditionFailed, "accommodation could not be deleted")
 		} else {
@@ -168,6 +562,45 @@ func (handler *AccommodationHandler) Delete(w http.ResponseWriter, r *http.Reque
 	w.WriteHeader(http.StatusOK)
 }
 
+func (handler *AccommodationHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "restore-post")
+	defer func() { span.End() }()
+
+	vars := mux.Vars(r)
+	accommodationId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		util.HttpTraceError(err, "invalid accommodation id", span, handler.loki, "Restore", "")
+		handleError(w, http.StatusBadRequest, "Invalid accommodation ID")
+		return
+	}
+
+	if !handler.authorizeOwner(w, r, accommodationId, span, "Restore") {
+		return
+	}
+
+	accommodation, err := handler.service.Restore(ctx, accommodationId, handler.deletedRetention, requestActor(r), span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to restore accommodation", span, handler.loki, "Restore", accommodationId.Hex())
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			handleError(w, http.StatusNotFound, "deleted accommodation not found")
+		case errors.Is(err, domain.ErrRetentionExpired):
+			handleError(w, http.StatusGone, "accommodation can no longer be restored")
+		default:
+			handleError(w, http.StatusInternalServerError, "failed to restore accommodation")
+		}
+		return
+	}
+	util.HttpTraceInfo("Accommodation restored successfully", span, handler.loki, "Restore", accommodationId.Hex())
+
+	setETag(w, accommodation.Version)
+	writeJson(w, http.StatusOK, dto.MapAccommodationResponse(*accommodation))
+}
+
+func (handler *AccommodationHandler) GetCancellationPolicies(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, dto.MapCancellationPoliciesResponse())
+}
+
 func (handler *AccommodationHandler) GetHealthCheck(w http.ResponseWriter, r *http.Request) {
 	response := HealthCheckResponse{
 		Size: "Hotel SERVICE OK",
@@ -197,7 +630,10 @@ func (handler *AccommodationHandler) GetByHostId(w http.ResponseWriter, r *http.
 
 	var response []*dto.AccommodationResponse
 	for _, acc := range accommodations {
-		accommodationResponse := dto.MapAccommodationResponse(*acc)
+		if !canView(r, acc) {
+			continue
+		}
+		accommodationResponse := dto.MapLocalizedAccommodationResponse(*acc, r.Header.Get("Accept-Language"))
 		response = append(response, accommodationResponse)
 	}
 
@@ -209,6 +645,7 @@ func (handler *AccommodationHandler) GetByHostId(w http.ResponseWriter, r *http.
 	}
 	util.HttpTraceInfo("Accommodations retrieved successfully by host id", span, handler.loki, "GetByHostId", "")
 
+	w.Header().Set("Vary", "Accept-Language")
 	w.WriteHeader(http.StatusOK)
 	w.Write(jsonResponse)
 }
@@ -231,8 +668,12 @@ func (handler *AccommodationHandler) GetById(w http.ResponseWriter, r *http.Requ
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	if !canView(r, accommodation) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
 
-	response := dto.MapAccommodationResponse(*accommodation)
+	response := dto.MapLocalizedAccommodationResponse(*accommodation, r.Header.Get("Accept-Language"))
 
 	jsonResponse, err := json.Marshal(response)
 	if err != nil {
@@ -242,6 +683,9 @@ func (handler *AccommodationHandler) GetById(w http.ResponseWriter, r *http.Requ
 	}
 	util.HttpTraceInfo("Accommodation retrieved successfully by id", span, handler.loki, "GetById", "")
 
+	setETag(w, accommodation.Version)
+	w.Header().Set("Content-Language", response.Locale)
+	w.Header().Set("Vary", "Accept-Language")
 	w.WriteHeader(http.StatusOK)
 	w.Write(jsonResponse)
 }
@@ -259,7 +703,7 @@ func (handler *AccommodationHandler) GetAll(w http.ResponseWriter, r *http.Reque
 
 	var responses []*dto.AccommodationResponse
 	for _, acc := range accommodations {
-		response := dto.MapAccommodationResponse(*acc)
+		response := dto.MapLocalizedAccommodationResponse(*acc, r.Header.Get("Accept-Language"))
 		responses = append(responses, response)
 	}
 
@@ -272,6 +716,7 @@ func (handler *AccommodationHandler) GetAll(w http.ResponseWriter, r *http.Reque
 
 	util.HttpTraceInfo("All accommodations retrieved successfully", span, handler.loki, "GetAll", "")
 
+	w.Header().Set("Vary", "Accept-Language")
 	w.WriteHeader(http.StatusOK)
 	w.Write(jsonResponse)
 }
@@ -282,7 +727,7 @@ func (handler *AccommodationHandler) GetImagesForAccommodations(w http.ResponseW
 	var accommodationIds []dto.GetImagesRequest
 	if err := json.NewDecoder(r.Body).Decode(&accommodationIds); err != nil {
 		util.HttpTraceError(err, "invalid request payload", span, handler.loki, "GetImagesForAccommodations", "")
-		handleError(w, http.StatusBadRequest, "Invalid request payload")
+		handleBodyError(w, err, "Invalid request payload")
 		return
 	}
 
@@ -306,12 +751,12 @@ func (handler *AccommodationHandler) GetImagesForAccommodations(w http.ResponseW
 }
 
 func (handler *AccommodationHandler) Add(w http.ResponseWriter, r *http.Request) {
-	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "add-post")
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "add-post")
 	defer func() { span.End() }()
 	err := r.ParseMultipartForm(10 << 20)
 	if err != nil {
 		util.HttpTraceError(err, "failed to parse form data", span, handler.loki, "Add", "")
-		handleError(w, http.StatusBadRequest, "failed to parse form data")
+		handleBodyError(w, err, "failed to parse form data")
 		return
 	}
 	var createAccommodationDto dto.AccommodationDto
@@ -322,9 +767,10 @@ func (handler *AccommodationHandler) Add(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := dto.ValidateAccommodationDto(&createAccommodationDto); err != nil {
-		util.HttpTraceError(err, "failed to validate payload", span, handler.loki, "Add", "")
-		handleError(w, http.StatusBadRequest, err.Error())
+	if !handler.validateAccommodationDto(w, &createAccommodationDto, span, "Add") {
+		return
+	}
+	if !authorizeHost(w, r, createAccommodationDto.HostId) {
 		return
 	}
 
@@ -338,8 +784,13 @@ func (handler *AccommodationHandler) Add(w http.ResponseWriter, r *http.Request)
 	newAccommodation := dto.MapAccommodation(&createAccommodationDto)
 	newAccommodation.Photos = photos
 
-	if err := handler.service.Add(newAccommodation, span, handler.loki); err != nil {
+	if err := handler.service.Add(ctx, newAccommodation, requestActor(r), span, handler.loki); err != nil {
 		util.HttpTraceError(err, "failed to add accommodation", span, handler.loki, "Add", "")
+		var incompleteListing *domain.IncompleteListingError
+		if errors.As(err, &incompleteListing) {
+			writeJson(w, http.StatusUnprocessableEntity, dto.IncompleteListingResponse{Problems: incompleteListing.Problems})
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -349,16 +800,31 @@ func (handler *AccommodationHandler) Add(w http.ResponseWriter, r *http.Request)
 
 func (handler *AccommodationHandler) OnDeleteAccommodations(message *kafka.Message) {
 	ctx := context.Background()
-	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(ctx, "on-delete-accommodations")
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(ctx, "on-delete-accommodations")
 	defer func() { span.End() }()
 
 	var deleteAccommodationRequest dto.DeleteAccommodationsRequest
 	if err := json.Unmarshal(message.Value, &deleteAccommodationRequest); err != nil {
 		util.HttpTraceError(err, "failed to marshal data", span, handler.loki, "OnDeleteAccommodations", "")
-		log.Printf("Error unmarshalling rating change request: %v", err)
+		log.Printf("Error unmarshalling delete accommodations request: %v", err)
+		return
 	}
 
-	handler.service.OnDeleteAccommodations(deleteAccommodationRequest.HostId, span, handler.loki)
+	if _, err := handler.hostDeletionSaga.Start(ctx, deleteAccommodationRequest.HostId, span, handler.loki); err != nil {
+		util.HttpTraceError(err, "host deletion saga failed", span, handler.loki, "OnDeleteAccommodations", deleteAccommodationRequest.HostId)
+		log.Printf("Host deletion saga failed for host %s: %v", deleteAccommodationRequest.HostId, err)
+	}
+}
+
+func handleVersionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrVersionConflict):
+		handleError(w, http.StatusPreconditionFailed, "accommodation was modified by another request")
+	case errors.Is(err, domain.ErrNotFound):
+		handleError(w, http.StatusNotFound, "Accommodation not found")
+	default:
+		handleError(w, http.StatusInternalServerError, "failed to fetch accommodation")
+	}
 }
 
 func handleError(w http.ResponseWriter, statusCode int, message string) {
@@ -367,6 +833,17 @@ func handleError(w http.ResponseWriter, statusCode int, message string) {
 	fmt.Fprintf(w, message)
 }
 
+func writeJson(w http.ResponseWriter, statusCode int, data interface{}) {
+	jsonResponse, err := json.Marshal(data)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(jsonResponse)
+}
+
 func handlePhotoUploads(r *http.Request, w http.ResponseWriter, hostId string, span trace.Span, handler *AccommodationHandler) ([]string, error) {
 	var photos []string
 	for _, fileHeader := range r.MultipartForm.File["photos"] {
@@ -378,7 +855,7 @@ func handlePhotoUploads(r *http.Request, w http.ResponseWriter, hostId string, s
 		}
 		defer file.Close()
 
-		uploadDir := "./uploads"
+		uploadDir := domain.UploadDirectory
 		if err := os.MkdirAll(uploadDir, 0777); err != nil {
 			fmt.Println("Error creating directory:", err)
 		}
@@ -402,3 +879,18 @@ func handlePhotoUploads(r *http.Request, w http.ResponseWriter, hostId string, s
 	}
 	return photos, nil
 }
+
+func (handler *AccommodationHandler) validateAccommodationDto(w http.ResponseWriter, accommodationDto *dto.AccommodationDto, span trace.Span, funcName string) bool {
+	knownAmenities, err := handler.amenityService.KnownCodes(span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to load amenity catalogue", span, handler.loki, funcName, "")
+		handleError(w, http.StatusInternalServerError, "failed to load amenity catalogue")
+		return false
+	}
+	if err := dto.ValidateAccommodationDto(accommodationDto, knownAmenities); err != nil {
+		util.HttpTraceError(err, "failed to validate payload", span, handler.loki, funcName, "")
+		handleError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
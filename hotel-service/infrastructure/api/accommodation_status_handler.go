@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/dto"
+	"github.com/ZMS-DevOps/hotel-service/util"
+	"github.com/afiskon/promtail-client/promtail"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+var moderationRoles = []string{"admin"}
+
+type statusChange func(ctx context.Context, id primitive.ObjectID, version int64, actor domain.Actor, span trace.Span, loki promtail.Client) (*domain.Accommodation, error)
+
+func (handler *AccommodationHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	handler.changeStatus(w, r, "publish-post", "Publish", handler.service.Publish, true)
+}
+
+func (handler *AccommodationHandler) Unpublish(w http.ResponseWriter, r *http.Request) {
+	handler.changeStatus(w, r, "unpublish-post", "Unpublish", handler.service.Unpublish, true)
+}
+
+func (handler *AccommodationHandler) Suspend(w http.ResponseWriter, r *http.Request) {
+	if !isModerator(r) {
+		handleError(w, http.StatusForbidden, "only admins can suspend accommodations")
+		return
+	}
+	handler.changeStatus(w, r, "suspend-post", "Suspend", handler.service.Suspend, false)
+}
+
+func (handler *AccommodationHandler) Reinstate(w http.ResponseWriter, r *http.Request) {
+	if !isModerator(r) {
+		handleError(w, http.StatusForbidden, "only admins can reinstate accommodations")
+		return
+	}
+	handler.changeStatus(w, r, "reinstate-post", "Reinstate", handler.service.Reinstate, false)
+}
+
+func (handler *AccommodationHandler) changeStatus(w http.ResponseWriter, r *http.Request, spanName, funcName string, change statusChange, ownerOnly bool) {
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), spanName)
+	defer func() { span.End() }()
+
+	vars := mux.Vars(r)
+	accommodationId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		util.HttpTraceError(err, "invalid accommodation id", span, handler.loki, funcName, "")
+		handleError(w, http.StatusBadRequest, "Invalid accommodation ID")
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		util.HttpTraceError(err, "invalid If-Match header", span, handler.loki, funcName, accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, "Invalid If-Match header")
+		return
+	}
+
+	if ownerOnly && !handler.authorizeOwner(w, r, accommodationId, span, funcName) {
+		return
+	}
+
+	accommodation, err := change(ctx, accommodationId, version, requestActor(r), span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to change accommodation status", span, handler.loki, funcName, accommodationId.Hex())
+		var incompleteListing *domain.IncompleteListingError
+		switch {
+		case errors.As(err, &incompleteListing):
+			writeJson(w, http.StatusUnprocessableEntity, dto.IncompleteListingResponse{Problems: incompleteListing.Problems})
+		case errors.Is(err, domain.ErrNotFound):
+			handleError(w, http.StatusNotFound, "accommodation not found")
+		case errors.Is(err, domain.ErrVersionConflict):
+			handleError(w, http.StatusPreconditionFailed, "accommodation was modified by another request")
+		case errors.Is(err, domain.ErrListingSuspended):
+			handleError(w, http.StatusConflict, "accommodation is suspended")
+		default:
+			handleError(w, http.StatusInternalServerError, "failed to change accommodation status")
+		}
+		return
+	}
+	util.HttpTraceInfo("Accommodation status changed successfully", span, handler.loki, funcName, accommodationId.Hex())
+
+	setETag(w, accommodation.Version)
+	writeJson(w, http.StatusOK, dto.MapAccommodationResponse(*accommodation))
+}
+
+func isModerator(r *http.Request) bool {
+	caller, err := meshVerifiedIdentity(r)
+	return err == nil && caller.hasRole(moderationRoles...)
+}
+
+func isOwner(r *http.Request, accommodation *domain.Accommodation) bool {
+	caller, err := meshVerifiedIdentity(r)
+	return err == nil && caller.Subject == accommodation.HostId
+}
+
+// authorizeOwner lets the listing's host or an admin through and writes the error response otherwise.
+// Deleted listings are included so their host can still restore them.
+func (handler *AccommodationHandler) authorizeOwner(w http.ResponseWriter, r *http.Request, id primitive.ObjectID, span trace.Span, funcName string) bool {
+	if isModerator(r) {
+		return true
+	}
+	current, err := handler.service.GetIncludingDeleted(id, span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to get accommodation", span, handler.loki, funcName, id.Hex())
+		handleVersionError(w, err)
+		return false
+	}
+	if !isOwner(r, current) {
+		handleError(w, http.StatusForbidden, "only the host can change the accommodation")
+		return false
+	}
+	return true
+}
+
+// authorizeHost stops hosts from creating listings for, or handing listings over to, someone else.
+func authorizeHost(w http.ResponseWriter, r *http.Request, hostId string) bool {
+	if isModerator(r) {
+		return true
+	}
+	caller, err := meshVerifiedIdentity(r)
+	if err != nil || caller.Subject != hostId {
+		handleError(w, http.StatusForbidden, "host_id must match the caller")
+		return false
+	}
+	return true
+}
+
+// canView hides drafts and suspended listings from everyone except their host and moderators.
+func canView(r *http.Request, accommodation *domain.Accommodation) bool {
+	return accommodation.Status == domain.Published || isOwner(r, accommodation) || isModerator(r)
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ZMS-DevOps/hotel-service/application"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/dto"
+	"github.com/ZMS-DevOps/hotel-service/util"
+	"github.com/afiskon/promtail-client/promtail"
+	"github.com/gorilla/mux"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"net/http"
+)
+
+type AmenityHandler struct {
+	service       *application.AmenityService
+	traceProvider *sdktrace.TracerProvider
+	loki          promtail.Client
+}
+
+func NewAmenityHandler(service *application.AmenityService, traceProvider *sdktrace.TracerProvider, loki promtail.Client) *AmenityHandler {
+	return &AmenityHandler{
+		service:       service,
+		traceProvider: traceProvider,
+		loki:          loki,
+	}
+}
+
+func (handler *AmenityHandler) Init(router *mux.Router) {
+	router.HandleFunc("/accommodation/amenities", handler.GetAll).Methods("GET")
+	router.HandleFunc("/accommodation/amenities", handler.Add).Methods("POST")
+	router.HandleFunc("/accommodation/amenities/{code}", handler.Get).Methods("GET")
+	router.HandleFunc("/accommodation/amenities/{code}", handler.Update).Methods("PUT")
+	router.HandleFunc("/accommodation/amenities/{code}", handler.Delete).Methods("DELETE")
+}
+
+func (handler *AmenityHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "amenities-get")
+	defer func() { span.End() }()
+
+	amenities, err := handler.service.GetAll(span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to get amenities", span, handler.loki, "GetAll", "")
+		handleError(w, http.StatusInternalServerError, "failed to get amenities")
+		return
+	}
+	util.HttpTraceInfo("Amenities retrieved successfully", span, handler.loki, "GetAll", "")
+	writeJson(w, http.StatusOK, dto.MapAmenitiesResponse(amenities))
+}
+
+func (handler *AmenityHandler) Get(w http.ResponseWriter, r *http.Request) {
+	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "amenity-get")
+	defer func() { span.End() }()
+
+	code := mux.Vars(r)["code"]
+	amenity, err := handler.service.Get(code, span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to get amenity", span, handler.loki, "Get", code)
+		if errors.Is(err, domain.ErrNotFound) {
+			handleError(w, http.StatusNotFound, "amenity not found")
+			return
+		}
+		handleError(w, http.StatusInternalServerError, "failed to get amenity")
+		return
+	}
+	util.HttpTraceInfo("Amenity retrieved successfully", span, handler.loki, "Get", code)
+	writeJson(w, http.StatusOK, dto.MapAmenityResponse(amenity))
+}
+
+func (handler *AmenityHandler) Add(w http.ResponseWriter, r *http.Request) {
+	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "amenity-post")
+	defer func() { span.End() }()
+
+	if !isModerator(r) {
+		handleError(w, http.StatusForbidden, "only admins can manage amenities")
+		return
+	}
+	amenityDto, ok := handler.decodeAmenity(w, r)
+	if !ok {
+		return
+	}
+
+	amenity := dto.MapAmenity(amenityDto)
+	if err := handler.service.Add(amenity, span, handler.loki); err != nil {
+		util.HttpTraceError(err, "failed to add amenity", span, handler.loki, "Add", amenity.Code)
+		if errors.Is(err, domain.ErrAmenityExists) {
+			handleError(w, http.StatusConflict, "amenity already exists")
+			return
+		}
+		handleError(w, http.StatusInternalServerError, "failed to add amenity")
+		return
+	}
+	util.HttpTraceInfo("Amenity added successfully", span, handler.loki, "Add", amenity.Code)
+	writeJson(w, http.StatusCreated, dto.MapAmenityResponse(amenity))
+}
+
+func (handler *AmenityHandler) Update(w http.ResponseWriter, r *http.Request) {
+	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "amenity-put")
+	defer func() { span.End() }()
+
+	if !isModerator(r) {
+		handleError(w, http.StatusForbidden, "only admins can manage amenities")
+		return
+	}
+	code := mux.Vars(r)["code"]
+	amenityDto, ok := handler.decodeAmenity(w, r)
+	if !ok {
+		return
+	}
+	if amenityDto.Code != code {
+		handleError(w, http.StatusBadRequest, "amenity code can't be changed")
+		return
+	}
+
+	amenity := dto.MapAmenity(amenityDto)
+	if err := handler.service.Update(amenity, span, handler.loki); err != nil {
+		util.HttpTraceError(err, "failed to update amenity", span, handler.loki, "Update", code)
+		if errors.Is(err, domain.ErrNotFound) {
+			handleError(w, http.StatusNotFound, "amenity not found")
+			return
+		}
+		handleError(w, http.StatusInternalServerError, "failed to update amenity")
+		return
+	}
+	util.HttpTraceInfo("Amenity updated successfully", span, handler.loki, "Update", code)
+	writeJson(w, http.StatusOK, dto.MapAmenityResponse(amenity))
+}
+
+func (handler *AmenityHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "amenity-delete")
+	defer func() { span.End() }()
+
+	if !isModerator(r) {
+		handleError(w, http.StatusForbidden, "only admins can manage amenities")
+		return
+	}
+	code := mux.Vars(r)["code"]
+	if err := handler.service.Delete(code, span, handler.loki); err != nil {
+		util.HttpTraceError(err, "failed to delete amenity", span, handler.loki, "Delete", code)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			handleError(w, http.StatusNotFound, "amenity not found")
+		case errors.Is(err, domain.ErrAmenityInUse):
+			handleError(w, http.StatusConflict, "amenity is used by accommodations")
+		default:
+			handleError(w, http.StatusInternalServerError, "failed to delete amenity")
+		}
+		return
+	}
+	util.HttpTraceInfo("Amenity deleted successfully", span, handler.loki, "Delete", code)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *AmenityHandler) decodeAmenity(w http.ResponseWriter, r *http.Request) (*dto.AmenityDto, bool) {
+	var amenityDto dto.AmenityDto
+	if err := json.NewDecoder(r.Body).Decode(&amenityDto); err != nil {
+		handleBodyError(w, err, "Invalid request payload")
+		return nil, false
+	}
+	if err := dto.ValidateAmenityDto(&amenityDto); err != nil {
+		handleError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return &amenityDto, true
+}
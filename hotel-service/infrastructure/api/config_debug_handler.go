@@ -0,0 +1,22 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+type ConfigDebugHandler struct {
+	view func() map[string]interface{}
+}
+
+func NewConfigDebugHandler(view func() map[string]interface{}) *ConfigDebugHandler {
+	return &ConfigDebugHandler{view: view}
+}
+
+func (handler *ConfigDebugHandler) Init(router *mux.Router) {
+	router.HandleFunc("/debug/config", handler.Get).Methods("GET")
+}
+
+func (handler *ConfigDebugHandler) Get(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, handler.view())
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyPendingBackoff = "1"
+)
+
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+type Idempotency struct {
+	store domain.IdempotencyStore
+	lease time.Duration
+}
+
+func NewIdempotency(store domain.IdempotencyStore, lease time.Duration) *Idempotency {
+	return &Idempotency{store: store, lease: lease}
+}
+
+func (idempotency *Idempotency) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	if idempotency == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimSpace(r.Header.Get(idempotencyKeyHeader))
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			handleError(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		caller, err := meshVerifiedIdentity(r)
+		if err != nil {
+			handleError(w, http.StatusUnauthorized, "Idempotency-Key requires an authenticated caller")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			handleBodyError(w, err, "failed to read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		requestHash, err := hashRequest(r, body)
+		if err != nil {
+			handleError(w, http.StatusBadRequest, "failed to read request body")
+			return
+		}
+
+		now := time.Now().UTC()
+		record := &domain.IdempotencyRecord{
+			Id:             caller.Subject + ":" + key,
+			RequestHash:    requestHash,
+			Method:         r.Method,
+			Path:           r.URL.Path,
+			CreatedAt:      now,
+			LeaseExpiresAt: now.Add(idempotency.lease),
+		}
+		existing, err := idempotency.store.Reserve(record)
+		if err != nil {
+			log.Printf("Failed to reserve idempotency key: %v", err)
+			handleError(w, http.StatusInternalServerError, "failed to process Idempotency-Key")
+			return
+		}
+		if existing != nil {
+			replay(w, existing, requestHash)
+			return
+		}
+
+		capture := &responseCapture{ResponseWriter: w, statusCode: http.StatusOK}
+		next(capture, r)
+		if capture.statusCode < http.StatusInternalServerError {
+			err = idempotency.store.Complete(record.Id, capture.statusCode, capturedHeaders(w.Header()), capture.body.Bytes())
+			if err == nil {
+				return
+			}
+			log.Printf("Failed to store idempotent response, releasing the key: %v", err)
+		}
+		if err := idempotency.store.Release(record.Id); err != nil {
+			log.Printf("Failed to release idempotency key: %v", err)
+		}
+	}
+}
+
+func replay(w http.ResponseWriter, existing *domain.IdempotencyRecord, requestHash string) {
+	switch {
+	case existing.RequestHash != requestHash:
+		handleError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+	case existing.Status != domain.IdempotencyCompleted:
+		w.Header().Set("Retry-After", idempotencyPendingBackoff)
+		handleError(w, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+	default:
+		for name, values := range existing.Header {
+			w.Header()[name] = values
+		}
+		w.Header().Set(idempotentReplayedHeader, "true")
+		w.WriteHeader(existing.StatusCode)
+		w.Write(existing.Body)
+	}
+}
+
+func hashRequest(r *http.Request, body []byte) (string, error) {
+	hash := sha256.New()
+	for _, part := range []string{r.Method, r.URL.Path, r.Header.Get("If-Match")} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		hash.Write(body)
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		hash.Write([]byte(part.FormName() + "\x00" + part.FileName() + "\x00"))
+		if _, err := io.Copy(hash, part); err != nil {
+			return "", err
+		}
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func capturedHeaders(header http.Header) http.Header {
+	captured := http.Header{}
+	for _, name := range replayedHeaders {
+		if values := header.Values(name); len(values) > 0 {
+			captured[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	return captured
+}
+
+type responseCapture struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (capture *responseCapture) WriteHeader(statusCode int) {
+	if !capture.wroteHeader {
+		capture.statusCode = statusCode
+		capture.wroteHeader = true
+	}
+	capture.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (capture *responseCapture) Write(data []byte) (int, error) {
+	if !capture.wroteHeader {
+		capture.WriteHeader(http.StatusOK)
+	}
+	capture.body.Write(data)
+	return capture.ResponseWriter.Write(data)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"net/http"
+	"strings"
+)
+
+const jwtPayloadHeader = "x-jwt-payload"
+
+var historyRoles = []string{"admin", "support"}
+
+var errMissingIdentity = errors.New("missing caller identity")
+
+type identity struct {
+	Subject     string `json:"sub"`
+	Username    string `json:"preferred_username"`
+	RealmAccess struct {
+		Roles []string `json:"roles"`
+	} `json:"realm_access"`
+}
+
+// callerIdentity only decodes x-jwt-payload; authorization goes through meshVerifiedIdentity.
+func callerIdentity(r *http.Request) (*identity, error) {
+	payload := r.Header.Get(jwtPayloadHeader)
+	if payload == "" {
+		return nil, errMissingIdentity
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(payload, "="))
+	if err != nil {
+		return nil, err
+	}
+	var caller identity
+	if err := json.Unmarshal(decoded, &caller); err != nil {
+		return nil, err
+	}
+	if caller.Subject == "" {
+		return nil, errMissingIdentity
+	}
+	return &caller, nil
+}
+
+// meshVerifiedIdentity only accepts the payload header when it matches the bearer token the sidecar
+// validated, so a client cannot choose its own identity by sending x-jwt-payload without a token.
+func meshVerifiedIdentity(r *http.Request) (*identity, error) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return nil, errMissingIdentity
+	}
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 || strings.TrimRight(segments[1], "=") != strings.TrimRight(r.Header.Get(jwtPayloadHeader), "=") {
+		return nil, errMissingIdentity
+	}
+	return callerIdentity(r)
+}
+
+func requestActor(r *http.Request) domain.Actor {
+	caller, err := meshVerifiedIdentity(r)
+	if err != nil {
+		return domain.Actor{Id: "anonymous"}
+	}
+	return caller.actor()
+}
+
+func (caller *identity) actor() domain.Actor {
+	return domain.Actor{Id: caller.Subject, Username: caller.Username}
+}
+
+func (caller *identity) hasRole(roles ...string) bool {
+	for _, role := range caller.RealmAccess.Roles {
+		for _, expected := range roles {
+			if role == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/gorilla/mux"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	ReadBudget   = "reads"
+	WriteBudget  = "writes"
+	UploadBudget = "uploads"
+)
+
+var (
+	uploadRoutes = map[string]bool{
+		"POST /accommodation":                     true,
+		"PUT /accommodation/{id}":                 true,
+		"POST /accommodation/{id}/price-calendar": true,
+		"POST /accommodation/{id}/rooms":          true,
+		"PUT /accommodation/{id}/rooms/{roomId}":  true,
+	}
+	readRoutes = map[string]bool{
+		"POST /accommodation/images": true,
+	}
+	unlimitedPaths = map[string]bool{
+		"/accommodation/health": true,
+	}
+	rateLimitRejections = expvar.NewMap("rate_limit_rejections")
+)
+
+type RequestBudget struct {
+	Limit        domain.RateLimit
+	MaxBodyBytes int64
+}
+
+type RateLimits struct {
+	limiter     domain.RateLimiter
+	budgets     map[string]RequestBudget
+	trustedHops int
+}
+
+func NewRateLimits(limiter domain.RateLimiter, budgets map[string]RequestBudget, trustedHops int) *RateLimits {
+	return &RateLimits{limiter: limiter, budgets: budgets, trustedHops: trustedHops}
+}
+
+func (limits *RateLimits) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unlimitedPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		name := budgetName(r.Method, routeKey(r))
+		budget := limits.budgets[name]
+
+		if budget.Limit.PerMinute > 0 {
+			allowed, retryAfter, err := limits.limiter.Allow(name+":"+limits.clientKey(r), budget.Limit)
+			if err != nil {
+				log.Printf("Rate limiter unavailable, allowing request: %v", err)
+			} else if !allowed {
+				rateLimitRejections.Add(name, 1)
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil(retryAfter.Seconds())))))
+				handleError(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+		}
+
+		if budget.MaxBodyBytes > 0 {
+			if r.ContentLength > budget.MaxBodyBytes {
+				handleError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", budget.MaxBodyBytes))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, budget.MaxBodyBytes)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (limits *RateLimits) clientKey(r *http.Request) string {
+	if caller, err := meshVerifiedIdentity(r); err == nil {
+		return "user:" + caller.Subject
+	}
+	return "ip:" + limits.clientIP(r)
+}
+
+func (limits *RateLimits) clientIP(r *http.Request) string {
+	if limits.trustedHops > 0 {
+		forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+		if len(forwarded) >= limits.trustedHops {
+			if ip := strings.TrimSpace(forwarded[len(forwarded)-limits.trustedHops]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func routeKey(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return r.Method + " " + r.URL.Path
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return r.Method + " " + r.URL.Path
+	}
+	return r.Method + " " + template
+}
+
+func budgetName(method, route string) string {
+	switch {
+	case uploadRoutes[route]:
+		return UploadBudget
+	case readRoutes[route], method == http.MethodGet, method == http.MethodHead, method == http.MethodOptions:
+		return ReadBudget
+	default:
+		return WriteBudget
+	}
+}
+
+func handleBodyError(w http.ResponseWriter, err error, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		handleError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+		return
+	}
+	handleError(w, http.StatusBadRequest, message)
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/dto"
+	"github.com/ZMS-DevOps/hotel-service/util"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+func (handler *AccommodationHandler) GetRoomTypes(w http.ResponseWriter, r *http.Request) {
+	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "room-types-get")
+	defer func() { span.End() }()
+
+	accommodationId, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		util.HttpTraceError(err, "invalid accommodation id", span, handler.loki, "GetRoomTypes", "")
+		handleError(w, http.StatusBadRequest, "Invalid accommodation ID")
+		return
+	}
+
+	accommodation, err := handler.service.Get(accommodationId, span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to get accommodation", span, handler.loki, "GetRoomTypes", accommodationId.Hex())
+		handleRoomTypeError(w, err)
+		return
+	}
+	if !canView(r, accommodation) {
+		handleRoomTypeError(w, domain.ErrNotFound)
+		return
+	}
+	util.HttpTraceInfo("Room types retrieved successfully", span, handler.loki, "GetRoomTypes", accommodationId.Hex())
+
+	setETag(w, accommodation.Version)
+	writeJson(w, http.StatusOK, dto.MapRoomTypesResponse(accommodation.RoomTypes))
+}
+
+func (handler *AccommodationHandler) GetRoomType(w http.ResponseWriter, r *http.Request) {
+	_, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "room-type-get")
+	defer func() { span.End() }()
+
+	accommodationId, roomTypeId, ok := parseRoomTypeIds(w, r)
+	if !ok {
+		return
+	}
+
+	accommodation, roomType, err := handler.service.GetRoomType(accommodationId, roomTypeId, span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to get room type", span, handler.loki, "GetRoomType", roomTypeId.Hex())
+		handleRoomTypeError(w, err)
+		return
+	}
+	if !canView(r, accommodation) {
+		handleRoomTypeError(w, domain.ErrNotFound)
+		return
+	}
+	util.HttpTraceInfo("Room type retrieved successfully", span, handler.loki, "GetRoomType", roomTypeId.Hex())
+
+	setETag(w, accommodation.Version)
+	writeJson(w, http.StatusOK, dto.MapRoomTypeResponse(roomType))
+}
+
+func (handler *AccommodationHandler) AddRoomType(w http.ResponseWriter, r *http.Request) {
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "room-type-post")
+	defer func() { span.End() }()
+
+	accommodationId, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		util.HttpTraceError(err, "invalid accommodation id", span, handler.loki, "AddRoomType", "")
+		handleError(w, http.StatusBadRequest, "Invalid accommodation ID")
+		return
+	}
+	if !handler.authorizeOwner(w, r, accommodationId, span, "AddRoomType") {
+		return
+	}
+	version, err := parseIfMatch(r)
+	if err != nil {
+		util.HttpTraceError(err, "invalid If-Match header", span, handler.loki, "AddRoomType", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, "Invalid If-Match header")
+		return
+	}
+
+	roomType, ok := handler.decodeRoomType(w, r, accommodationId, version, span)
+	if !ok {
+		return
+	}
+	if roomType.Photos == nil {
+		roomType.Photos = []string{}
+	}
+
+	accommodation, err := handler.service.AddRoomType(ctx, accommodationId, roomType, version, requestActor(r), span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to add room type", span, handler.loki, "AddRoomType", accommodationId.Hex())
+		handleRoomTypeError(w, err)
+		return
+	}
+	util.HttpTraceInfo("Room type added successfully", span, handler.loki, "AddRoomType", roomType.Id.Hex())
+
+	setETag(w, accommodation.Version)
+	writeJson(w, http.StatusCreated, dto.MapRoomTypeResponse(roomType))
+}
+
+func (handler *AccommodationHandler) UpdateRoomType(w http.ResponseWriter, r *http.Request) {
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "room-type-put")
+	defer func() { span.End() }()
+
+	accommodationId, roomTypeId, ok := parseRoomTypeIds(w, r)
+	if !ok {
+		return
+	}
+	if !handler.authorizeOwner(w, r, accommodationId, span, "UpdateRoomType") {
+		return
+	}
+	version, err := parseIfMatch(r)
+	if err != nil {
+		util.HttpTraceError(err, "invalid If-Match header", span, handler.loki, "UpdateRoomType", roomTypeId.Hex())
+		handleError(w, http.StatusBadRequest, "Invalid If-Match header")
+		return
+	}
+
+	roomType, ok := handler.decodeRoomType(w, r, accommodationId, version, span)
+	if !ok {
+		return
+	}
+
+	accommodation, err := handler.service.UpdateRoomType(ctx, accommodationId, roomTypeId, roomType, version, requestActor(r), span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to update room type", span, handler.loki, "UpdateRoomType", roomTypeId.Hex())
+		handleRoomTypeError(w, err)
+		return
+	}
+	util.HttpTraceInfo("Room type updated successfully", span, handler.loki, "UpdateRoomType", roomTypeId.Hex())
+
+	setETag(w, accommodation.Version)
+	writeJson(w, http.StatusOK, dto.MapRoomTypeResponse(roomType))
+}
+
+func (handler *AccommodationHandler) DeleteRoomType(w http.ResponseWriter, r *http.Request) {
+	ctx, span := handler.traceProvider.Tracer(domain.ServiceName).Start(r.Context(), "room-type-delete")
+	defer func() { span.End() }()
+
+	accommodationId, roomTypeId, ok := parseRoomTypeIds(w, r)
+	if !ok {
+		return
+	}
+	if !handler.authorizeOwner(w, r, accommodationId, span, "DeleteRoomType") {
+		return
+	}
+	version, err := parseIfMatch(r)
+	if err != nil {
+		util.HttpTraceError(err, "invalid If-Match header", span, handler.loki, "DeleteRoomType", roomTypeId.Hex())
+		handleError(w, http.StatusBadRequest, "Invalid If-Match header")
+		return
+	}
+
+	accommodation, err := handler.service.DeleteRoomType(ctx, accommodationId, roomTypeId, version, requestActor(r), span, handler.loki)
+	if err != nil {
+		util.HttpTraceError(err, "failed to delete room type", span, handler.loki, "DeleteRoomType", roomTypeId.Hex())
+		handleRoomTypeError(w, err)
+		return
+	}
+	util.HttpTraceInfo("Room type deleted successfully", span, handler.loki, "DeleteRoomType", roomTypeId.Hex())
+
+	setETag(w, accommodation.Version)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *AccommodationHandler) decodeRoomType(w http.ResponseWriter, r *http.Request, accommodationId primitive.ObjectID, version int64, span trace.Span) (*domain.RoomType, bool) {
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		util.HttpTraceError(err, "failed to parse form data", span, handler.loki, "DecodeRoomType", accommodationId.Hex())
+		handleBodyError(w, err, "failed to parse form data")
+		return nil, false
+	}
+	var roomTypeDto dto.RoomTypeDto
+	if err := json.Unmarshal([]byte(r.FormValue("json")), &roomTypeDto); err != nil {
+		util.HttpTraceError(err, "failed to parse json data", span, handler.loki, "DecodeRoomType", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if err := dto.ValidateRoomTypeDto(&roomTypeDto); err != nil {
+		util.HttpTraceError(err, "failed to validate payload", span, handler.loki, "DecodeRoomType", accommodationId.Hex())
+		handleError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if err := handler.service.CheckVersion(accommodationId, version); err != nil {
+		util.HttpTraceError(err, "failed to check accommodation version", span, handler.loki, "DecodeRoomType", accommodationId.Hex())
+		handleRoomTypeError(w, err)
+		return nil, false
+	}
+
+	photos, err := handlePhotoUploads(r, w, accommodationId.Hex(), span, handler)
+	if err != nil {
+		util.HttpTraceError(err, "failed to upload photos", span, handler.loki, "DecodeRoomType", accommodationId.Hex())
+		return nil, false
+	}
+	roomType := dto.MapRoomType(&roomTypeDto)
+	roomType.Photos = photos
+	return roomType, true
+}
+
+func parseRoomTypeIds(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, primitive.ObjectID, bool) {
+	vars := mux.Vars(r)
+	accommodationId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		handleError(w, http.StatusBadRequest, "Invalid accommodation ID")
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	roomTypeId, err := primitive.ObjectIDFromHex(vars["roomId"])
+	if err != nil {
+		handleError(w, http.StatusBadRequest, "Invalid room type ID")
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return accommodationId, roomTypeId, true
+}
+
+func handleRoomTypeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		handleError(w, http.StatusNotFound, "accommodation not found")
+	case errors.Is(err, domain.ErrRoomTypeNotFound):
+		handleError(w, http.StatusNotFound, "room type not found")
+	case errors.Is(err, domain.ErrVersionConflict):
+		handleError(w, http.StatusPreconditionFailed, "accommodation was modified by another request")
+	case errors.Is(err, domain.ErrHasReservations):
+		handleError(w, http.StatusPreconditionFailed, "room type has active reservations")
+	default:
+		handleError(w, http.StatusInternalServerError, "failed to process room type")
+	}
+}
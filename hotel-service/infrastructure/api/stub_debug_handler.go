@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/ZMS-DevOps/hotel-service/application/external"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+type StubDebugHandler struct {
+	recorder *external.StubRecorder
+}
+
+type StubDebugResponse struct {
+	Calls    []external.StubCall `json:"calls"`
+	Failures map[string]string   `json:"failures"`
+}
+
+type StubFailureRequest struct {
+	Code string `json:"code"`
+}
+
+func NewStubDebugHandler(recorder *external.StubRecorder) *StubDebugHandler {
+	return &StubDebugHandler{recorder: recorder}
+}
+
+func (handler *StubDebugHandler) Init(router *mux.Router) {
+	router.HandleFunc("/debug/stubs", handler.Get).Methods("GET")
+	router.HandleFunc("/debug/stubs", handler.Reset).Methods("DELETE")
+	router.HandleFunc("/debug/stubs/failures/{method}", handler.SetFailure).Methods("PUT")
+	router.HandleFunc("/debug/stubs/failures/{method}", handler.ClearFailure).Methods("DELETE")
+}
+
+func (handler *StubDebugHandler) Get(w http.ResponseWriter, r *http.Request) {
+	calls := handler.recorder.Calls()
+	if method := r.URL.Query().Get("method"); method != "" {
+		filtered := []external.StubCall{}
+		for _, call := range calls {
+			if call.Method == method {
+				filtered = append(filtered, call)
+			}
+		}
+		calls = filtered
+	}
+	writeJson(w, http.StatusOK, StubDebugResponse{Calls: calls, Failures: handler.recorder.Failures()})
+}
+
+func (handler *StubDebugHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	handler.recorder.Reset()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *StubDebugHandler) SetFailure(w http.ResponseWriter, r *http.Request) {
+	var request StubFailureRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		handleError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := handler.recorder.SetFailure(mux.Vars(r)["method"], request.Code); err != nil {
+		handleError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJson(w, http.StatusOK, handler.recorder.Failures())
+}
+
+func (handler *StubDebugHandler) ClearFailure(w http.ResponseWriter, r *http.Request) {
+	handler.recorder.ClearFailure(mux.Vars(r)["method"])
+	w.WriteHeader(http.StatusNoContent)
+}
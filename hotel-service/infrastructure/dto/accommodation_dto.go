@@ -6,14 +6,35 @@ import (
 )
 
 type AccommodationDto struct {
-	HostId                                string          `json:"host_id" validate:"required"`
-	Name                                  string          `json:"name" validate:"required"`
-	Location                              string          `json:"location" validate:"required"`
-	Benefits                              []string        `json:"benefits"`
-	Photos                                []string        `json:"photos"`
-	GuestNumber                           GuestNumberDto  `json:"guest_number" validate:"required"`
-	DefaultPrice                          DefaultPriceDto `json:"default_price"  validate:"required"`
-	ReviewReservationRequestAutomatically bool            `json:"review_reservation_request_automatically"`
+	HostId                                string                    `json:"host_id" validate:"required"`
+	Name                                  string                    `json:"name" validate:"required"`
+	Description                           string                    `json:"description,omitempty" validate:"max=5000"`
+	DefaultLocale                         string                    `json:"default_locale,omitempty" validate:"omitempty,bcp47_language_tag"`
+	Translations                          map[string]TranslationDto `json:"translations,omitempty" validate:"dive,keys,bcp47_language_tag,endkeys"`
+	Location                              string                    `json:"location" validate:"required_without=Address"`
+	Address                               *AddressDto               `json:"address,omitempty"`
+	Coordinates                           *CoordinatesDto           `json:"coordinates,omitempty"`
+	Benefits                              []string                  `json:"benefits" validate:"dive,amenity"`
+	Photos                                []string                  `json:"photos"`
+	GuestNumber                           GuestNumberDto            `json:"guest_number" validate:"required"`
+	DefaultPrice                          DefaultPriceDto           `json:"default_price"  validate:"required"`
+	HouseRules                            *HouseRulesDto            `json:"house_rules,omitempty"`
+	CancellationPolicy                    string                    `json:"cancellation_policy,omitempty" validate:"omitempty,oneof=flexible moderate strict non_refundable"`
+	ReviewReservationRequestAutomatically bool                      `json:"review_reservation_request_automatically"`
+	Status                                string                    `json:"status,omitempty" validate:"omitempty,oneof=draft published"`
+}
+
+type AddressDto struct {
+	Street      string `json:"street,omitempty"`
+	City        string `json:"city" validate:"required"`
+	Region      string `json:"region,omitempty"`
+	CountryCode string `json:"country_code" validate:"required,iso3166_1_alpha2"`
+	PostalCode  string `json:"postal_code,omitempty"`
+}
+
+type CoordinatesDto struct {
+	Latitude  *float64 `json:"latitude" validate:"required,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" validate:"required,min=-180,max=180"`
 }
 
 type GuestNumberDto struct {
@@ -26,9 +47,15 @@ type DefaultPriceDto struct {
 	Type  string  `json:"type" validate:"omitempty,oneof=PerApartmentUnit PerGuest"`
 }
 
-func ValidateAccommodationDto(dto *AccommodationDto) error {
+func ValidateAccommodationDto(dto *AccommodationDto, knownAmenities map[string]bool) error {
 	validate := validator.New()
+	validate.RegisterValidation("amenity", func(fl validator.FieldLevel) bool {
+		return knownAmenities[fl.Field().String()]
+	})
 	validate.RegisterStructValidation(validateGuestNumberDto, GuestNumberDto{})
+	validate.RegisterStructValidation(validateAccommodationTranslations, AccommodationDto{})
+	validate.RegisterStructValidation(validateHouseRulesDto, HouseRulesDto{})
+	validate.RegisterValidation("clock", validateClock)
 	validate.RegisterStructValidation(validateDefaultPriceDto, DefaultPriceDto{})
 	validate.RegisterValidation("pricetype", validatePricingType)
 	return validate.Struct(dto)
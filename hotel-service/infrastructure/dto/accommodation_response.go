@@ -5,16 +5,33 @@ import (
 )
 
 type AccommodationResponse struct {
-	Id                                    string            `json:"id"`
-	HostId                                string            `json:"host_id"`
-	Name                                  string            `json:"name"`
-	Location                              string            `json:"location"`
-	Benefits                              []string          `json:"benefits"`
-	Photos                                []string          `json:"photos"`
-	GuestNumber                           GuestNumberDto    `json:"guest_number"`
-	DefaultPrice                          DefaultPriceDto   `json:"default_price"`
-	SpecialPrice                          []SpecialPriceDto `json:"special_price"`
-	ReviewReservationRequestAutomatically bool              `json:"review_reservation_request_automatically"`
+	Id                                    string                      `json:"id"`
+	HostId                                string                      `json:"host_id"`
+	Name                                  string                      `json:"name"`
+	Description                           string                      `json:"description"`
+	Locale                                string                      `json:"locale"`
+	DefaultLocale                         string                      `json:"default_locale"`
+	Translations                          map[string]TranslationDto   `json:"translations,omitempty"`
+	Location                              string                      `json:"location"`
+	Address                               *AddressDto                 `json:"address,omitempty"`
+	Coordinates                           *CoordinatesDto             `json:"coordinates,omitempty"`
+	GeocodingNeedsReview                  bool                        `json:"geocoding_needs_review"`
+	Benefits                              []string                    `json:"benefits"`
+	UnmappedBenefits                      []string                    `json:"unmapped_benefits,omitempty"`
+	Photos                                []string                    `json:"photos"`
+	GuestNumber                           GuestNumberDto              `json:"guest_number"`
+	DefaultPrice                          DefaultPriceDto             `json:"default_price"`
+	SpecialPrice                          []SpecialPriceDto           `json:"special_price"`
+	RoomTypes                             []RoomTypeResponse          `json:"room_types,omitempty"`
+	HouseRules                            *HouseRulesDto              `json:"house_rules,omitempty"`
+	CancellationPolicy                    *CancellationPolicyResponse `json:"cancellation_policy,omitempty"`
+	ReviewReservationRequestAutomatically bool                        `json:"review_reservation_request_automatically"`
+	Status                                string                      `json:"status"`
+	Version                               int64                       `json:"version"`
+}
+
+type IncompleteListingResponse struct {
+	Problems []string `json:"problems"`
 }
 
 type GuestNumber struct {
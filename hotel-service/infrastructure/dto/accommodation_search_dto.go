@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	MaxSearchOffset    = 1000
+	MaxSearchLength    = 200
+)
+
+type AccommodationSearchResultDto struct {
+	Id         string              `json:"id"`
+	HostId     string              `json:"host_id"`
+	Name       string              `json:"name"`
+	Location   string              `json:"location"`
+	Benefits   []string            `json:"benefits"`
+	Status     string              `json:"status"`
+	Score      float64             `json:"score"`
+	Highlights map[string][]string `json:"highlights"`
+}
+
+type AccommodationSearchResponse struct {
+	Query   string                         `json:"query"`
+	Total   int64                          `json:"total"`
+	Offset  int                            `json:"offset"`
+	Limit   int                            `json:"limit"`
+	Results []AccommodationSearchResultDto `json:"results"`
+}
+
+func ParseSearchRequest(text, offset, limit string) (*domain.AccommodationSearchQuery, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("q is required")
+	}
+	if len(text) > MaxSearchLength {
+		return nil, fmt.Errorf("q must not exceed %d characters", MaxSearchLength)
+	}
+	query := &domain.AccommodationSearchQuery{Text: text, Limit: DefaultSearchLimit}
+	if limit != "" {
+		parsedLimit, err := strconv.Atoi(limit)
+		if err != nil || parsedLimit < 1 || parsedLimit > MaxSearchLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", MaxSearchLimit)
+		}
+		query.Limit = parsedLimit
+	}
+	if offset != "" {
+		parsedOffset, err := strconv.Atoi(offset)
+		if err != nil || parsedOffset < 0 || parsedOffset > MaxSearchOffset {
+			return nil, fmt.Errorf("offset must be between 0 and %d", MaxSearchOffset)
+		}
+		query.Offset = parsedOffset
+	}
+	return query, nil
+}
+
+func MapAccommodationSearchResponse(query *domain.AccommodationSearchQuery, hits []*domain.AccommodationSearchHit, total int64) *AccommodationSearchResponse {
+	results := make([]AccommodationSearchResultDto, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, AccommodationSearchResultDto{
+			Id:         hit.Accommodation.Id.Hex(),
+			HostId:     hit.Accommodation.HostId,
+			Name:       hit.Accommodation.Name,
+			Location:   hit.Accommodation.Location,
+			Benefits:   hit.Accommodation.Benefits,
+			Status:     hit.Accommodation.Status.String(),
+			Score:      hit.Score,
+			Highlights: hit.Highlights,
+		})
+	}
+	return &AccommodationSearchResponse{
+		Query:   query.Text,
+		Total:   total,
+		Offset:  query.Offset,
+		Limit:   query.Limit,
+		Results: results,
+	}
+}
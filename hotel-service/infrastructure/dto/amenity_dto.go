@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/go-playground/validator/v10"
+	"regexp"
+)
+
+var amenityCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type AmenityDto struct {
+	Code     string            `json:"code" validate:"required,max=64,amenitycode"`
+	Category string            `json:"category" validate:"required,max=64"`
+	Labels   map[string]string `json:"labels" validate:"required,defaultlabel,dive,keys,bcp47_language_tag,endkeys,required"`
+	Icon     string            `json:"icon" validate:"required,max=64"`
+	Aliases  []string          `json:"aliases,omitempty" validate:"dive,required"`
+}
+
+type AmenityResponse struct {
+	Code     string            `json:"code"`
+	Category string            `json:"category"`
+	Labels   map[string]string `json:"labels"`
+	Icon     string            `json:"icon"`
+	Aliases  []string          `json:"aliases,omitempty"`
+}
+
+func ValidateAmenityDto(dto *AmenityDto) error {
+	validate := validator.New()
+	validate.RegisterValidation("amenitycode", func(fl validator.FieldLevel) bool {
+		return amenityCodePattern.MatchString(fl.Field().String())
+	})
+	validate.RegisterValidation("defaultlabel", func(fl validator.FieldLevel) bool {
+		labels, ok := fl.Field().Interface().(map[string]string)
+		return ok && labels[domain.DefaultLocale] != ""
+	})
+	return validate.Struct(dto)
+}
+
+func MapAmenity(dto *AmenityDto) *domain.Amenity {
+	return &domain.Amenity{
+		Code:     dto.Code,
+		Category: dto.Category,
+		Labels:   dto.Labels,
+		Icon:     dto.Icon,
+		Aliases:  dto.Aliases,
+	}
+}
+
+func MapAmenityResponse(amenity *domain.Amenity) AmenityResponse {
+	return AmenityResponse{
+		Code:     amenity.Code,
+		Category: amenity.Category,
+		Labels:   amenity.Labels,
+		Icon:     amenity.Icon,
+		Aliases:  amenity.Aliases,
+	}
+}
+
+func MapAmenitiesResponse(amenities []*domain.Amenity) []AmenityResponse {
+	response := make([]AmenityResponse, 0, len(amenities))
+	for _, amenity := range amenities {
+		response = append(response, MapAmenityResponse(amenity))
+	}
+	return response
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+type PageRequestDto struct {
+	Page int
+	Size int
+}
+
+type ActorDto struct {
+	Id       string `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+type FieldChangeDto struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AuditEntryDto struct {
+	Id        string           `json:"id"`
+	Actor     ActorDto         `json:"actor"`
+	Action    string           `json:"action"`
+	Timestamp time.Time        `json:"timestamp"`
+	Changes   []FieldChangeDto `json:"changes"`
+}
+
+type AuditHistoryResponse struct {
+	AccommodationId string          `json:"accommodation_id"`
+	Page            int             `json:"page"`
+	Size            int             `json:"size"`
+	Total           int64           `json:"total"`
+	Entries         []AuditEntryDto `json:"entries"`
+}
+
+func ParsePageRequest(page, size string) (*PageRequestDto, error) {
+	request := &PageRequestDto{Page: 1, Size: DefaultPageSize}
+	if page != "" {
+		parsedPage, err := strconv.Atoi(page)
+		if err != nil || parsedPage < 1 {
+			return nil, errors.New("page must be a positive integer")
+		}
+		request.Page = parsedPage
+	}
+	if size != "" {
+		parsedSize, err := strconv.Atoi(size)
+		if err != nil || parsedSize < 1 || parsedSize > MaxPageSize {
+			return nil, fmt.Errorf("size must be between 1 and %d", MaxPageSize)
+		}
+		request.Size = parsedSize
+	}
+	return request, nil
+}
+
+func MapAuditHistoryResponse(accommodationId string, request *PageRequestDto, total int64, audits []*domain.AccommodationAudit) *AuditHistoryResponse {
+	entries := make([]AuditEntryDto, 0, len(audits))
+	for _, audit := range audits {
+		changes := make([]FieldChangeDto, 0, len(audit.Changes))
+		for _, change := range audit.Changes {
+			changes = append(changes, FieldChangeDto{Field: change.Field, Before: change.Before, After: change.After})
+		}
+		entries = append(entries, AuditEntryDto{
+			Id:        audit.Id.Hex(),
+			Actor:     ActorDto{Id: audit.Actor.Id, Username: audit.Actor.Username},
+			Action:    string(audit.Action),
+			Timestamp: audit.Timestamp,
+			Changes:   changes,
+		})
+	}
+	return &AuditHistoryResponse{
+		AccommodationId: accommodationId,
+		Page:            request.Page,
+		Size:            request.Size,
+		Total:           total,
+		Entries:         entries,
+	}
+}
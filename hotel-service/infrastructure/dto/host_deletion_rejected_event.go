@@ -0,0 +1,6 @@
+package dto
+
+type HostDeletionRejectedEvent struct {
+	HostId string `json:"id"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/go-playground/validator/v10"
+	"time"
+)
+
+const ClockLayout = "15:04"
+
+type HouseRulesDto struct {
+	CheckInFrom    string         `json:"check_in_from" validate:"required,clock"`
+	CheckInUntil   string         `json:"check_in_until" validate:"required,clock"`
+	CheckOutUntil  string         `json:"check_out_until" validate:"required,clock"`
+	PetsAllowed    bool           `json:"pets_allowed"`
+	SmokingAllowed bool           `json:"smoking_allowed"`
+	PartiesAllowed bool           `json:"parties_allowed"`
+	QuietHours     *TimeWindowDto `json:"quiet_hours,omitempty"`
+}
+
+type TimeWindowDto struct {
+	From  string `json:"from" validate:"required,clock"`
+	Until string `json:"until" validate:"required,clock"`
+}
+
+type RefundRuleDto struct {
+	DaysBeforeCheckIn int `json:"days_before_check_in"`
+	RefundPercent     int `json:"refund_percent"`
+}
+
+type CancellationPolicyResponse struct {
+	Tier           string          `json:"tier"`
+	RefundSchedule []RefundRuleDto `json:"refund_schedule"`
+}
+
+func validateClock(fl validator.FieldLevel) bool {
+	_, err := time.Parse(ClockLayout, fl.Field().String())
+	return err == nil
+}
+
+// Check-in windows may wrap past midnight (e.g. 15:00 until 02:00), so only an empty window is rejected.
+func validateHouseRulesDto(sl validator.StructLevel) {
+	dto := sl.Current().Interface().(HouseRulesDto)
+	from, fromErr := time.Parse(ClockLayout, dto.CheckInFrom)
+	until, untilErr := time.Parse(ClockLayout, dto.CheckInUntil)
+	if fromErr == nil && untilErr == nil && until.Equal(from) {
+		sl.ReportError(dto.CheckInUntil, "CheckInUntil", "CheckInUntil", "nefield", "CheckInFrom")
+	}
+}
+
+func mapHouseRulesDto(houseRules *HouseRulesDto) *domain.HouseRules {
+	if houseRules == nil {
+		return nil
+	}
+	result := &domain.HouseRules{
+		CheckInFrom:    houseRules.CheckInFrom,
+		CheckInUntil:   houseRules.CheckInUntil,
+		CheckOutUntil:  houseRules.CheckOutUntil,
+		PetsAllowed:    houseRules.PetsAllowed,
+		SmokingAllowed: houseRules.SmokingAllowed,
+		PartiesAllowed: houseRules.PartiesAllowed,
+	}
+	if houseRules.QuietHours != nil {
+		result.QuietHours = &domain.TimeWindow{From: houseRules.QuietHours.From, Until: houseRules.QuietHours.Until}
+	}
+	return result
+}
+
+func mapHouseRules(houseRules *domain.HouseRules) *HouseRulesDto {
+	if houseRules == nil {
+		return nil
+	}
+	result := &HouseRulesDto{
+		CheckInFrom:    houseRules.CheckInFrom,
+		CheckInUntil:   houseRules.CheckInUntil,
+		CheckOutUntil:  houseRules.CheckOutUntil,
+		PetsAllowed:    houseRules.PetsAllowed,
+		SmokingAllowed: houseRules.SmokingAllowed,
+		PartiesAllowed: houseRules.PartiesAllowed,
+	}
+	if houseRules.QuietHours != nil {
+		result.QuietHours = &TimeWindowDto{From: houseRules.QuietHours.From, Until: houseRules.QuietHours.Until}
+	}
+	return result
+}
+
+func MapCancellationPolicyResponse(policy domain.CancellationPolicy) *CancellationPolicyResponse {
+	if !policy.IsValid() {
+		return nil
+	}
+	response := &CancellationPolicyResponse{Tier: string(policy), RefundSchedule: []RefundRuleDto{}}
+	for _, rule := range policy.RefundSchedule() {
+		response.RefundSchedule = append(response.RefundSchedule, RefundRuleDto{DaysBeforeCheckIn: rule.DaysBeforeCheckIn, RefundPercent: rule.RefundPercent})
+	}
+	return response
+}
+
+func MapCancellationPoliciesResponse() []*CancellationPolicyResponse {
+	response := make([]*CancellationPolicyResponse, 0, len(domain.CancellationPolicies))
+	for _, policy := range domain.CancellationPolicies {
+		response = append(response, MapCancellationPolicyResponse(policy))
+	}
+	return response
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
+)
+
+type TranslationDto struct {
+	Name        string `json:"name,omitempty" validate:"required_without=Description"`
+	Description string `json:"description,omitempty" validate:"max=5000"`
+}
+
+func NegotiateLocale(acceptLanguage string, accommodation *domain.Accommodation) string {
+	locales := []string{accommodation.Locale()}
+	for locale := range accommodation.Translations {
+		if locale != accommodation.Locale() {
+			locales = append(locales, locale)
+		}
+	}
+	desired, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(desired) == 0 {
+		return locales[0]
+	}
+
+	supported := make([]language.Tag, 0, len(locales))
+	for _, locale := range locales {
+		supported = append(supported, language.Make(locale))
+	}
+	_, index, confidence := language.NewMatcher(supported).Match(desired...)
+	if confidence == language.No {
+		return locales[0]
+	}
+	return locales[index]
+}
+
+func canonicalLocale(locale string) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return locale
+	}
+	return tag.String()
+}
+
+func mapTranslationsDto(translations map[string]TranslationDto) map[string]domain.Translation {
+	if len(translations) == 0 {
+		return nil
+	}
+	result := make(map[string]domain.Translation, len(translations))
+	for locale, translation := range translations {
+		result[canonicalLocale(locale)] = domain.Translation{Name: translation.Name, Description: translation.Description}
+	}
+	return result
+}
+
+func mapTranslations(translations map[string]domain.Translation) map[string]TranslationDto {
+	if len(translations) == 0 {
+		return nil
+	}
+	result := make(map[string]TranslationDto, len(translations))
+	for locale, translation := range translations {
+		result[locale] = TranslationDto{Name: translation.Name, Description: translation.Description}
+	}
+	return result
+}
+
+func validateAccommodationTranslations(sl validator.StructLevel) {
+	dto := sl.Current().Interface().(AccommodationDto)
+	defaultLocale := domain.DefaultLocale
+	if dto.DefaultLocale != "" {
+		defaultLocale = canonicalLocale(dto.DefaultLocale)
+	}
+	seen := map[string]bool{}
+	for locale := range dto.Translations {
+		canonical := canonicalLocale(locale)
+		if canonical == defaultLocale || seen[canonical] {
+			sl.ReportError(dto.Translations, "Translations", "Translations", "uniquelocale", locale)
+		}
+		seen[canonical] = true
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/ZMS-DevOps/hotel-service/domain"
 	search "github.com/ZMS-DevOps/search-service/proto"
 	"io/ioutil"
+	"strings"
 	"time"
 )
 
@@ -12,16 +13,109 @@ func MapAccommodation(accommodation *AccommodationDto) *domain.Accommodation {
 	accommodationPb := &domain.Accommodation{
 		HostId:                                accommodation.HostId,
 		Name:                                  accommodation.Name,
+		Description:                           accommodation.Description,
+		DefaultLocale:                         mapDefaultLocaleDto(accommodation.DefaultLocale),
+		Translations:                          mapTranslationsDto(accommodation.Translations),
 		Location:                              accommodation.Location,
+		Address:                               mapAddressDto(accommodation.Address),
+		GeoLocation:                           mapCoordinatesDto(accommodation.Coordinates),
 		Benefits:                              accommodation.Benefits,
 		Photos:                                accommodation.Photos,
 		GuestNumber:                           mapGuestNumberDto(&accommodation.GuestNumber),
 		DefaultPrice:                          mapDefaultPriceDto(&accommodation.DefaultPrice),
+		HouseRules:                            mapHouseRulesDto(accommodation.HouseRules),
+		CancellationPolicy:                    domain.CancellationPolicy(accommodation.CancellationPolicy),
 		ReviewReservationRequestAutomatically: accommodation.ReviewReservationRequestAutomatically,
+		Status:                                mapAccommodationStatusDto(accommodation.Status),
+	}
+	if accommodationPb.Location == "" && accommodationPb.Address != nil {
+		accommodationPb.Location = accommodationPb.Address.Format()
 	}
 	return accommodationPb
 }
 
+func mapDefaultLocaleDto(locale string) string {
+	if locale == "" {
+		return ""
+	}
+	return canonicalLocale(locale)
+}
+
+func MapLocalizedAccommodationResponse(accommodation domain.Accommodation, acceptLanguage string) *AccommodationResponse {
+	response := MapAccommodationResponse(accommodation)
+	response.Locale = NegotiateLocale(acceptLanguage, &accommodation)
+	response.Name, response.Description = accommodation.Localize(response.Locale)
+	return response
+}
+
+func mapAddressDto(address *AddressDto) *domain.Address {
+	if address == nil {
+		return nil
+	}
+	return &domain.Address{
+		Street:      address.Street,
+		City:        address.City,
+		Region:      address.Region,
+		CountryCode: strings.ToUpper(address.CountryCode),
+		PostalCode:  address.PostalCode,
+	}
+}
+
+func mapAddress(address *domain.Address) *AddressDto {
+	if address == nil {
+		return nil
+	}
+	return &AddressDto{
+		Street:      address.Street,
+		City:        address.City,
+		Region:      address.Region,
+		CountryCode: address.CountryCode,
+		PostalCode:  address.PostalCode,
+	}
+}
+
+func mapCoordinatesDto(coordinates *CoordinatesDto) *domain.GeoPoint {
+	if coordinates == nil || coordinates.Latitude == nil || coordinates.Longitude == nil {
+		return nil
+	}
+	return domain.NewGeoPoint(*coordinates.Latitude, *coordinates.Longitude)
+}
+
+func mapCoordinates(point *domain.GeoPoint) *CoordinatesDto {
+	if point == nil || len(point.Coordinates) != 2 {
+		return nil
+	}
+	latitude, longitude := point.Latitude(), point.Longitude()
+	return &CoordinatesDto{Latitude: &latitude, Longitude: &longitude}
+}
+
+func mapAccommodationStatusDto(status string) domain.AccommodationStatus {
+	if status == domain.Draft.String() {
+		return domain.Draft
+	}
+	return domain.Published
+}
+
+func MapAccommodationDto(accommodation *domain.Accommodation) *AccommodationDto {
+	return &AccommodationDto{
+		HostId:                                accommodation.HostId,
+		Name:                                  accommodation.Name,
+		Description:                           accommodation.Description,
+		DefaultLocale:                         accommodation.DefaultLocale,
+		Translations:                          mapTranslations(accommodation.Translations),
+		Location:                              accommodation.Location,
+		Address:                               mapAddress(accommodation.Address),
+		Coordinates:                           mapCoordinates(accommodation.GeoLocation),
+		Benefits:                              accommodation.Benefits,
+		Photos:                                accommodation.Photos,
+		GuestNumber:                           mapGuestNumber(&accommodation.GuestNumber),
+		DefaultPrice:                          mapDefaultPrice(&accommodation.DefaultPrice),
+		HouseRules:                            mapHouseRules(accommodation.HouseRules),
+		CancellationPolicy:                    string(accommodation.CancellationPolicy),
+		ReviewReservationRequestAutomatically: accommodation.ReviewReservationRequestAutomatically,
+	}
+}
+
 func mapGuestNumberDto(guestNumber *GuestNumberDto) domain.GuestNumber {
 	return domain.GuestNumber{
 		Min: guestNumber.Min,
@@ -39,7 +133,7 @@ func mapGuestNumber(guestNumber *domain.GuestNumber) GuestNumberDto {
 func mapDefaultPriceDto(defaultPrice *DefaultPriceDto) domain.DefaultPrice {
 	return domain.DefaultPrice{
 		Price: defaultPrice.Price,
-		Type:  *MapPricingType(&defaultPrice.Type),
+		Type:  *domain.ParsePricingType(&defaultPrice.Type),
 	}
 }
 
@@ -50,33 +144,35 @@ func mapDefaultPrice(defaultPrice *domain.DefaultPrice) DefaultPriceDto {
 	}
 }
 
-func MapPricingType(typeOfPayment *string) *domain.PricingType {
-	var pricingType domain.PricingType
-	switch *typeOfPayment {
-	case "PerApartmentUnit":
-		pricingType = domain.PerApartmentUnit
-	case "PerGuest":
-		pricingType = domain.PerGuest
-	default:
-		return nil
-	}
-	return &pricingType
-}
-
 func MapToSearchAccommodation(accommodation *domain.Accommodation) *search.Accommodation {
 	var mainPhoto = ""
 	if (accommodation.Photos != nil) && (len(accommodation.Photos) != 0) {
 		mainPhoto = accommodation.Photos[0]
 	}
+	guestNumber, defaultPrice := accommodation.GuestNumber, accommodation.DefaultPrice
+	for i, roomType := range accommodation.RoomTypes {
+		if i == 0 || roomType.Capacity.Min < guestNumber.Min {
+			guestNumber.Min = roomType.Capacity.Min
+		}
+		if i == 0 || roomType.Capacity.Max > guestNumber.Max {
+			guestNumber.Max = roomType.Capacity.Max
+		}
+		if i == 0 || roomType.DefaultPrice.Price < defaultPrice.Price {
+			defaultPrice = roomType.DefaultPrice
+		}
+		if mainPhoto == "" && len(roomType.Photos) != 0 {
+			mainPhoto = roomType.Photos[0]
+		}
+	}
 	return &search.Accommodation{
 		AccommodationId: accommodation.Id.Hex(),
 		Name:            accommodation.Name,
 		Location:        accommodation.Location,
 		MainPhoto:       mainPhoto,
-		MinGuestNumber:  int32(accommodation.GuestNumber.Min),
-		MaxGuestNumber:  int32(accommodation.GuestNumber.Max),
-		DefaultPrice:    accommodation.DefaultPrice.Price,
-		PriceType:       accommodation.DefaultPrice.Type.String(),
+		MinGuestNumber:  int32(guestNumber.Min),
+		MaxGuestNumber:  int32(guestNumber.Max),
+		DefaultPrice:    defaultPrice.Price,
+		PriceType:       defaultPrice.Type.String(),
 		SpecialPrice:    mapSearchSpecialPrice(accommodation.SpecialPrice),
 		HostId:          accommodation.HostId,
 	}
@@ -141,13 +237,26 @@ func MapAccommodationResponse(accommodation domain.Accommodation) *Accommodation
 	return &AccommodationResponse{
 		Id:                                    accommodation.Id.Hex(),
 		Name:                                  accommodation.Name,
+		Description:                           accommodation.Description,
+		Locale:                                accommodation.Locale(),
+		DefaultLocale:                         accommodation.Locale(),
+		Translations:                          mapTranslations(accommodation.Translations),
 		Location:                              accommodation.Location,
+		Address:                               mapAddress(accommodation.Address),
+		Coordinates:                           mapCoordinates(accommodation.GeoLocation),
+		GeocodingNeedsReview:                  accommodation.GeocodingNeedsReview,
 		Benefits:                              accommodation.Benefits,
+		UnmappedBenefits:                      accommodation.UnmappedBenefits,
 		Photos:                                base64Photos,
 		GuestNumber:                           mapGuestNumber(&accommodation.GuestNumber),
 		DefaultPrice:                          mapDefaultPrice(&accommodation.DefaultPrice),
 		SpecialPrice:                          toSpecialPriceDto(accommodation.SpecialPrice),
+		RoomTypes:                             MapRoomTypesResponse(accommodation.RoomTypes),
 		HostId:                                accommodation.HostId,
+		HouseRules:                            mapHouseRules(accommodation.HouseRules),
+		CancellationPolicy:                    MapCancellationPolicyResponse(accommodation.CancellationPolicy),
 		ReviewReservationRequestAutomatically: accommodation.ReviewReservationRequestAutomatically,
+		Status:                                accommodation.Status.String(),
+		Version:                               accommodation.Version,
 	}
 }
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const MergePatchContentType = "application/merge-patch+json"
+
+func ParseMergePatch(patch []byte) (map[string]interface{}, error) {
+	var patchObject map[string]interface{}
+	if err := json.Unmarshal(patch, &patchObject); err != nil {
+		return nil, err
+	}
+	if patchObject == nil {
+		return nil, errors.New("merge patch must be a JSON object")
+	}
+	return patchObject, nil
+}
+
+func ApplyMergePatch(document []byte, patch map[string]interface{}) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(target, patch))
+}
+
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"strconv"
+)
+
+const (
+	DefaultNearbyRadiusMeters = 5000
+	MaxNearbyRadiusMeters     = 100000
+	DefaultNearbyLimit        = 20
+	MaxNearbyLimit            = 100
+)
+
+type NearbyAccommodationDto struct {
+	Id             string          `json:"id"`
+	HostId         string          `json:"host_id"`
+	Name           string          `json:"name"`
+	Locale         string          `json:"locale"`
+	Location       string          `json:"location"`
+	Address        *AddressDto     `json:"address,omitempty"`
+	Coordinates    *CoordinatesDto `json:"coordinates"`
+	DefaultPrice   DefaultPriceDto `json:"default_price"`
+	DistanceMeters float64         `json:"distance_meters"`
+}
+
+func ParseNearbyRequest(latitude, longitude, radius, limit string) (*domain.NearbyQuery, error) {
+	if latitude == "" || longitude == "" {
+		return nil, errors.New("lat and lng are required")
+	}
+	lat, err := strconv.ParseFloat(latitude, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nil, errors.New("lat must be between -90 and 90")
+	}
+	lng, err := strconv.ParseFloat(longitude, 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return nil, errors.New("lng must be between -180 and 180")
+	}
+	query := &domain.NearbyQuery{Latitude: lat, Longitude: lng, RadiusMeters: DefaultNearbyRadiusMeters, Limit: DefaultNearbyLimit}
+	if radius != "" {
+		parsedRadius, err := strconv.ParseFloat(radius, 64)
+		if err != nil || parsedRadius <= 0 || parsedRadius > MaxNearbyRadiusMeters {
+			return nil, fmt.Errorf("radius must be between 0 and %d meters", MaxNearbyRadiusMeters)
+		}
+		query.RadiusMeters = parsedRadius
+	}
+	if limit != "" {
+		parsedLimit, err := strconv.Atoi(limit)
+		if err != nil || parsedLimit < 1 || parsedLimit > MaxNearbyLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", MaxNearbyLimit)
+		}
+		query.Limit = parsedLimit
+	}
+	return query, nil
+}
+
+func MapNearbyResponse(hits []*domain.NearbyHit, acceptLanguage string) []NearbyAccommodationDto {
+	response := make([]NearbyAccommodationDto, 0, len(hits))
+	for _, hit := range hits {
+		locale := NegotiateLocale(acceptLanguage, hit.Accommodation)
+		name, _ := hit.Accommodation.Localize(locale)
+		response = append(response, NearbyAccommodationDto{
+			Id:             hit.Accommodation.Id.Hex(),
+			HostId:         hit.Accommodation.HostId,
+			Name:           name,
+			Locale:         locale,
+			Location:       hit.Accommodation.Location,
+			Address:        mapAddress(hit.Accommodation.Address),
+			Coordinates:    mapCoordinates(hit.Accommodation.GeoLocation),
+			DefaultPrice:   mapDefaultPrice(&hit.Accommodation.DefaultPrice),
+			DistanceMeters: hit.DistanceMeters,
+		})
+	}
+	return response
+}
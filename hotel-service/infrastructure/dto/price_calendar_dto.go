@@ -0,0 +1,218 @@
+package dto
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const PriceCalendarDateLayout = "2006-01-02"
+
+type PriceCalendarRowDto struct {
+	Date     string   `json:"date"`
+	Price    *float32 `json:"price"`
+	Row      int      `json:"-"`
+	parseErr error
+}
+
+type PriceCalendarRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+type PriceCalendarErrorResponse struct {
+	Errors []PriceCalendarRowError `json:"errors"`
+}
+
+type PriceCalendarResponse struct {
+	DryRun       bool              `json:"dry_run"`
+	Version      int64             `json:"version"`
+	Added        []SpecialPriceDto `json:"added"`
+	Removed      []SpecialPriceDto `json:"removed"`
+	SpecialPrice []SpecialPriceDto `json:"special_price"`
+}
+
+func ParsePriceCalendarJson(reader io.Reader) ([]PriceCalendarRowDto, error) {
+	var rows []PriceCalendarRowDto
+	if err := json.NewDecoder(reader).Decode(&rows); err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Row = i + 1
+	}
+	return rows, nil
+}
+
+func ParsePriceCalendarCsv(reader io.Reader) ([]PriceCalendarRowDto, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []PriceCalendarRowDto
+	for i, record := range records {
+		if i == 0 && len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "date") {
+			continue
+		}
+		rows = append(rows, parsePriceCalendarRecord(record, i+1))
+	}
+	return rows, nil
+}
+
+func parsePriceCalendarRecord(record []string, rowNumber int) PriceCalendarRowDto {
+	row := PriceCalendarRowDto{Row: rowNumber}
+	if len(record) != 2 {
+		row.parseErr = errors.New("expected 2 columns: date, price")
+		return row
+	}
+	row.Date = strings.TrimSpace(record[0])
+	price, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 32)
+	if err != nil {
+		row.parseErr = errors.New("invalid price")
+		return row
+	}
+	parsedPrice := float32(price)
+	row.Price = &parsedPrice
+	return row
+}
+
+func ValidatePriceCalendarRows(rows []PriceCalendarRowDto) []PriceCalendarRowError {
+	if len(rows) == 0 {
+		return []PriceCalendarRowError{{Row: 0, Error: "price calendar is empty"}}
+	}
+	var rowErrors []PriceCalendarRowError
+	seen := map[string]int{}
+	for _, row := range rows {
+		if err := validatePriceCalendarRow(row); err != nil {
+			rowErrors = append(rowErrors, PriceCalendarRowError{Row: row.Row, Error: err.Error()})
+			continue
+		}
+		if previous, ok := seen[row.Date]; ok {
+			rowErrors = append(rowErrors, PriceCalendarRowError{Row: row.Row, Error: fmt.Sprintf("duplicate date, already set in row %d", previous)})
+			continue
+		}
+		seen[row.Date] = row.Row
+	}
+	return rowErrors
+}
+
+func validatePriceCalendarRow(row PriceCalendarRowDto) error {
+	if row.parseErr != nil {
+		return row.parseErr
+	}
+	if row.Date == "" {
+		return errors.New("date is required")
+	}
+	if _, err := time.Parse(PriceCalendarDateLayout, row.Date); err != nil {
+		return fmt.Errorf("invalid date, expected format %s", PriceCalendarDateLayout)
+	}
+	if row.Price == nil {
+		return errors.New("price is required")
+	}
+	if price := float64(*row.Price); math.IsNaN(price) || math.IsInf(price, 0) {
+		return errors.New("price must be a finite number")
+	}
+	if *row.Price < 0 {
+		return errors.New("price must not be negative")
+	}
+	return nil
+}
+
+func MapPriceCalendarRows(rows []PriceCalendarRowDto) []domain.SpecialPrice {
+	var nights []domain.SpecialPrice
+	for _, row := range rows {
+		date, err := time.Parse(PriceCalendarDateLayout, row.Date)
+		if err != nil || row.Price == nil {
+			continue
+		}
+		nights = append(nights, domain.SpecialPrice{
+			Price: *row.Price,
+			DateRange: domain.DateRange{
+				Start: date,
+				End:   date.AddDate(0, 0, 1),
+			},
+		})
+	}
+	return nights
+}
+
+func MapPriceCalendarResponse(dryRun bool, version int64, added, removed, specialPrices []domain.SpecialPrice) *PriceCalendarResponse {
+	return &PriceCalendarResponse{
+		DryRun:       dryRun,
+		Version:      version,
+		Added:        toSpecialPriceDto(added),
+		Removed:      toSpecialPriceDto(removed),
+		SpecialPrice: toSpecialPriceDto(specialPrices),
+	}
+}
+
+const MaxCalendarNights = 366
+
+type CalendarRequestDto struct {
+	From time.Time
+	To   time.Time
+}
+
+type NightlyPriceDto struct {
+	Date        string  `json:"date"`
+	Price       float32 `json:"price"`
+	Source      string  `json:"source"`
+	PricingType string  `json:"pricing_type"`
+}
+
+type CalendarResponse struct {
+	AccommodationId string            `json:"accommodation_id"`
+	From            string            `json:"from"`
+	To              string            `json:"to"`
+	Nights          []NightlyPriceDto `json:"nights"`
+}
+
+func ParseCalendarRequest(from, to string) (*CalendarRequestDto, error) {
+	if from == "" || to == "" {
+		return nil, errors.New("from and to are required")
+	}
+	fromDate, err := time.Parse(PriceCalendarDateLayout, from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from date, expected format %s", PriceCalendarDateLayout)
+	}
+	toDate, err := time.Parse(PriceCalendarDateLayout, to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to date, expected format %s", PriceCalendarDateLayout)
+	}
+	if !toDate.After(fromDate) {
+		return nil, errors.New("to must be after from")
+	}
+	if toDate.Sub(fromDate) > MaxCalendarNights*24*time.Hour {
+		return nil, fmt.Errorf("calendar window must not exceed %d nights", MaxCalendarNights)
+	}
+	return &CalendarRequestDto{From: fromDate, To: toDate}, nil
+}
+
+func MapCalendarResponse(accommodation *domain.Accommodation, request *CalendarRequestDto, nights []domain.NightlyPrice) *CalendarResponse {
+	nightlyPrices := make([]NightlyPriceDto, 0, len(nights))
+	for _, night := range nights {
+		nightlyPrices = append(nightlyPrices, NightlyPriceDto{
+			Date:        night.Date.Format(PriceCalendarDateLayout),
+			Price:       night.Price,
+			Source:      night.Source.String(),
+			PricingType: night.Type.String(),
+		})
+	}
+	return &CalendarResponse{
+		AccommodationId: accommodation.Id.Hex(),
+		From:            request.From.Format(PriceCalendarDateLayout),
+		To:              request.To.Format(PriceCalendarDateLayout),
+		Nights:          nightlyPrices,
+	}
+}
@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/go-playground/validator/v10"
+)
+
+type RoomTypeDto struct {
+	Name         string                `json:"name" validate:"required,max=200"`
+	Capacity     GuestNumberDto        `json:"capacity" validate:"required"`
+	Beds         []BedConfigurationDto `json:"beds" validate:"required,min=1,dive"`
+	UnitCount    int                   `json:"unit_count" validate:"required,min=1"`
+	DefaultPrice DefaultPriceDto       `json:"default_price" validate:"required"`
+	SpecialPrice []RoomSpecialPriceDto `json:"special_price" validate:"dive"`
+}
+
+type BedConfigurationDto struct {
+	Type  string `json:"type" validate:"required,oneof=single double queen king sofa bunk"`
+	Count int    `json:"count" validate:"required,min=1"`
+}
+
+type RoomSpecialPriceDto struct {
+	Price     float32      `json:"price" validate:"min=0"`
+	DateRange DateRangeDTO `json:"date_range" validate:"required"`
+}
+
+type RoomTypeResponse struct {
+	Id           string                `json:"id"`
+	Name         string                `json:"name"`
+	Capacity     GuestNumberDto        `json:"capacity"`
+	Beds         []BedConfigurationDto `json:"beds"`
+	UnitCount    int                   `json:"unit_count"`
+	DefaultPrice DefaultPriceDto       `json:"default_price"`
+	SpecialPrice []SpecialPriceDto     `json:"special_price"`
+	Photos       []string              `json:"photos"`
+}
+
+func ValidateRoomTypeDto(dto *RoomTypeDto) error {
+	validate := validator.New()
+	validate.RegisterStructValidation(validateGuestNumberDto, GuestNumberDto{})
+	validate.RegisterStructValidation(validateDefaultPriceDto, DefaultPriceDto{})
+	validate.RegisterStructValidation(validateRoomSpecialPriceDto, RoomSpecialPriceDto{})
+	validate.RegisterStructValidation(validateRoomTypeSpecialPrices, RoomTypeDto{})
+	return validate.Struct(dto)
+}
+
+func validateRoomSpecialPriceDto(sl validator.StructLevel) {
+	dto := sl.Current().Interface().(RoomSpecialPriceDto)
+	if !dto.DateRange.End.After(dto.DateRange.Start) {
+		sl.ReportError(dto.DateRange.End, "DateRange", "DateRange", "start_before_end", "")
+	}
+}
+
+func validateRoomTypeSpecialPrices(sl validator.StructLevel) {
+	dto := sl.Current().Interface().(RoomTypeDto)
+	for i := range dto.SpecialPrice {
+		for j := i + 1; j < len(dto.SpecialPrice); j++ {
+			a, b := dto.SpecialPrice[i].DateRange, dto.SpecialPrice[j].DateRange
+			if a.Start.Before(b.End) && b.Start.Before(a.End) {
+				sl.ReportError(dto.SpecialPrice, "SpecialPrice", "SpecialPrice", "no_overlap", "")
+				return
+			}
+		}
+	}
+}
+
+func MapRoomType(dto *RoomTypeDto) *domain.RoomType {
+	roomType := &domain.RoomType{
+		Name:         dto.Name,
+		Capacity:     mapGuestNumberDto(&dto.Capacity),
+		Beds:         []domain.BedConfiguration{},
+		UnitCount:    dto.UnitCount,
+		DefaultPrice: mapDefaultPriceDto(&dto.DefaultPrice),
+		SpecialPrice: []domain.SpecialPrice{},
+	}
+	for _, bed := range dto.Beds {
+		roomType.Beds = append(roomType.Beds, domain.BedConfiguration{Type: bed.Type, Count: bed.Count})
+	}
+	for _, price := range dto.SpecialPrice {
+		roomType.SpecialPrice = append(roomType.SpecialPrice, domain.SpecialPrice{
+			Price:     price.Price,
+			DateRange: domain.DateRange{Start: price.DateRange.Start, End: price.DateRange.End},
+		})
+	}
+	return roomType
+}
+
+func MapRoomTypeResponse(roomType *domain.RoomType) RoomTypeResponse {
+	response := RoomTypeResponse{
+		Id:           roomType.Id.Hex(),
+		Name:         roomType.Name,
+		Capacity:     mapGuestNumber(&roomType.Capacity),
+		Beds:         []BedConfigurationDto{},
+		UnitCount:    roomType.UnitCount,
+		DefaultPrice: mapDefaultPrice(&roomType.DefaultPrice),
+		SpecialPrice: toSpecialPriceDto(roomType.SpecialPrice),
+		Photos:       roomType.Photos,
+	}
+	for _, bed := range roomType.Beds {
+		response.Beds = append(response.Beds, BedConfigurationDto{Type: bed.Type, Count: bed.Count})
+	}
+	return response
+}
+
+func MapRoomTypesResponse(roomTypes []domain.RoomType) []RoomTypeResponse {
+	response := make([]RoomTypeResponse, 0, len(roomTypes))
+	for i := range roomTypes {
+		response = append(response, MapRoomTypeResponse(&roomTypes[i]))
+	}
+	return response
+}
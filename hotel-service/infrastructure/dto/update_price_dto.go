@@ -9,6 +9,7 @@ type UpdatePriceDto struct {
 	DateRange *DateRangeDTO `json:"date_range" validate:"omitempty"`
 	Price     *float32      `json:"price" validate:"omitempty,min=0"`
 	Type      *string       `json:"type" validate:"omitempty,oneof=PerApartmentUnit PerGuest"`
+	Version   int64         `json:"-"`
 }
 
 type DateRangeDTO struct {
@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"sort"
+	"sync"
+)
+
+type AccommodationAuditMemoryStore struct {
+	mutex  sync.RWMutex
+	audits []domain.AccommodationAudit
+}
+
+func NewAccommodationAuditMemoryStore() domain.AccommodationAuditStore {
+	return &AccommodationAuditMemoryStore{}
+}
+
+func (store *AccommodationAuditMemoryStore) Insert(audit *domain.AccommodationAudit) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	audit.Id = primitive.NewObjectID()
+	store.audits = append(store.audits, *audit)
+	return nil
+}
+
+func (store *AccommodationAuditMemoryStore) GetByAccommodationId(accommodationId primitive.ObjectID, page, size int) ([]*domain.AccommodationAudit, int64, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	matching := []*domain.AccommodationAudit{}
+	for _, audit := range store.audits {
+		if audit.AccommodationId == accommodationId {
+			audit := audit
+			matching = append(matching, &audit)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		if !matching[i].Timestamp.Equal(matching[j].Timestamp) {
+			return matching[i].Timestamp.After(matching[j].Timestamp)
+		}
+		return matching[i].Id.Hex() > matching[j].Id.Hex()
+	})
+
+	total := int64(len(matching))
+	start := (page - 1) * size
+	if start >= len(matching) {
+		return []*domain.AccommodationAudit{}, total, nil
+	}
+	end := start + size
+	if end > len(matching) {
+		end = len(matching)
+	}
+	return matching[start:end], total, nil
+}
@@ -0,0 +1,66 @@
+package persistence
+
+import (
+	"context"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+)
+
+const (
+	AUDIT_COLLECTION = "accommodation_audit"
+)
+
+type AccommodationAuditMongoDBStore struct {
+	client MongoDatabaseSource
+}
+
+func (store *AccommodationAuditMongoDBStore) audits() *mongo.Collection {
+	return store.client.Database(DATABASE).Collection(AUDIT_COLLECTION)
+}
+
+func NewAccommodationAuditMongoDBStore(client MongoDatabaseSource) domain.AccommodationAuditStore {
+	audits := client.Database(DATABASE).Collection(AUDIT_COLLECTION)
+	_, err := audits.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "accommodation_id", Value: 1}, {Key: "timestamp", Value: -1}},
+	})
+	if err != nil {
+		log.Printf("Failed to create audit index: %v", err)
+	}
+	return &AccommodationAuditMongoDBStore{
+		client: client,
+	}
+}
+
+func (store *AccommodationAuditMongoDBStore) Insert(audit *domain.AccommodationAudit) error {
+	audit.Id = primitive.NewObjectID()
+	_, err := store.audits().InsertOne(context.TODO(), audit)
+	return err
+}
+
+func (store *AccommodationAuditMongoDBStore) GetByAccommodationId(accommodationId primitive.ObjectID, page, size int) ([]*domain.AccommodationAudit, int64, error) {
+	filter := bson.M{"accommodation_id": accommodationId}
+	total, err := store.audits().CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * size)).
+		SetLimit(int64(size))
+	cursor, err := store.audits().Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	audits := []*domain.AccommodationAudit{}
+	if err := cursor.All(context.TODO(), &audits); err != nil {
+		return nil, 0, err
+	}
+	return audits, total, nil
+}
@@ -0,0 +1,371 @@
+package persistence
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const earthRadiusMeters = 6378100
+
+type AccommodationMemoryStore struct {
+	mutex          sync.RWMutex
+	accommodations map[primitive.ObjectID]*domain.Accommodation
+	order          []primitive.ObjectID
+}
+
+func NewAccommodationMemoryStore() domain.AccommodationStore {
+	return &AccommodationMemoryStore{
+		accommodations: map[primitive.ObjectID]*domain.Accommodation{},
+	}
+}
+
+func (store *AccommodationMemoryStore) Get(id primitive.ObjectID) (*domain.Accommodation, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return store.findOne(id, func(accommodation *domain.Accommodation) bool {
+		return accommodation.DeletedAt == nil
+	})
+}
+
+func (store *AccommodationMemoryStore) GetAll() ([]*domain.Accommodation, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return store.filter(func(accommodation *domain.Accommodation) bool {
+		return accommodation.DeletedAt == nil
+	})
+}
+
+func (store *AccommodationMemoryStore) GetByHostId(hostId string) ([]*domain.Accommodation, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return store.filter(func(accommodation *domain.Accommodation) bool {
+		return accommodation.HostId == hostId && accommodation.DeletedAt == nil
+	})
+}
+
+func (store *AccommodationMemoryStore) CountWithAmenity(code string) (int64, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	var count int64
+	for _, accommodation := range store.accommodations {
+		if accommodation.DeletedAt != nil {
+			continue
+		}
+		for _, benefit := range accommodation.Benefits {
+			if benefit == code {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+func (store *AccommodationMemoryStore) GetDeleted(id primitive.ObjectID) (*domain.Accommodation, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return store.findOne(id, func(accommodation *domain.Accommodation) bool {
+		return accommodation.DeletedAt != nil
+	})
+}
+
+func (store *AccommodationMemoryStore) GetDeletedBefore(cutoff time.Time) ([]*domain.Accommodation, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return store.filter(func(accommodation *domain.Accommodation) bool {
+		return accommodation.DeletedAt != nil && accommodation.DeletedAt.Before(cutoff)
+	})
+}
+
+func (store *AccommodationMemoryStore) Insert(accommodation *domain.Accommodation) error {
+	accommodation.Id = primitive.NewObjectID()
+	accommodation.Version = 1
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.insert(accommodation)
+}
+
+func (store *AccommodationMemoryStore) InsertWithId(accommodation *domain.Accommodation) error {
+	if accommodation.Version == 0 {
+		accommodation.Version = 1
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.insert(accommodation)
+}
+
+func (store *AccommodationMemoryStore) insert(accommodation *domain.Accommodation) error {
+	if _, exists := store.accommodations[accommodation.Id]; exists {
+		return domain.ErrDuplicateId
+	}
+	stored, err := copyAccommodation(accommodation)
+	if err != nil {
+		return err
+	}
+	store.accommodations[accommodation.Id] = stored
+	store.order = append(store.order, accommodation.Id)
+	return nil
+}
+
+func (store *AccommodationMemoryStore) DeleteAll() {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.accommodations = map[primitive.ObjectID]*domain.Accommodation{}
+	store.order = nil
+}
+
+func (store *AccommodationMemoryStore) Delete(id primitive.ObjectID) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.remove(func(accommodation *domain.Accommodation) bool {
+		return accommodation.Id == id
+	})
+	return nil
+}
+
+func (store *AccommodationMemoryStore) remove(match func(accommodation *domain.Accommodation) bool) {
+	remaining := store.order[:0]
+	for _, id := range store.order {
+		if match(store.accommodations[id]) {
+			delete(store.accommodations, id)
+			continue
+		}
+		remaining = append(remaining, id)
+	}
+	store.order = remaining
+}
+
+func (store *AccommodationMemoryStore) SoftDelete(id primitive.ObjectID, deletedAt time.Time) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	accommodation, ok := store.accommodations[id]
+	if !ok || accommodation.DeletedAt != nil {
+		return domain.ErrNotFound
+	}
+	deletedAt = deletedAt.UTC().Truncate(time.Millisecond)
+	accommodation.DeletedAt = &deletedAt
+	accommodation.Version++
+	return nil
+}
+
+func (store *AccommodationMemoryStore) Restore(id primitive.ObjectID) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	accommodation, ok := store.accommodations[id]
+	if !ok || accommodation.DeletedAt == nil {
+		return domain.ErrNotFound
+	}
+	accommodation.DeletedAt = nil
+	accommodation.Version++
+	return nil
+}
+
+func (store *AccommodationMemoryStore) Update(id primitive.ObjectID, accommodation *domain.Accommodation) error {
+	return store.updateVersioned(id, accommodation.Version, func(stored *domain.Accommodation) {
+		stored.HostId = accommodation.HostId
+		stored.Name = accommodation.Name
+		stored.Description = accommodation.Description
+		stored.DefaultLocale = accommodation.DefaultLocale
+		stored.Translations = accommodation.Translations
+		stored.Location = accommodation.Location
+		stored.Address = accommodation.Address
+		stored.GeoLocation = accommodation.GeoLocation
+		stored.GeocodingNeedsReview = accommodation.GeocodingNeedsReview
+		stored.Benefits = accommodation.Benefits
+		stored.UnmappedBenefits = accommodation.UnmappedBenefits
+		stored.Photos = accommodation.Photos
+		stored.GuestNumber = accommodation.GuestNumber
+		stored.DefaultPrice = accommodation.DefaultPrice
+		stored.HouseRules = accommodation.HouseRules
+		stored.CancellationPolicy = accommodation.CancellationPolicy
+		stored.ReviewReservationRequestAutomatically = accommodation.ReviewReservationRequestAutomatically
+	})
+}
+
+func (store *AccommodationMemoryStore) UpdateStatus(id primitive.ObjectID, status domain.AccommodationStatus, pendingBookingRegistration bool, version int64) error {
+	return store.updateVersioned(id, version, func(stored *domain.Accommodation) {
+		stored.Status = status
+		stored.PendingBookingRegistration = pendingBookingRegistration
+	})
+}
+
+func (store *AccommodationMemoryStore) UpdatePrices(id primitive.ObjectID, defaultPrice domain.DefaultPrice, specialPrices []domain.SpecialPrice, version int64) error {
+	return store.updateVersioned(id, version, func(stored *domain.Accommodation) {
+		stored.DefaultPrice = defaultPrice
+		stored.SpecialPrice = specialPrices
+	})
+}
+
+func (store *AccommodationMemoryStore) UpdateSpecialPrice(id primitive.ObjectID, updatedSpecialPrices []domain.SpecialPrice, version int64) error {
+	return store.updateVersioned(id, version, func(stored *domain.Accommodation) {
+		stored.SpecialPrice = updatedSpecialPrices
+	})
+}
+
+func (store *AccommodationMemoryStore) UpdateRoomTypes(id primitive.ObjectID, roomTypes []domain.RoomType, version int64) error {
+	return store.updateVersioned(id, version, func(stored *domain.Accommodation) {
+		stored.RoomTypes = roomTypes
+	})
+}
+
+func (store *AccommodationMemoryStore) updateVersioned(id primitive.ObjectID, version int64, update func(stored *domain.Accommodation)) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	current, ok := store.accommodations[id]
+	if !ok || current.DeletedAt != nil {
+		return domain.ErrNotFound
+	}
+	if current.Version != version {
+		return domain.ErrVersionConflict
+	}
+	updated := *current
+	update(&updated)
+	updated.Version++
+	// The copy detaches the stored listing from slices and maps the caller still holds.
+	stored, err := copyAccommodation(&updated)
+	if err != nil {
+		return err
+	}
+	store.accommodations[id] = stored
+	return nil
+}
+
+// Search keeps listings that contain any of the query words and ranks them by the number of words
+// they contain. It does not reproduce the weighting or stemming of the Mongo text index.
+func (store *AccommodationMemoryStore) Search(query domain.AccommodationSearchQuery) ([]*domain.AccommodationSearchHit, int64, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	words := textWords(query.Text)
+	hits := []*domain.AccommodationSearchHit{}
+	for _, id := range store.order {
+		accommodation := store.accommodations[id]
+		if accommodation.DeletedAt != nil || (query.HostId != "" && accommodation.HostId != query.HostId) {
+			continue
+		}
+		matches := matchingWords(searchableWords(accommodation), words)
+		if matches == 0 {
+			continue
+		}
+		result, err := copyAccommodation(accommodation)
+		if err != nil {
+			return nil, 0, err
+		}
+		hits = append(hits, &domain.AccommodationSearchHit{Accommodation: result, Score: float64(matches)})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Score > hits[j].Score
+	})
+	total := int64(len(hits))
+	hits = hits[min(query.Offset, len(hits)):]
+	if query.Limit > 0 && len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+	return hits, total, nil
+}
+
+func (store *AccommodationMemoryStore) Nearby(query domain.NearbyQuery) ([]*domain.NearbyHit, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	hits := []*domain.NearbyHit{}
+	for _, id := range store.order {
+		accommodation := store.accommodations[id]
+		if accommodation.DeletedAt != nil || accommodation.Status != domain.Published || accommodation.GeoLocation == nil || len(accommodation.GeoLocation.Coordinates) != 2 {
+			continue
+		}
+		distance := haversine(query.Latitude, query.Longitude, accommodation.GeoLocation.Latitude(), accommodation.GeoLocation.Longitude())
+		if distance > query.RadiusMeters {
+			continue
+		}
+		result, err := copyAccommodation(accommodation)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, &domain.NearbyHit{Accommodation: result, DistanceMeters: distance})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].DistanceMeters < hits[j].DistanceMeters
+	})
+	if query.Limit > 0 && len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+	return hits, nil
+}
+
+func (store *AccommodationMemoryStore) findOne(id primitive.ObjectID, match func(accommodation *domain.Accommodation) bool) (*domain.Accommodation, error) {
+	accommodation, ok := store.accommodations[id]
+	if !ok || !match(accommodation) {
+		return nil, domain.ErrNotFound
+	}
+	return copyAccommodation(accommodation)
+}
+
+func (store *AccommodationMemoryStore) filter(match func(accommodation *domain.Accommodation) bool) ([]*domain.Accommodation, error) {
+	var accommodations []*domain.Accommodation
+	for _, id := range store.order {
+		accommodation := store.accommodations[id]
+		if !match(accommodation) {
+			continue
+		}
+		result, err := copyAccommodation(accommodation)
+		if err != nil {
+			return nil, err
+		}
+		accommodations = append(accommodations, result)
+	}
+	return accommodations, nil
+}
+
+func copyAccommodation(accommodation *domain.Accommodation) (*domain.Accommodation, error) {
+	data, err := bson.Marshal(accommodation)
+	if err != nil {
+		return nil, err
+	}
+	var result domain.Accommodation
+	if err := bson.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func searchableWords(accommodation *domain.Accommodation) map[string]bool {
+	words := map[string]bool{}
+	values := append([]string{accommodation.Name, accommodation.Description, accommodation.Location}, accommodation.Benefits...)
+	for _, value := range append(values, accommodation.TranslatedText()...) {
+		for _, word := range textWords(value) {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+func matchingWords(searchable map[string]bool, words []string) int {
+	matches := 0
+	for _, word := range words {
+		if searchable[word] {
+			matches++
+		}
+	}
+	return matches
+}
+
+func textWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func haversine(latitude1, longitude1, latitude2, longitude2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	deltaLatitude := toRadians(latitude2 - latitude1)
+	deltaLongitude := toRadians(longitude2 - longitude1)
+	a := math.Sin(deltaLatitude/2)*math.Sin(deltaLatitude/2) +
+		math.Cos(toRadians(latitude1))*math.Cos(toRadians(latitude2))*math.Sin(deltaLongitude/2)*math.Sin(deltaLongitude/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
@@ -2,183 +2,342 @@ package persistence
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"github.com/ZMS-DevOps/hotel-service/domain"
-	"github.com/ZMS-DevOps/hotel-service/infrastructure/dto"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	"time"
 )
 
 const (
 	DATABASE   = "accommodationdb"
 	COLLECTION = "accommodation"
+
+	legacyTextIndex   = "accommodation_text"
+	indexNotFoundCode = 27
 )
 
 type AccommodationMongoDBStore struct {
-	accommodations *mongo.Collection
+	client MongoDatabaseSource
+}
+
+func (store *AccommodationMongoDBStore) accommodations() *mongo.Collection {
+	return store.client.Database(DATABASE).Collection(COLLECTION)
+}
+
+func NewAccommodationMongoDBStore(client MongoDatabaseSource) domain.AccommodationStore {
+	store := &AccommodationMongoDBStore{
+		client: client,
+	}
+	store.ensureIndexes()
+	return store
 }
 
-func NewAccommodationMongoDBStore(client *mongo.Client) domain.AccommodationStore {
-	accommodations := client.Database(DATABASE).Collection(COLLECTION)
-	return &AccommodationMongoDBStore{
-		accommodations: accommodations,
+func (store *AccommodationMongoDBStore) ensureIndexes() {
+	// A collection can only have one text index, so the one without description and translations goes first.
+	_, err := store.accommodations().Indexes().DropOne(context.TODO(), legacyTextIndex)
+	var commandErr mongo.CommandError
+	if err != nil && !(errors.As(err, &commandErr) && commandErr.Code == indexNotFoundCode) {
+		log.Printf("Failed to drop legacy accommodation text index: %v", err)
+	}
+	_, err = store.accommodations().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "name", Value: "text"},
+			{Key: "location", Value: "text"},
+			{Key: "translated_text", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "benefits", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("accommodation_search_text").
+			SetDefaultLanguage("none").
+			SetWeights(bson.D{
+				{Key: "name", Value: 10},
+				{Key: "location", Value: 5},
+				{Key: "translated_text", Value: 3},
+				{Key: "description", Value: 2},
+				{Key: "benefits", Value: 1},
+			}),
+	})
+	if err != nil {
+		log.Printf("Failed to create accommodation text index: %v", err)
+	}
+	_, err = store.accommodations().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "geo_location", Value: "2dsphere"}},
+		Options: options.Index().SetName("accommodation_geo_location"),
+	})
+	if err != nil {
+		log.Printf("Failed to create accommodation geo index: %v", err)
 	}
 }
 
 func (store *AccommodationMongoDBStore) Get(id primitive.ObjectID) (*domain.Accommodation, error) {
-	filter := bson.M{"_id": id}
+	filter := bson.M{"_id": id, "deleted_at": nil}
 	return store.filterOne(filter)
 }
 
 func (store *AccommodationMongoDBStore) GetAll() ([]*domain.Accommodation, error) {
-	filter := bson.D{{}}
+	filter := bson.M{"deleted_at": nil}
 	return store.filter(filter)
 }
 
 func (store *AccommodationMongoDBStore) GetByHostId(hostId string) ([]*domain.Accommodation, error) {
-	filter := bson.M{"host_id": hostId}
+	filter := bson.M{"host_id": hostId, "deleted_at": nil}
+	return store.filter(filter)
+}
+
+func (store *AccommodationMongoDBStore) CountWithAmenity(code string) (int64, error) {
+	count, err := store.accommodations().CountDocuments(context.TODO(), bson.M{"benefits": code, "deleted_at": nil})
+	return count, storeError(err)
+}
+
+func (store *AccommodationMongoDBStore) GetDeleted(id primitive.ObjectID) (*domain.Accommodation, error) {
+	filter := bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}
+	return store.filterOne(filter)
+}
+
+func (store *AccommodationMongoDBStore) GetDeletedBefore(cutoff time.Time) ([]*domain.Accommodation, error) {
+	filter := bson.M{"deleted_at": bson.M{"$ne": nil, "$lt": cutoff}}
 	return store.filter(filter)
 }
 
 func (store *AccommodationMongoDBStore) Insert(accommodation *domain.Accommodation) error {
 	accommodation.Id = primitive.NewObjectID()
-	result, err := store.accommodations.InsertOne(context.TODO(), accommodation)
+	accommodation.Version = 1
+	result, err := store.accommodations().InsertOne(context.TODO(), newSearchableAccommodation(accommodation))
 	if err != nil {
-		return err
+		return storeError(err)
 	}
 	accommodation.Id = result.InsertedID.(primitive.ObjectID)
 	return nil
 }
 
 func (store *AccommodationMongoDBStore) InsertWithId(accommodation *domain.Accommodation) error {
-	result, err := store.accommodations.InsertOne(context.TODO(), accommodation)
+	if accommodation.Version == 0 {
+		accommodation.Version = 1
+	}
+	result, err := store.accommodations().InsertOne(context.TODO(), newSearchableAccommodation(accommodation))
 	if err != nil {
-		return err
+		return storeError(err)
 	}
 	accommodation.Id = result.InsertedID.(primitive.ObjectID)
 	return nil
 }
 
 func (store *AccommodationMongoDBStore) DeleteAll() {
-	store.accommodations.DeleteMany(context.TODO(), bson.D{{}})
+	store.accommodations().DeleteMany(context.TODO(), bson.D{{}})
 }
 
 func (store *AccommodationMongoDBStore) Delete(id primitive.ObjectID) error {
 	filter := bson.M{"_id": id}
-	_, err := store.accommodations.DeleteOne(context.TODO(), filter)
+	_, err := store.accommodations().DeleteOne(context.TODO(), filter)
+	return storeError(err)
+}
+
+func (store *AccommodationMongoDBStore) SoftDelete(id primitive.ObjectID, deletedAt time.Time) error {
+	filter := bson.M{"_id": id, "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"deleted_at": deletedAt}, "$inc": bson.M{"version": 1}}
+	result, err := store.accommodations().UpdateOne(context.TODO(), filter, update)
 	if err != nil {
-		return err
+		return storeError(err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
 	}
 	return nil
 }
 
-func (store *AccommodationMongoDBStore) Update(id primitive.ObjectID, accommodation *domain.Accommodation) error {
-	filter := bson.M{"_id": id}
-
-	updateFields := bson.D{
-		{"host_id", accommodation.HostId},
-		{"review_reservation_request_automatically", accommodation.ReviewReservationRequestAutomatically},
-		{"name", accommodation.Name},
-		{"location", accommodation.Location},
-		{"benefits", accommodation.Benefits},
-		{"photos", accommodation.Photos},
-		{"guest_number", accommodation.GuestNumber},
-		{"default_price", accommodation.DefaultPrice},
-	}
-	update := bson.D{{"$set", updateFields}}
-
-	_, err := store.accommodations.UpdateOne(context.TODO(), filter, update)
+func (store *AccommodationMongoDBStore) Restore(id primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}
+	update := bson.M{"$unset": bson.M{"deleted_at": ""}, "$inc": bson.M{"version": 1}}
+	result, err := store.accommodations().UpdateOne(context.TODO(), filter, update)
 	if err != nil {
-		return err
+		return storeError(err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
 	}
 	return nil
 }
 
-func (store *AccommodationMongoDBStore) filter(filter interface{}) ([]*domain.Accommodation, error) {
-	cursor, err := store.accommodations.Find(context.TODO(), filter)
-	defer cursor.Close(context.TODO())
+func (store *AccommodationMongoDBStore) Update(id primitive.ObjectID, accommodation *domain.Accommodation) error {
+	updateFields := bson.M{
+		"host_id":                accommodation.HostId,
+		"name":                   accommodation.Name,
+		"description":            accommodation.Description,
+		"default_locale":         accommodation.DefaultLocale,
+		"translations":           accommodation.Translations,
+		"translated_text":        accommodation.TranslatedText(),
+		"location":               accommodation.Location,
+		"address":                accommodation.Address,
+		"geo_location":           accommodation.GeoLocation,
+		"geocoding_needs_review": accommodation.GeocodingNeedsReview,
+		"benefits":               accommodation.Benefits,
+		"unmapped_benefits":      accommodation.UnmappedBenefits,
+		"photos":                 accommodation.Photos,
+		"guest_number":           accommodation.GuestNumber,
+		"default_price":          accommodation.DefaultPrice,
+		"house_rules":            accommodation.HouseRules,
+		"cancellation_policy":    accommodation.CancellationPolicy,
+		"review_reservation_request_automatically": accommodation.ReviewReservationRequestAutomatically,
+	}
+	return store.updateVersioned(id, accommodation.Version, updateFields)
+}
+
+func (store *AccommodationMongoDBStore) UpdateStatus(id primitive.ObjectID, status domain.AccommodationStatus, pendingBookingRegistration bool, version int64) error {
+	return store.updateVersioned(id, version, bson.M{"status": status, "pending_booking_registration": pendingBookingRegistration})
+}
 
+func (store *AccommodationMongoDBStore) UpdatePrices(id primitive.ObjectID, defaultPrice domain.DefaultPrice, specialPrices []domain.SpecialPrice, version int64) error {
+	return store.updateVersioned(id, version, bson.M{"default_price": defaultPrice, "special_price": specialPrices})
+}
+
+func (store *AccommodationMongoDBStore) UpdateSpecialPrice(id primitive.ObjectID, updatedSpecialPrices []domain.SpecialPrice, version int64) error {
+	return store.updateVersioned(id, version, bson.M{"special_price": updatedSpecialPrices})
+}
+
+func (store *AccommodationMongoDBStore) UpdateRoomTypes(id primitive.ObjectID, roomTypes []domain.RoomType, version int64) error {
+	return store.updateVersioned(id, version, bson.M{"room_types": roomTypes})
+}
+
+func (store *AccommodationMongoDBStore) updateVersioned(id primitive.ObjectID, version int64, updateFields bson.M) error {
+	update := bson.M{"$set": updateFields, "$inc": bson.M{"version": 1}}
+	result, err := store.accommodations().UpdateOne(context.TODO(), versionFilter(id, version), update)
 	if err != nil {
-		return nil, err
+		return storeError(err)
 	}
-	return decode(cursor)
+	if result.MatchedCount == 0 {
+		if _, err := store.Get(id); err != nil {
+			return err
+		}
+		return domain.ErrVersionConflict
+	}
+	return nil
 }
 
-func (store *AccommodationMongoDBStore) filterOne(filter interface{}) (accommodation *domain.Accommodation, err error) {
-	result := store.accommodations.FindOne(context.TODO(), filter)
-	err = result.Decode(&accommodation)
-	return
+func versionFilter(id primitive.ObjectID, version int64) bson.M {
+	if version == 0 {
+		return bson.M{"_id": id, "deleted_at": nil, "$or": bson.A{
+			bson.M{"version": 0},
+			bson.M{"version": bson.M{"$exists": false}},
+		}}
+	}
+	return bson.M{"_id": id, "deleted_at": nil, "version": version}
 }
 
-func decode(cursor *mongo.Cursor) (accommodations []*domain.Accommodation, err error) {
+func (store *AccommodationMongoDBStore) Search(query domain.AccommodationSearchQuery) ([]*domain.AccommodationSearchHit, int64, error) {
+	filter := bson.M{"$text": bson.M{"$search": query.Text}, "deleted_at": nil}
+	if query.HostId != "" {
+		filter["host_id"] = query.HostId
+	}
+	total, err := store.accommodations().CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return nil, 0, storeError(err)
+	}
+	findOptions := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(query.Offset)).
+		SetLimit(int64(query.Limit))
+	cursor, err := store.accommodations().Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		return nil, 0, storeError(err)
+	}
+	defer cursor.Close(context.TODO())
+
+	hits := []*domain.AccommodationSearchHit{}
 	for cursor.Next(context.TODO()) {
-		var accommodation domain.Accommodation
-		err = cursor.Decode(&accommodation)
-		if err != nil {
-			return
+		var result scoredAccommodation
+		if err := cursor.Decode(&result); err != nil {
+			return nil, 0, err
 		}
-		accommodations = append(accommodations, &accommodation)
+		accommodation := result.Accommodation
+		hits = append(hits, &domain.AccommodationSearchHit{Accommodation: &accommodation, Score: result.Score})
 	}
-	err = cursor.Err()
-	return
+	return hits, total, storeError(cursor.Err())
 }
 
-func (store *AccommodationMongoDBStore) UpdateDefaultPrice(id primitive.ObjectID, price *float32) error {
-	if price == nil {
-		return nil
+func (store *AccommodationMongoDBStore) Nearby(query domain.NearbyQuery) ([]*domain.NearbyHit, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$geoNear", Value: bson.M{
+			"near":          domain.NewGeoPoint(query.Latitude, query.Longitude),
+			"distanceField": "distance",
+			"maxDistance":   query.RadiusMeters,
+			"spherical":     true,
+			"query":         bson.M{"deleted_at": nil, "status": bson.M{"$in": bson.A{domain.Published, nil}}},
+		}}},
+		{{Key: "$limit", Value: query.Limit}},
 	}
+	cursor, err := store.accommodations().Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, storeError(err)
+	}
+	defer cursor.Close(context.TODO())
 
-	filter := bson.M{"_id": id}
-	update := bson.M{"$set": bson.M{"default_price.price": *price}}
-
-	_, err := store.accommodations.UpdateOne(context.TODO(), filter, update)
-	return err
+	hits := []*domain.NearbyHit{}
+	for cursor.Next(context.TODO()) {
+		var result distancedAccommodation
+		if err := cursor.Decode(&result); err != nil {
+			return nil, err
+		}
+		accommodation := result.Accommodation
+		hits = append(hits, &domain.NearbyHit{Accommodation: &accommodation, DistanceMeters: result.Distance})
+	}
+	return hits, storeError(cursor.Err())
 }
 
-func (store *AccommodationMongoDBStore) UpdateSpecialPrice(id primitive.ObjectID, updatedSpecialPrices []domain.SpecialPrice) error {
-	filter := bson.M{"_id": id}
-	update := bson.M{}
+type distancedAccommodation struct {
+	domain.Accommodation `bson:",inline"`
+	Distance             float64 `bson:"distance"`
+}
 
-	_, err := store.GetSpecialPrices(id)
-	if err != nil {
-		return err
-	}
+// searchableAccommodation stores the translations as a flat list next to the listing, because a text
+// index can only cover fields with known paths and the translations are keyed by locale.
+type searchableAccommodation struct {
+	*domain.Accommodation `bson:",inline"`
+	TranslatedText        []string `bson:"translated_text"`
+}
 
-	update = bson.M{"$set": bson.M{"special_price": updatedSpecialPrices}}
+func newSearchableAccommodation(accommodation *domain.Accommodation) *searchableAccommodation {
+	return &searchableAccommodation{Accommodation: accommodation, TranslatedText: accommodation.TranslatedText()}
+}
 
-	_, err = store.accommodations.UpdateOne(context.TODO(), filter, update)
-	return err
+type scoredAccommodation struct {
+	domain.Accommodation `bson:",inline"`
+	Score                float64 `bson:"score"`
 }
 
-func (store *AccommodationMongoDBStore) GetSpecialPrices(id primitive.ObjectID) ([]domain.SpecialPrice, error) {
-	var accommodation domain.Accommodation
-	filter := bson.M{"_id": id}
-	err := store.accommodations.FindOne(context.TODO(), filter).Decode(&accommodation)
+func (store *AccommodationMongoDBStore) filter(filter interface{}) ([]*domain.Accommodation, error) {
+	cursor, err := store.accommodations().Find(context.TODO(), filter)
 	if err != nil {
-		return nil, err
+		return nil, storeError(err)
 	}
-	return accommodation.SpecialPrice, nil
-}
+	defer cursor.Close(context.TODO())
 
-func (store *AccommodationMongoDBStore) DeleteByHostId(hostId string) error {
-	filter := bson.M{"host_id": hostId}
-	_, err := store.accommodations.DeleteMany(context.TODO(), filter)
-	return err
+	accommodations, err := decode(cursor)
+	return accommodations, storeError(err)
 }
 
-func (store *AccommodationMongoDBStore) UpdateTypeOfPayment(id primitive.ObjectID, typeOfPayment *string) error {
-	if typeOfPayment == nil {
-		return fmt.Errorf("payment type is nil but should not be")
+func (store *AccommodationMongoDBStore) filterOne(filter interface{}) (*domain.Accommodation, error) {
+	var accommodation domain.Accommodation
+	if err := store.accommodations().FindOne(context.TODO(), filter).Decode(&accommodation); err != nil {
+		return nil, storeError(err)
 	}
+	return &accommodation, nil
+}
 
-	var pricingType = dto.MapPricingType(typeOfPayment)
-	if pricingType == nil {
-		return fmt.Errorf("payment type is nil but should not be")
+func decode(cursor *mongo.Cursor) (accommodations []*domain.Accommodation, err error) {
+	for cursor.Next(context.TODO()) {
+		var accommodation domain.Accommodation
+		err = cursor.Decode(&accommodation)
+		if err != nil {
+			return
+		}
+		accommodations = append(accommodations, &accommodation)
 	}
-	filter := bson.M{"_id": id}
-	update := bson.M{"$set": bson.M{"default_price.type": pricingType}}
-
-	_, err := store.accommodations.UpdateOne(context.TODO(), filter, update)
-	return err
+	err = cursor.Err()
+	return
 }
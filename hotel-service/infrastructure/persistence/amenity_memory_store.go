@@ -0,0 +1,82 @@
+package persistence
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"sort"
+	"sync"
+)
+
+type AmenityMemoryStore struct {
+	mutex     sync.RWMutex
+	amenities map[string]domain.Amenity
+}
+
+func NewAmenityMemoryStore() domain.AmenityStore {
+	return &AmenityMemoryStore{amenities: map[string]domain.Amenity{}}
+}
+
+func (store *AmenityMemoryStore) Get(code string) (*domain.Amenity, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	amenity, ok := store.amenities[code]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &amenity, nil
+}
+
+func (store *AmenityMemoryStore) GetAll() ([]*domain.Amenity, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	result := make([]*domain.Amenity, 0, len(store.amenities))
+	for _, amenity := range store.amenities {
+		amenity := amenity
+		result = append(result, &amenity)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Category != result[j].Category {
+			return result[i].Category < result[j].Category
+		}
+		return result[i].Code < result[j].Code
+	})
+	return result, nil
+}
+
+func (store *AmenityMemoryStore) Insert(amenity *domain.Amenity) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if _, ok := store.amenities[amenity.Code]; ok {
+		return domain.ErrAmenityExists
+	}
+	store.amenities[amenity.Code] = *amenity
+	return nil
+}
+
+func (store *AmenityMemoryStore) InsertIfMissing(amenity *domain.Amenity) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if _, ok := store.amenities[amenity.Code]; !ok {
+		store.amenities[amenity.Code] = *amenity
+	}
+	return nil
+}
+
+func (store *AmenityMemoryStore) Update(amenity *domain.Amenity) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if _, ok := store.amenities[amenity.Code]; !ok {
+		return domain.ErrNotFound
+	}
+	store.amenities[amenity.Code] = *amenity
+	return nil
+}
+
+func (store *AmenityMemoryStore) Delete(code string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if _, ok := store.amenities[code]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(store.amenities, code)
+	return nil
+}
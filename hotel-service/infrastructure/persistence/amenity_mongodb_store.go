@@ -0,0 +1,89 @@
+package persistence
+
+import (
+	"context"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	AMENITY_COLLECTION = "amenity"
+)
+
+type AmenityMongoDBStore struct {
+	client MongoDatabaseSource
+}
+
+func (store *AmenityMongoDBStore) amenities() *mongo.Collection {
+	return store.client.Database(DATABASE).Collection(AMENITY_COLLECTION)
+}
+
+func NewAmenityMongoDBStore(client MongoDatabaseSource) domain.AmenityStore {
+	return &AmenityMongoDBStore{
+		client: client,
+	}
+}
+
+func (store *AmenityMongoDBStore) Get(code string) (*domain.Amenity, error) {
+	var amenity domain.Amenity
+	if err := store.amenities().FindOne(context.TODO(), bson.M{"_id": code}).Decode(&amenity); err != nil {
+		return nil, storeError(err)
+	}
+	return &amenity, nil
+}
+
+func (store *AmenityMongoDBStore) GetAll() ([]*domain.Amenity, error) {
+	return loadAmenities(store.amenities())
+}
+
+func (store *AmenityMongoDBStore) Insert(amenity *domain.Amenity) error {
+	_, err := store.amenities().InsertOne(context.TODO(), amenity)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrAmenityExists
+	}
+	return err
+}
+
+func (store *AmenityMongoDBStore) InsertIfMissing(amenity *domain.Amenity) error {
+	update := bson.M{"$setOnInsert": amenity}
+	_, err := store.amenities().UpdateOne(context.TODO(), bson.M{"_id": amenity.Code}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (store *AmenityMongoDBStore) Update(amenity *domain.Amenity) error {
+	result, err := store.amenities().ReplaceOne(context.TODO(), bson.M{"_id": amenity.Code}, amenity)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (store *AmenityMongoDBStore) Delete(code string) error {
+	result, err := store.amenities().DeleteOne(context.TODO(), bson.M{"_id": code})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func loadAmenities(amenities *mongo.Collection) ([]*domain.Amenity, error) {
+	cursor, err := amenities.Find(context.TODO(), bson.M{}, options.Find().SetSort(bson.D{{Key: "category", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	result := []*domain.Amenity{}
+	if err := cursor.All(context.TODO(), &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -2,14 +2,232 @@ package persistence
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"expvar"
 	"fmt"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
 )
 
-func GetClient(username, password, host, port string) (*mongo.Client, error) {
-	uri := fmt.Sprintf("mongodb://%s:%s@%s:%s/", username, password, host, port)
+type MongoDatabaseSource interface {
+	Database(name string, opts ...*options.DatabaseOptions) *mongo.Database
+}
+
+var mongoPoolMetrics = expvar.NewMap("mongo_pool")
+
+var mongoPoolMonitor = &event.PoolMonitor{
+	Event: func(poolEvent *event.PoolEvent) {
+		switch poolEvent.Type {
+		case event.ConnectionCreated:
+			mongoPoolMetrics.Add("connections_open", 1)
+			mongoPoolMetrics.Add("connections_created", 1)
+		case event.ConnectionClosed:
+			mongoPoolMetrics.Add("connections_open", -1)
+		case event.GetSucceeded:
+			mongoPoolMetrics.Add("connections_in_use", 1)
+			mongoPoolMetrics.Add("checkouts", 1)
+		case event.ConnectionReturned:
+			mongoPoolMetrics.Add("connections_in_use", -1)
+		case event.GetFailed:
+			mongoPoolMetrics.Add("checkout_failures", 1)
+		case event.PoolCleared:
+			mongoPoolMetrics.Add("pool_cleared", 1)
+		}
+	},
+}
+
+type MongoOptions struct {
+	URI                    string
+	Host                   string
+	Port                   string
+	Username               string
+	Password               string
+	AuthSource             string
+	ReplicaSet             string
+	TLS                    bool
+	TLSCAFile              string
+	MaxPoolSize            uint64
+	MinPoolSize            uint64
+	MaxConnIdleTime        time.Duration
+	ConnectTimeout         time.Duration
+	ServerSelectionTimeout time.Duration
+	SocketTimeout          time.Duration
+	WriteConcern           string
+	ReadConcern            string
+	ReadPreference         string
+}
+
+func (mongoOptions MongoOptions) ClientOptions() (*options.ClientOptions, error) {
+	uri := mongoOptions.URI
+	if uri == "" {
+		uri = fmt.Sprintf("mongodb://%s/", net.JoinHostPort(mongoOptions.Host, mongoOptions.Port))
+	}
+	clientOptions := options.Client().ApplyURI(uri).SetPoolMonitor(mongoPoolMonitor)
+	if mongoOptions.Username != "" {
+		clientOptions.SetAuth(options.Credential{
+			Username:   mongoOptions.Username,
+			Password:   mongoOptions.Password,
+			AuthSource: mongoOptions.AuthSource,
+		})
+	}
+	if mongoOptions.ReplicaSet != "" {
+		clientOptions.SetReplicaSet(mongoOptions.ReplicaSet)
+	}
+	if mongoOptions.TLS {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if mongoOptions.TLSCAFile != "" {
+			pem, err := os.ReadFile(mongoOptions.TLSCAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in %s", mongoOptions.TLSCAFile)
+			}
+		}
+		clientOptions.SetTLSConfig(tlsConfig)
+	}
+	if mongoOptions.MaxPoolSize > 0 && mongoOptions.MinPoolSize > mongoOptions.MaxPoolSize {
+		return nil, fmt.Errorf("min pool size %d exceeds max pool size %d", mongoOptions.MinPoolSize, mongoOptions.MaxPoolSize)
+	}
+	if mongoOptions.MaxPoolSize > 0 {
+		clientOptions.SetMaxPoolSize(mongoOptions.MaxPoolSize)
+	}
+	clientOptions.SetMinPoolSize(mongoOptions.MinPoolSize)
+	if mongoOptions.MaxConnIdleTime > 0 {
+		clientOptions.SetMaxConnIdleTime(mongoOptions.MaxConnIdleTime)
+	}
+	if mongoOptions.ConnectTimeout > 0 {
+		clientOptions.SetConnectTimeout(mongoOptions.ConnectTimeout)
+	}
+	if mongoOptions.ServerSelectionTimeout > 0 {
+		clientOptions.SetServerSelectionTimeout(mongoOptions.ServerSelectionTimeout)
+	}
+	if mongoOptions.SocketTimeout > 0 {
+		clientOptions.SetSocketTimeout(mongoOptions.SocketTimeout)
+	}
+	if mongoOptions.WriteConcern != "" {
+		concern, err := parseWriteConcern(mongoOptions.WriteConcern)
+		if err != nil {
+			return nil, err
+		}
+		clientOptions.SetWriteConcern(concern)
+	}
+	if mongoOptions.ReadConcern != "" {
+		clientOptions.SetReadConcern(readconcern.New(readconcern.Level(mongoOptions.ReadConcern)))
+	}
+	if mongoOptions.ReadPreference != "" {
+		preference, err := parseReadPreference(mongoOptions.ReadPreference)
+		if err != nil {
+			return nil, err
+		}
+		clientOptions.SetReadPreference(preference)
+	}
+	return clientOptions, clientOptions.Validate()
+}
+
+func parseWriteConcern(value string) (*writeconcern.WriteConcern, error) {
+	if value == "majority" {
+		return writeconcern.New(writeconcern.WMajority()), nil
+	}
+	w, err := strconv.Atoi(value)
+	if err != nil || w < 0 {
+		return nil, fmt.Errorf("invalid write concern %q, expected majority or a number of nodes", value)
+	}
+	return writeconcern.New(writeconcern.W(w)), nil
+}
+
+func parseReadPreference(value string) (*readpref.ReadPref, error) {
+	mode, err := readpref.ModeFromString(value)
+	if err != nil {
+		return nil, err
+	}
+	return readpref.New(mode)
+}
+
+func GetClient(mongoOptions MongoOptions) (*mongo.Client, error) {
+	clientOptions, err := mongoOptions.ClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	return mongo.Connect(context.TODO(), clientOptions)
+}
+
+func ConnectWithRetry(mongoOptions MongoOptions, attempts int, backoff, maxBackoff time.Duration) (*mongo.Client, error) {
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var client *mongo.Client
+		if client, err = GetClient(mongoOptions); err != nil {
+			return nil, err
+		}
+		if err = Ping(client, mongoOptions.ServerSelectionTimeout); err == nil {
+			return client, nil
+		}
+		_ = client.Disconnect(context.Background())
+		if attempt < attempts {
+			log.Printf("Mongo is not reachable (attempt %d/%d), retrying in %s: %v", attempt, attempts, backoff, err)
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+	}
+	return nil, fmt.Errorf("mongo is not reachable after %d attempts: %v", attempts, err)
+}
+
+func Ping(client *mongo.Client, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return client.Ping(ctx, nil)
+}
+
+type ReloadableClient struct {
+	mutex        sync.RWMutex
+	client       *mongo.Client
+	drainTimeout time.Duration
+}
+
+func NewReloadableClient(client *mongo.Client, drainTimeout time.Duration) *ReloadableClient {
+	return &ReloadableClient{client: client, drainTimeout: drainTimeout}
+}
+
+func (reloadable *ReloadableClient) Client() *mongo.Client {
+	reloadable.mutex.RLock()
+	defer reloadable.mutex.RUnlock()
+	return reloadable.client
+}
+
+func (reloadable *ReloadableClient) Database(name string, opts ...*options.DatabaseOptions) *mongo.Database {
+	return reloadable.Client().Database(name, opts...)
+}
 
-	options := options.Client().ApplyURI(uri)
-	return mongo.Connect(context.TODO(), options)
+func (reloadable *ReloadableClient) Swap(client *mongo.Client) {
+	reloadable.mutex.Lock()
+	previous := reloadable.client
+	reloadable.client = client
+	reloadable.mutex.Unlock()
+	if previous == nil || previous == client {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reloadable.drainTimeout)
+		defer cancel()
+		if err := previous.Disconnect(ctx); err != nil {
+			log.Printf("Failed to disconnect previous mongo client: %v", err)
+		}
+	}()
 }
@@ -0,0 +1,24 @@
+package persistence
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// storeError maps driver errors to the domain errors the application layer checks for.
+func storeError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return domain.ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return domain.ErrDuplicateId
+	case mongo.IsNetworkError(err) || mongo.IsTimeout(err):
+		return fmt.Errorf("%w: %v", domain.ErrStoreUnavailable, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,39 @@
+package persistence
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"sync"
+	"time"
+)
+
+type GeocodeCacheMemoryStore struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[string]geocodeCacheEntry
+	now     func() time.Time
+}
+
+func NewGeocodeCacheMemoryStore(ttl time.Duration) domain.GeocodeCacheStore {
+	return &GeocodeCacheMemoryStore{ttl: ttl, entries: map[string]geocodeCacheEntry{}, now: time.Now}
+}
+
+func (store *GeocodeCacheMemoryStore) Get(key string) (*domain.GeoPoint, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	entry, ok := store.entries[key]
+	if !ok {
+		return nil, domain.ErrGeocodeNotCached
+	}
+	if store.now().Sub(entry.CreatedAt) >= store.ttl {
+		delete(store.entries, key)
+		return nil, domain.ErrGeocodeNotCached
+	}
+	return entry.Point, nil
+}
+
+func (store *GeocodeCacheMemoryStore) Put(key string, point *domain.GeoPoint) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.entries[key] = geocodeCacheEntry{Key: key, Point: point, CreatedAt: store.now()}
+	return nil
+}
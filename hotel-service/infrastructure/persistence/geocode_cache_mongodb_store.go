@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	"time"
+)
+
+const (
+	GEOCODE_CACHE_COLLECTION = "geocode_cache"
+)
+
+type GeocodeCacheMongoDBStore struct {
+	client MongoDatabaseSource
+}
+
+func (store *GeocodeCacheMongoDBStore) entries() *mongo.Collection {
+	return store.client.Database(DATABASE).Collection(GEOCODE_CACHE_COLLECTION)
+}
+
+type geocodeCacheEntry struct {
+	Key       string           `bson:"_id"`
+	Point     *domain.GeoPoint `bson:"point"`
+	CreatedAt time.Time        `bson:"created_at"`
+}
+
+func NewGeocodeCacheMongoDBStore(client MongoDatabaseSource, ttl time.Duration) domain.GeocodeCacheStore {
+	entries := client.Database(DATABASE).Collection(GEOCODE_CACHE_COLLECTION)
+	_, err := entries.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(ttl.Seconds())),
+	})
+	if err != nil {
+		log.Printf("Failed to create geocode cache index: %v", err)
+	}
+	return &GeocodeCacheMongoDBStore{
+		client: client,
+	}
+}
+
+func (store *GeocodeCacheMongoDBStore) Get(key string) (*domain.GeoPoint, error) {
+	var entry geocodeCacheEntry
+	err := store.entries().FindOne(context.TODO(), bson.M{"_id": key}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrGeocodeNotCached
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry.Point, nil
+}
+
+func (store *GeocodeCacheMongoDBStore) Put(key string, point *domain.GeoPoint) error {
+	entry := geocodeCacheEntry{Key: key, Point: point, CreatedAt: time.Now()}
+	_, err := store.entries().ReplaceOne(context.TODO(), bson.M{"_id": key}, entry, options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"sync"
+	"time"
+)
+
+type HostDeletionSagaMemoryStore struct {
+	mutex sync.RWMutex
+	sagas map[primitive.ObjectID]domain.HostDeletionSaga
+	order []primitive.ObjectID
+}
+
+func NewHostDeletionSagaMemoryStore() domain.HostDeletionSagaStore {
+	return &HostDeletionSagaMemoryStore{sagas: map[primitive.ObjectID]domain.HostDeletionSaga{}}
+}
+
+func (store *HostDeletionSagaMemoryStore) Insert(saga *domain.HostDeletionSaga) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	saga.Id = primitive.NewObjectID()
+	saga.CreatedAt = time.Now()
+	saga.UpdatedAt = saga.CreatedAt
+	saga.Version = 1
+	store.sagas[saga.Id] = copySaga(saga)
+	store.order = append(store.order, saga.Id)
+	return nil
+}
+
+func (store *HostDeletionSagaMemoryStore) Update(saga *domain.HostDeletionSaga) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	current, ok := store.sagas[saga.Id]
+	if !ok || current.Version != saga.Version {
+		return domain.ErrVersionConflict
+	}
+	store.replace(saga)
+	return nil
+}
+
+func (store *HostDeletionSagaMemoryStore) Lease(saga *domain.HostDeletionSaga, until time.Time) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	current, ok := store.sagas[saga.Id]
+	if !ok || current.Version != saga.Version || current.LeaseExpiresAt.After(time.Now()) {
+		return domain.ErrVersionConflict
+	}
+	saga.LeaseExpiresAt = until
+	store.replace(saga)
+	return nil
+}
+
+func (store *HostDeletionSagaMemoryStore) replace(saga *domain.HostDeletionSaga) {
+	saga.Version++
+	saga.UpdatedAt = time.Now()
+	store.sagas[saga.Id] = copySaga(saga)
+}
+
+func (store *HostDeletionSagaMemoryStore) GetUnfinishedByHostId(hostId string) (*domain.HostDeletionSaga, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	for _, id := range store.order {
+		saga := store.sagas[id]
+		if saga.HostId == hostId && isUnfinished(saga.Status) {
+			result := copySaga(&saga)
+			return &result, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (store *HostDeletionSagaMemoryStore) GetUnfinished() ([]*domain.HostDeletionSaga, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	var sagas []*domain.HostDeletionSaga
+	for _, id := range store.order {
+		saga := store.sagas[id]
+		if isUnfinished(saga.Status) {
+			result := copySaga(&saga)
+			sagas = append(sagas, &result)
+		}
+	}
+	return sagas, nil
+}
+
+func isUnfinished(status domain.HostDeletionStatus) bool {
+	for _, unfinished := range unfinishedStatuses() {
+		if unfinished == status {
+			return true
+		}
+	}
+	return false
+}
+
+func copySaga(saga *domain.HostDeletionSaga) domain.HostDeletionSaga {
+	result := *saga
+	result.AccommodationIds = append([]primitive.ObjectID(nil), saga.AccommodationIds...)
+	result.RoomTypeIds = append([]primitive.ObjectID(nil), saga.RoomTypeIds...)
+	result.DeletedIds = append([]primitive.ObjectID(nil), saga.DeletedIds...)
+	return result
+}
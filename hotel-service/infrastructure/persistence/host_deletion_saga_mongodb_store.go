@@ -0,0 +1,105 @@
+package persistence
+
+import (
+	"context"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+const (
+	HOST_DELETION_SAGA_COLLECTION = "host_deletion_saga"
+)
+
+type HostDeletionSagaMongoDBStore struct {
+	client MongoDatabaseSource
+}
+
+func (store *HostDeletionSagaMongoDBStore) sagas() *mongo.Collection {
+	return store.client.Database(DATABASE).Collection(HOST_DELETION_SAGA_COLLECTION)
+}
+
+func NewHostDeletionSagaMongoDBStore(client MongoDatabaseSource) domain.HostDeletionSagaStore {
+	return &HostDeletionSagaMongoDBStore{
+		client: client,
+	}
+}
+
+func (store *HostDeletionSagaMongoDBStore) Insert(saga *domain.HostDeletionSaga) error {
+	saga.Id = primitive.NewObjectID()
+	saga.CreatedAt = time.Now()
+	saga.UpdatedAt = saga.CreatedAt
+	saga.Version = 1
+	_, err := store.sagas().InsertOne(context.TODO(), saga)
+	return storeError(err)
+}
+
+func (store *HostDeletionSagaMongoDBStore) Update(saga *domain.HostDeletionSaga) error {
+	return store.replaceVersioned(sagaVersionFilter(saga), saga)
+}
+
+func (store *HostDeletionSagaMongoDBStore) Lease(saga *domain.HostDeletionSaga, until time.Time) error {
+	leased := *saga
+	leased.LeaseExpiresAt = until
+	filter := sagaVersionFilter(saga)
+	filter["lease_expires_at"] = bson.M{"$not": bson.M{"$gte": time.Now()}}
+	if err := store.replaceVersioned(filter, &leased); err != nil {
+		return err
+	}
+	*saga = leased
+	return nil
+}
+
+func (store *HostDeletionSagaMongoDBStore) replaceVersioned(filter bson.M, saga *domain.HostDeletionSaga) error {
+	updated := *saga
+	updated.Version = saga.Version + 1
+	updated.UpdatedAt = time.Now()
+	result, err := store.sagas().ReplaceOne(context.TODO(), filter, &updated)
+	if err != nil {
+		return storeError(err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrVersionConflict
+	}
+	saga.Version = updated.Version
+	saga.UpdatedAt = updated.UpdatedAt
+	return nil
+}
+
+func (store *HostDeletionSagaMongoDBStore) GetUnfinishedByHostId(hostId string) (*domain.HostDeletionSaga, error) {
+	var saga domain.HostDeletionSaga
+	filter := bson.M{"host_id": hostId, "status": bson.M{"$in": unfinishedStatuses()}}
+	if err := store.sagas().FindOne(context.TODO(), filter).Decode(&saga); err != nil {
+		return nil, storeError(err)
+	}
+	return &saga, nil
+}
+
+func (store *HostDeletionSagaMongoDBStore) GetUnfinished() ([]*domain.HostDeletionSaga, error) {
+	filter := bson.M{"status": bson.M{"$in": unfinishedStatuses()}}
+	cursor, err := store.sagas().Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var sagas []*domain.HostDeletionSaga
+	if err := cursor.All(context.TODO(), &sagas); err != nil {
+		return nil, err
+	}
+	return sagas, nil
+}
+
+// Sagas stored before versioning have no version field, which Mongo matches with null.
+func sagaVersionFilter(saga *domain.HostDeletionSaga) bson.M {
+	if saga.Version == 0 {
+		return bson.M{"_id": saga.Id, "version": bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.M{"_id": saga.Id, "version": saga.Version}
+}
+
+func unfinishedStatuses() bson.A {
+	return bson.A{domain.HostDeletionChecking, domain.HostDeletionDeleting, domain.HostDeletionCompensating, domain.HostDeletionRejecting}
+}
@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const idempotencySweepInterval = time.Minute
+
+type IdempotencyMemoryStore struct {
+	mutex     sync.Mutex
+	ttl       time.Duration
+	records   map[string]domain.IdempotencyRecord
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewIdempotencyMemoryStore(ttl time.Duration) domain.IdempotencyStore {
+	return &IdempotencyMemoryStore{ttl: ttl, records: map[string]domain.IdempotencyRecord{}, lastSweep: time.Now(), now: time.Now}
+}
+
+func (store *IdempotencyMemoryStore) Reserve(record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	now := store.now()
+	store.sweep(now)
+	if existing, ok := store.records[record.Id]; ok && !store.expired(existing, now) && !existing.LeaseExpired(now) {
+		return &existing, nil
+	}
+	record.Status = domain.IdempotencyPending
+	store.records[record.Id] = *record
+	return nil, nil
+}
+
+func (store *IdempotencyMemoryStore) Complete(id string, statusCode int, header http.Header, body []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	record, ok := store.records[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	record.Status = domain.IdempotencyCompleted
+	record.StatusCode = statusCode
+	record.Header = header.Clone()
+	record.Body = append([]byte{}, body...)
+	store.records[id] = record
+	return nil
+}
+
+func (store *IdempotencyMemoryStore) sweep(now time.Time) {
+	if now.Sub(store.lastSweep) < idempotencySweepInterval {
+		return
+	}
+	store.lastSweep = now
+	for id, record := range store.records {
+		if store.expired(record, now) {
+			delete(store.records, id)
+		}
+	}
+}
+
+func (store *IdempotencyMemoryStore) expired(record domain.IdempotencyRecord, now time.Time) bool {
+	return now.Sub(record.CreatedAt) >= store.ttl
+}
+
+func (store *IdempotencyMemoryStore) Release(id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if record, ok := store.records[id]; ok && record.Status == domain.IdempotencyPending {
+		delete(store.records, id)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	IDEMPOTENCY_COLLECTION = "idempotency_keys"
+)
+
+type IdempotencyMongoDBStore struct {
+	client MongoDatabaseSource
+}
+
+func (store *IdempotencyMongoDBStore) records() *mongo.Collection {
+	return store.client.Database(DATABASE).Collection(IDEMPOTENCY_COLLECTION)
+}
+
+func NewIdempotencyMongoDBStore(client MongoDatabaseSource, ttl time.Duration) domain.IdempotencyStore {
+	records := client.Database(DATABASE).Collection(IDEMPOTENCY_COLLECTION)
+	_, err := records.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(ttl.Seconds())),
+	})
+	if err != nil {
+		log.Printf("Failed to create idempotency index: %v", err)
+	}
+	return &IdempotencyMongoDBStore{
+		client: client,
+	}
+}
+
+func (store *IdempotencyMongoDBStore) Reserve(record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	record.Status = domain.IdempotencyPending
+	_, err := store.records().InsertOne(context.TODO(), record)
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+	expiredLease := bson.M{"_id": record.Id, "status": domain.IdempotencyPending, "lease_expires_at": bson.M{"$lte": time.Now().UTC()}}
+	result, err := store.records().ReplaceOne(context.TODO(), expiredLease, record)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount > 0 {
+		return nil, nil
+	}
+	var existing domain.IdempotencyRecord
+	if err := store.records().FindOne(context.TODO(), bson.M{"_id": record.Id}).Decode(&existing); err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+func (store *IdempotencyMongoDBStore) Complete(id string, statusCode int, header http.Header, body []byte) error {
+	result, err := store.records().UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"status":      domain.IdempotencyCompleted,
+		"status_code": statusCode,
+		"header":      header,
+		"body":        body,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (store *IdempotencyMongoDBStore) Release(id string) error {
+	_, err := store.records().DeleteOne(context.TODO(), bson.M{"_id": id, "status": domain.IdempotencyPending})
+	return err
+}
@@ -0,0 +1,157 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"reflect"
+	"time"
+)
+
+const MIGRATION_COLLECTION = "migrations"
+
+type migration struct {
+	name string
+	run  func(database *mongo.Database) (int, error)
+}
+
+type appliedMigration struct {
+	Name      string    `bson:"_id"`
+	Migrated  int       `bson:"migrated"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+var migrations = []migration{
+	{name: "structured_address_from_legacy_location", run: migrateLegacyLocations},
+	{name: "amenity_codes_from_free_text_benefits", run: migrateBenefitsToAmenities},
+	{name: "translated_text_for_search", run: migrateTranslatedText},
+}
+
+func Migrate(client *mongo.Client) error {
+	database := client.Database(DATABASE)
+	applied := database.Collection(MIGRATION_COLLECTION)
+	for _, m := range migrations {
+		err := applied.FindOne(context.TODO(), bson.M{"_id": m.name}).Err()
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		migrated, err := m.run(database)
+		if err != nil {
+			return err
+		}
+		record := appliedMigration{Name: m.name, Migrated: migrated, AppliedAt: time.Now()}
+		if _, err := applied.InsertOne(context.TODO(), record); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		log.Printf("Migration %s updated %d documents", m.name, migrated)
+	}
+	return nil
+}
+
+func migrateLegacyLocations(database *mongo.Database) (int, error) {
+	accommodations := database.Collection(COLLECTION)
+	filter := bson.M{"address": bson.M{"$exists": false}, "location": bson.M{"$nin": bson.A{"", nil}}}
+	cursor, err := accommodations.Find(context.TODO(), filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	migrated := 0
+	for cursor.Next(context.TODO()) {
+		var document struct {
+			Id       primitive.ObjectID `bson:"_id"`
+			Location string             `bson:"location"`
+		}
+		if err := cursor.Decode(&document); err != nil {
+			return migrated, err
+		}
+		address, ok := domain.ParseLegacyLocation(document.Location)
+		if !ok {
+			continue
+		}
+		update := bson.M{"$set": bson.M{"address": address}, "$inc": bson.M{"version": 1}}
+		if _, err := accommodations.UpdateOne(context.TODO(), bson.M{"_id": document.Id}, update); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
+
+func migrateBenefitsToAmenities(database *mongo.Database) (int, error) {
+	catalogue, err := loadAmenities(database.Collection(AMENITY_COLLECTION))
+	if err != nil {
+		return 0, err
+	}
+	matcher := domain.NewAmenityMatcher(catalogue)
+
+	accommodations := database.Collection(COLLECTION)
+	cursor, err := accommodations.Find(context.TODO(), bson.M{"benefits.0": bson.M{"$exists": true}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	migrated := 0
+	for cursor.Next(context.TODO()) {
+		var document struct {
+			Id               primitive.ObjectID `bson:"_id"`
+			Benefits         []string           `bson:"benefits"`
+			UnmappedBenefits []string           `bson:"unmapped_benefits"`
+		}
+		if err := cursor.Decode(&document); err != nil {
+			return migrated, err
+		}
+		codes, unmapped := matcher.Match(document.Benefits)
+		if len(unmapped) == 0 && reflect.DeepEqual(codes, document.Benefits) {
+			continue
+		}
+		update := bson.M{"$set": bson.M{"benefits": codes}, "$inc": bson.M{"version": 1}}
+		if len(unmapped) > 0 {
+			update["$addToSet"] = bson.M{"unmapped_benefits": bson.M{"$each": unmapped}}
+		}
+		if _, err := accommodations.UpdateOne(context.TODO(), bson.M{"_id": document.Id}, update); err != nil {
+			return migrated, err
+		}
+		if len(unmapped) > 0 {
+			log.Printf("Accommodation %s has benefits without an amenity code: %v", document.Id.Hex(), unmapped)
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
+
+func migrateTranslatedText(database *mongo.Database) (int, error) {
+	accommodations := database.Collection(COLLECTION)
+	cursor, err := accommodations.Find(context.TODO(), bson.M{"translations": bson.M{"$exists": true}, "translated_text": bson.M{"$exists": false}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	migrated := 0
+	for cursor.Next(context.TODO()) {
+		var document struct {
+			Id           primitive.ObjectID            `bson:"_id"`
+			Translations map[string]domain.Translation `bson:"translations"`
+		}
+		if err := cursor.Decode(&document); err != nil {
+			return migrated, err
+		}
+		accommodation := domain.Accommodation{Translations: document.Translations}
+		update := bson.M{"$set": bson.M{"translated_text": accommodation.TranslatedText()}}
+		if _, err := accommodations.UpdateOne(context.TODO(), bson.M{"_id": document.Id}, update); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
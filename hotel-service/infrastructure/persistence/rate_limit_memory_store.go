@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"sync"
+	"time"
+)
+
+const rateLimitSweepInterval = time.Minute
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	idle    time.Duration
+}
+
+type RateLimitMemoryStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewRateLimitMemoryStore() domain.RateLimiter {
+	return &RateLimitMemoryStore{buckets: map[string]*tokenBucket{}, lastSweep: time.Now(), now: time.Now}
+}
+
+func (store *RateLimitMemoryStore) Allow(key string, limit domain.RateLimit) (bool, time.Duration, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	now := store.now()
+	store.sweep(now)
+
+	interval := limit.Interval()
+	bucket, ok := store.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), updated: now}
+		store.buckets[key] = bucket
+	}
+	bucket.idle = interval * time.Duration(limit.Burst)
+	bucket.tokens = refill(bucket.tokens, now.Sub(bucket.updated), interval, limit.Burst)
+	bucket.updated = now
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) * float64(interval)), nil
+	}
+	bucket.tokens--
+	return true, 0, nil
+}
+
+func (store *RateLimitMemoryStore) sweep(now time.Time) {
+	if now.Sub(store.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	store.lastSweep = now
+	for key, bucket := range store.buckets {
+		if now.Sub(bucket.updated) >= bucket.idle {
+			delete(store.buckets, key)
+		}
+	}
+}
+
+func refill(tokens float64, elapsed time.Duration, interval time.Duration, burst int) float64 {
+	if elapsed > 0 {
+		tokens += float64(elapsed) / float64(interval)
+	}
+	if tokens > float64(burst) {
+		return float64(burst)
+	}
+	return tokens
+}
@@ -0,0 +1,56 @@
+package persistence
+
+import (
+	"context"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+const rateLimitKeyPrefix = "hotel-service:rate-limit:"
+
+// Refills the bucket stored at KEYS[1] and takes a token if one is available.
+// Returns {allowed, milliseconds until the next token}.
+var tokenBucketScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(bucket[1]) or burst
+local updated = tonumber(bucket[2]) or now
+if now > updated then
+	tokens = math.min(burst, tokens + (now - updated) / interval)
+end
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * interval)
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated", tostring(math.max(now, updated)))
+redis.call("PEXPIRE", KEYS[1], math.ceil(interval * burst))
+return {allowed, wait}
+`)
+
+type RateLimitRedisStore struct {
+	client  redis.Scripter
+	timeout time.Duration
+}
+
+func NewRateLimitRedisStore(client redis.Scripter, timeout time.Duration) domain.RateLimiter {
+	return &RateLimitRedisStore{client: client, timeout: timeout}
+}
+
+func (store *RateLimitRedisStore) Allow(key string, limit domain.RateLimit) (bool, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+	interval := float64(limit.Interval()) / float64(time.Millisecond)
+	now := time.Now().UnixMilli()
+	result, err := tokenBucketScript.Run(ctx, store.client, []string{rateLimitKeyPrefix + key}, interval, limit.Burst, now).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
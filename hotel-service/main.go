@@ -14,10 +14,10 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 	"log"
-	"time"
+	"os"
 )
 
-func initJaegerTracer(jaegerHost string) (*sdktrace.TracerProvider, error) {
+func initJaegerTracer(jaegerHost string, sampleRatio float64) (*sdktrace.TracerProvider, error) {
 	log.Printf("Initializing tracing to jaeger at %s\n", jaegerHost)
 	exporter, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(jaegerHost)))
 	if err != nil {
@@ -25,6 +25,7 @@ func initJaegerTracer(jaegerHost string) (*sdktrace.TracerProvider, error) {
 	}
 	return sdktrace.NewTracerProvider(
 		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
 		sdktrace.WithResource(resource.NewWithAttributes(
 			semconv.SchemaURL,
 			semconv.ServiceNameKey.String(domain.ServiceName),
@@ -32,13 +33,13 @@ func initJaegerTracer(jaegerHost string) (*sdktrace.TracerProvider, error) {
 	), nil
 }
 
-func initPromtailClient(lokiHost string) (promtail.Client, error) {
+func initPromtailClient(config *cfg.Config) (promtail.Client, error) {
 	labels := "{source=\"" + domain.ServiceName + "\",service_name=\"" + "\"}"
 	conf := promtail.ClientConfig{
-		PushURL:            lokiHost,
+		PushURL:            config.LokiHost,
 		Labels:             labels,
-		BatchWait:          5 * time.Second,
-		BatchEntriesNumber: 10000,
+		BatchWait:          config.LokiBatchWait,
+		BatchEntriesNumber: config.LokiBatchEntries,
 		SendLevel:          promtail.INFO,
 		PrintLevel:         promtail.ERROR,
 	}
@@ -46,42 +47,17 @@ func initPromtailClient(lokiHost string) (promtail.Client, error) {
 	return promtail.NewClientJson(conf)
 }
 
-func main() {
-	config := cfg.NewConfig()
-
-	var err error
-	tp, err := initJaegerTracer(config.JaegerHost)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer func() {
-		if err := tp.Shutdown(context.Background()); err != nil {
-			log.Printf("Error shutting down tracer provider: %v", err)
-		}
-	}()
-	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
-
-	loki, err := initPromtailClient(config.LokiHost)
-
-	if err != nil {
-		log.Printf("promtail.NewClient: %s\n", err)
-	}
-
-	server := startup.NewServer(config, tp, loki)
-	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
-		"bootstrap.servers": config.BootstrapServers,
-		"security.protocol": "sasl_plaintext",
-		"sasl.mechanism":    "PLAIN",
-		"sasl.username":     "user1",
-		"sasl.password":     config.KafkaAuthPassword,
-		"group.id":          "hotel-service",
-		"auto.offset.reset": "earliest",
-	})
+func startConsumer(config *cfg.Config, server *startup.Server) *kafka.Consumer {
+	consumerConfig := startup.KafkaConfigMap(config)
+	_ = consumerConfig.SetKey("group.id", config.KafkaGroupId)
+	_ = consumerConfig.SetKey("auto.offset.reset", config.KafkaAutoOffsetReset)
+	consumer, err := kafka.NewConsumer(consumerConfig)
 	if err != nil {
 		log.Fatalf("Failed to create consumer: %s", err)
 	}
-	defer consumer.Close()
+	server.OnSecretRotation("kafka consumer", func(updated *cfg.Config) error {
+		return consumer.SetSaslCredentials(updated.KafkaAuthUsername, updated.KafkaAuthPassword)
+	}, "KAFKA_AUTH_USERNAME", "KAFKA_AUTH_PASSWORD")
 
 	consumer.SubscribeTopics([]string{"accommodation.delete"}, nil)
 	topicHandlers := map[string]func(*kafka.Message){
@@ -112,6 +88,40 @@ func main() {
 			handlerFunc(msg)
 		}
 	}()
+	return consumer
+}
+
+func main() {
+	config, err := cfg.Load(os.LookupEnv)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tp, err := initJaegerTracer(config.JaegerHost, config.TraceSampleRatio)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			log.Printf("Error shutting down tracer provider: %v", err)
+		}
+	}()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	loki, err := initPromtailClient(config)
+
+	if err != nil {
+		log.Printf("promtail.NewClient: %s\n", err)
+	}
+
+	server := startup.NewServer(config, tp, loki)
+	if config.KafkaEnabled {
+		consumer := startConsumer(config, server)
+		defer consumer.Close()
+	} else {
+		log.Printf("Kafka is disabled, accommodation.delete events will not be consumed")
+	}
 	server.Start()
 	loki.Shutdown()
 
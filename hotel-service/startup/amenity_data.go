@@ -0,0 +1,20 @@
+package startup
+
+import "github.com/ZMS-DevOps/hotel-service/domain"
+
+var amenities = []*domain.Amenity{
+	{Code: "wifi", Category: "internet", Icon: "wifi", Labels: map[string]string{"en": "Free WiFi", "sr": "Besplatan WiFi"}, Aliases: []string{"WiFi", "Wi-Fi", "Internet"}},
+	{Code: "tv", Category: "entertainment", Icon: "tv", Labels: map[string]string{"en": "TV", "sr": "Televizor"}, Aliases: []string{"Television"}},
+	{Code: "phone", Category: "entertainment", Icon: "phone", Labels: map[string]string{"en": "Phone", "sr": "Telefon"}, Aliases: []string{"Telephone"}},
+	{Code: "kitchen", Category: "kitchen", Icon: "kitchen", Labels: map[string]string{"en": "Kitchen", "sr": "Kuhinja"}},
+	{Code: "bbq_grill", Category: "outdoor", Icon: "grill", Labels: map[string]string{"en": "BBQ grill", "sr": "Roštilj"}, Aliases: []string{"BBQ", "BBQ facilities", "Barbecue"}},
+	{Code: "balcony", Category: "outdoor", Icon: "balcony", Labels: map[string]string{"en": "Balcony", "sr": "Balkon"}, Aliases: []string{"Terrace"}},
+	{Code: "pool", Category: "outdoor", Icon: "pool", Labels: map[string]string{"en": "Swimming pool", "sr": "Bazen"}, Aliases: []string{"Pool"}},
+	{Code: "air_conditioning", Category: "climate", Icon: "snowflake", Labels: map[string]string{"en": "Air conditioning", "sr": "Klima uređaj"}, Aliases: []string{"Air condition", "AC"}},
+	{Code: "bathtub", Category: "bathroom", Icon: "bath", Labels: map[string]string{"en": "Bathtub", "sr": "Kada"}},
+	{Code: "king_bed", Category: "bedroom", Icon: "bed", Labels: map[string]string{"en": "King size bed", "sr": "Bračni krevet king size"}, Aliases: []string{"Kings bed", "King bed"}},
+	{Code: "laundry", Category: "services", Icon: "washing-machine", Labels: map[string]string{"en": "Laundry", "sr": "Vešeraj"}, Aliases: []string{"Washing machine"}},
+	{Code: "parking", Category: "parking", Icon: "car", Labels: map[string]string{"en": "Parking", "sr": "Parking"}, Aliases: []string{"Free parking"}},
+	{Code: "gym", Category: "wellness", Icon: "dumbbell", Labels: map[string]string{"en": "Fitness center", "sr": "Teretana"}, Aliases: []string{"Gym", "Fitness"}},
+	{Code: "non_smoking", Category: "policies", Icon: "no-smoking", Labels: map[string]string{"en": "Non-smoking", "sr": "Zabranjeno pušenje"}, Aliases: []string{"No smoke", "No smoking"}},
+}
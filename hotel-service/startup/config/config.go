@@ -1,38 +1,163 @@
 package config
 
-import "os"
+import (
+	"time"
+)
+
+const (
+	MongoStore  = "mongo"
+	MemoryStore = "memory"
+
+	RemoteDependencies = "remote"
+	LocalDependencies  = "local"
+
+	MemoryRateLimiter = "memory"
+	RedisRateLimiter  = "redis"
+)
 
 type Config struct {
-	Port              string
-	GrpcPort          string
-	HotelDBHost       string
-	HotelDBPort       string
-	HotelDBUsername   string
-	HotelDBPassword   string
-	BookingHost       string
-	BookingPort       string
-	SearchHost        string
-	SearchPort        string
-	BootstrapServers  string
-	KafkaAuthPassword string
-	JaegerHost        string
-	LokiHost          string
+	Port                        string        `yaml:"service_port" env:"SERVICE_PORT"`
+	AdminPort                   string        `yaml:"admin_port" env:"ADMIN_PORT"`
+	HotelDBHost                 string        `yaml:"db_host" env:"DB_HOST"`
+	HotelDBPort                 string        `yaml:"db_port" env:"DB_PORT"`
+	HotelDBUsername             string        `yaml:"db_username" env:"MONGO_INITDB_ROOT_USERNAME"`
+	HotelDBPassword             string        `yaml:"db_password" env:"MONGO_INITDB_ROOT_PASSWORD" secret:"true"`
+	MongoURI                    string        `yaml:"mongo_uri" env:"MONGO_URI" secret:"true"`
+	MongoAuthSource             string        `yaml:"mongo_auth_source" env:"MONGO_AUTH_SOURCE"`
+	MongoReplicaSet             string        `yaml:"mongo_replica_set" env:"MONGO_REPLICA_SET"`
+	MongoTLS                    bool          `yaml:"mongo_tls" env:"MONGO_TLS"`
+	MongoTLSCAFile              string        `yaml:"mongo_tls_ca_file" env:"MONGO_TLS_CA_FILE"`
+	MongoMaxPoolSize            int           `yaml:"mongo_max_pool_size" env:"MONGO_MAX_POOL_SIZE"`
+	MongoMinPoolSize            int           `yaml:"mongo_min_pool_size" env:"MONGO_MIN_POOL_SIZE"`
+	MongoMaxConnIdleTime        time.Duration `yaml:"mongo_max_conn_idle_time" env:"MONGO_MAX_CONN_IDLE_TIME"`
+	MongoConnectTimeout         time.Duration `yaml:"mongo_connect_timeout" env:"MONGO_CONNECT_TIMEOUT"`
+	MongoServerSelectionTimeout time.Duration `yaml:"mongo_server_selection_timeout" env:"MONGO_SERVER_SELECTION_TIMEOUT"`
+	MongoSocketTimeout          time.Duration `yaml:"mongo_socket_timeout" env:"MONGO_SOCKET_TIMEOUT"`
+	MongoWriteConcern           string        `yaml:"mongo_write_concern" env:"MONGO_WRITE_CONCERN"`
+	MongoReadConcern            string        `yaml:"mongo_read_concern" env:"MONGO_READ_CONCERN"`
+	MongoReadPreference         string        `yaml:"mongo_read_preference" env:"MONGO_READ_PREFERENCE"`
+	MongoConnectRetries         int           `yaml:"mongo_connect_retries" env:"MONGO_CONNECT_RETRIES"`
+	MongoConnectRetryBackoff    time.Duration `yaml:"mongo_connect_retry_backoff" env:"MONGO_CONNECT_RETRY_BACKOFF"`
+	MongoConnectMaxRetryBackoff time.Duration `yaml:"mongo_connect_max_retry_backoff" env:"MONGO_CONNECT_MAX_RETRY_BACKOFF"`
+	BookingHost                 string        `yaml:"booking_host" env:"BOOKING_HOST"`
+	BookingPort                 string        `yaml:"booking_port" env:"BOOKING_PORT"`
+	SearchHost                  string        `yaml:"search_host" env:"SEARCH_HOST"`
+	SearchPort                  string        `yaml:"search_port" env:"SEARCH_PORT"`
+	KafkaEnabled                bool          `yaml:"kafka_enabled" env:"KAFKA_ENABLED"`
+	BootstrapServers            string        `yaml:"kafka_bootstrap_servers" env:"KAFKA_BOOTSTRAP_SERVERS"`
+	KafkaSecurityProtocol       string        `yaml:"kafka_security_protocol" env:"KAFKA_SECURITY_PROTOCOL"`
+	KafkaSaslMechanism          string        `yaml:"kafka_sasl_mechanism" env:"KAFKA_SASL_MECHANISM"`
+	KafkaAuthUsername           string        `yaml:"kafka_auth_username" env:"KAFKA_AUTH_USERNAME"`
+	KafkaAuthPassword           string        `yaml:"kafka_auth_password" env:"KAFKA_AUTH_PASSWORD" secret:"true"`
+	KafkaGroupId                string        `yaml:"kafka_group_id" env:"KAFKA_GROUP_ID"`
+	KafkaAutoOffsetReset        string        `yaml:"kafka_auto_offset_reset" env:"KAFKA_AUTO_OFFSET_RESET"`
+	JaegerHost                  string        `yaml:"jaeger_endpoint" env:"JAEGER_ENDPOINT"`
+	TraceSampleRatio            float64       `yaml:"trace_sample_ratio" env:"TRACE_SAMPLE_RATIO"`
+	LokiHost                    string        `yaml:"loki_endpoint" env:"LOKI_ENDPOINT"`
+	LokiBatchWait               time.Duration `yaml:"loki_batch_wait" env:"LOKI_BATCH_WAIT"`
+	LokiBatchEntries            int           `yaml:"loki_batch_entries" env:"LOKI_BATCH_ENTRIES"`
+	DeletedRetention            time.Duration `yaml:"deleted_retention" env:"DELETED_RETENTION"`
+	PurgeInterval               time.Duration `yaml:"purge_interval" env:"PURGE_INTERVAL"`
+	HostDeletionResumeInterval  time.Duration `yaml:"host_deletion_resume_interval" env:"HOST_DELETION_RESUME_INTERVAL"`
+	HostDeletionSagaLease       time.Duration `yaml:"host_deletion_saga_lease" env:"HOST_DELETION_SAGA_LEASE"`
+	Geocoder                    string        `yaml:"geocoder" env:"GEOCODER"`
+	NominatimUrl                string        `yaml:"nominatim_url" env:"NOMINATIM_URL"`
+	GeocoderUserAgent           string        `yaml:"geocoder_user_agent" env:"GEOCODER_USER_AGENT"`
+	GeocoderTimeout             time.Duration `yaml:"geocoder_timeout" env:"GEOCODER_TIMEOUT"`
+	GeocoderMinInterval         time.Duration `yaml:"geocoder_min_interval" env:"GEOCODER_MIN_INTERVAL"`
+	GeocodeCacheTTL             time.Duration `yaml:"geocode_cache_ttl" env:"GEOCODE_CACHE_TTL"`
+	AccommodationStore          string        `yaml:"accommodation_store" env:"ACCOMMODATION_STORE"`
+	Dependencies                string        `yaml:"dependencies" env:"DEPENDENCIES"`
+	StubFailures                string        `yaml:"local_stub_failures" env:"LOCAL_STUB_FAILURES"`
+	GrpcCallTimeout             time.Duration `yaml:"grpc_call_timeout" env:"GRPC_CALL_TIMEOUT"`
+	GrpcMethodTimeouts          string        `yaml:"grpc_method_timeouts" env:"GRPC_METHOD_TIMEOUTS"`
+	GrpcMaxRetries              int           `yaml:"grpc_max_retries" env:"GRPC_MAX_RETRIES"`
+	GrpcRetryBackoff            time.Duration `yaml:"grpc_retry_backoff" env:"GRPC_RETRY_BACKOFF"`
+	GrpcMaxRetryBackoff         time.Duration `yaml:"grpc_max_retry_backoff" env:"GRPC_MAX_RETRY_BACKOFF"`
+	GrpcRetryDeadline           time.Duration `yaml:"grpc_retry_deadline" env:"GRPC_RETRY_DEADLINE"`
+	GrpcBreakerFailures         int           `yaml:"grpc_breaker_failures" env:"GRPC_BREAKER_FAILURES"`
+	GrpcBreakerOpenTimeout      time.Duration `yaml:"grpc_breaker_open_timeout" env:"GRPC_BREAKER_OPEN_TIMEOUT"`
+	GrpcTransport               string        `yaml:"grpc_transport" env:"GRPC_TRANSPORT"`
+	GrpcTLSCAFile               string        `yaml:"grpc_tls_ca_file" env:"GRPC_TLS_CA_FILE"`
+	GrpcTLSCertFile             string        `yaml:"grpc_tls_cert_file" env:"GRPC_TLS_CERT_FILE"`
+	GrpcTLSKeyFile              string        `yaml:"grpc_tls_key_file" env:"GRPC_TLS_KEY_FILE"`
+	GrpcTLSServerName           string        `yaml:"grpc_tls_server_name" env:"GRPC_TLS_SERVER_NAME"`
+	SecretPollInterval          time.Duration `yaml:"secret_poll_interval" env:"SECRET_POLL_INTERVAL"`
+	MongoDrainTimeout           time.Duration `yaml:"mongo_drain_timeout" env:"MONGO_DRAIN_TIMEOUT"`
+	IdempotencyTTL              time.Duration `yaml:"idempotency_ttl" env:"IDEMPOTENCY_TTL"`
+	IdempotencyLease            time.Duration `yaml:"idempotency_lease" env:"IDEMPOTENCY_LEASE"`
+	RateLimiter                 string        `yaml:"rate_limiter" env:"RATE_LIMITER"`
+	RateLimitTrustedProxyHops   int           `yaml:"rate_limit_trusted_proxy_hops" env:"RATE_LIMIT_TRUSTED_PROXY_HOPS"`
+	RateLimitReadsPerMinute     int           `yaml:"rate_limit_reads_per_minute" env:"RATE_LIMIT_READS_PER_MINUTE"`
+	RateLimitReadsBurst         int           `yaml:"rate_limit_reads_burst" env:"RATE_LIMIT_READS_BURST"`
+	RateLimitWritesPerMinute    int           `yaml:"rate_limit_writes_per_minute" env:"RATE_LIMIT_WRITES_PER_MINUTE"`
+	RateLimitWritesBurst        int           `yaml:"rate_limit_writes_burst" env:"RATE_LIMIT_WRITES_BURST"`
+	RateLimitUploadsPerMinute   int           `yaml:"rate_limit_uploads_per_minute" env:"RATE_LIMIT_UPLOADS_PER_MINUTE"`
+	RateLimitUploadsBurst       int           `yaml:"rate_limit_uploads_burst" env:"RATE_LIMIT_UPLOADS_BURST"`
+	MaxRequestBodyBytes         int           `yaml:"max_request_body_bytes" env:"MAX_REQUEST_BODY_BYTES"`
+	MaxUploadBodyBytes          int           `yaml:"max_upload_body_bytes" env:"MAX_UPLOAD_BODY_BYTES"`
+	RedisAddress                string        `yaml:"redis_address" env:"REDIS_ADDRESS"`
+	RedisUsername               string        `yaml:"redis_username" env:"REDIS_USERNAME"`
+	RedisPassword               string        `yaml:"redis_password" env:"REDIS_PASSWORD" secret:"true"`
+	RedisDB                     int           `yaml:"redis_db" env:"REDIS_DB"`
+	RedisTimeout                time.Duration `yaml:"redis_timeout" env:"REDIS_TIMEOUT"`
+	files                       map[string]string
 }
 
-func NewConfig() *Config {
+func Default() *Config {
 	return &Config{
-		Port:              os.Getenv("SERVICE_PORT"),
-		HotelDBHost:       os.Getenv("DB_HOST"),
-		HotelDBPort:       os.Getenv("DB_PORT"),
-		HotelDBUsername:   os.Getenv("MONGO_INITDB_ROOT_USERNAME"),
-		HotelDBPassword:   os.Getenv("MONGO_INITDB_ROOT_PASSWORD"),
-		BookingHost:       os.Getenv("BOOKING_HOST"),
-		BookingPort:       os.Getenv("BOOKING_PORT"),
-		SearchHost:        os.Getenv("SEARCH_HOST"),
-		SearchPort:        os.Getenv("SEARCH_PORT"),
-		BootstrapServers:  os.Getenv("KAFKA_BOOTSTRAP_SERVERS"),
-		KafkaAuthPassword: os.Getenv("KAFKA_AUTH_PASSWORD"),
-		JaegerHost:        os.Getenv("JAEGER_ENDPOINT"),
-		LokiHost:          os.Getenv("LOKI_ENDPOINT"),
+		Port:                        "8084",
+		HotelDBPort:                 "27017",
+		MongoMaxPoolSize:            100,
+		MongoConnectTimeout:         10 * time.Second,
+		MongoServerSelectionTimeout: 15 * time.Second,
+		MongoWriteConcern:           "majority",
+		MongoReadPreference:         "primary",
+		MongoConnectRetries:         5,
+		MongoConnectRetryBackoff:    time.Second,
+		MongoConnectMaxRetryBackoff: 30 * time.Second,
+		KafkaEnabled:                true,
+		KafkaSecurityProtocol:       "sasl_plaintext",
+		KafkaSaslMechanism:          "PLAIN",
+		KafkaAuthUsername:           "user1",
+		KafkaGroupId:                "hotel-service",
+		KafkaAutoOffsetReset:        "earliest",
+		TraceSampleRatio:            1,
+		LokiBatchWait:               5 * time.Second,
+		LokiBatchEntries:            10000,
+		DeletedRetention:            30 * 24 * time.Hour,
+		PurgeInterval:               time.Hour,
+		HostDeletionResumeInterval:  time.Minute,
+		HostDeletionSagaLease:       5 * time.Minute,
+		Geocoder:                    "offline",
+		NominatimUrl:                "https://nominatim.openstreetmap.org",
+		GeocoderUserAgent:           "hotel-service",
+		GeocoderTimeout:             5 * time.Second,
+		GeocoderMinInterval:         time.Second,
+		GeocodeCacheTTL:             30 * 24 * time.Hour,
+		AccommodationStore:          MongoStore,
+		Dependencies:                RemoteDependencies,
+		GrpcCallTimeout:             3 * time.Second,
+		GrpcMaxRetries:              2,
+		GrpcRetryBackoff:            100 * time.Millisecond,
+		GrpcMaxRetryBackoff:         2 * time.Second,
+		GrpcRetryDeadline:           10 * time.Second,
+		GrpcBreakerFailures:         5,
+		GrpcBreakerOpenTimeout:      30 * time.Second,
+		GrpcTransport:               "insecure",
+		SecretPollInterval:          30 * time.Second,
+		MongoDrainTimeout:           30 * time.Second,
+		IdempotencyTTL:              24 * time.Hour,
+		IdempotencyLease:            time.Minute,
+		RateLimiter:                 MemoryRateLimiter,
+		RateLimitReadsPerMinute:     600,
+		RateLimitReadsBurst:         100,
+		RateLimitWritesPerMinute:    120,
+		RateLimitWritesBurst:        30,
+		RateLimitUploadsPerMinute:   20,
+		RateLimitUploadsBurst:       5,
+		MaxRequestBodyBytes:         1 << 20,
+		MaxUploadBodyBytes:          32 << 20,
+		RedisTimeout:                200 * time.Millisecond,
 	}
 }
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ConfigFileEnv = "CONFIG_FILE"
+
+const fileEnvSuffix = "_FILE"
+
+const redacted = "[REDACTED]"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+type LookupEnv func(key string) (string, bool)
+
+func Load(lookupEnv LookupEnv) (*Config, error) {
+	config := Default()
+	if path, ok := lookupEnv(ConfigFileEnv); ok && path != "" {
+		if err := config.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	problems := config.applyEnv(lookupEnv)
+	problems = append(problems, config.validate()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return config, nil
+}
+
+func (config *Config) loadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+	return nil
+}
+
+func (config *Config) applyEnv(lookupEnv LookupEnv) []string {
+	var problems []string
+	value := reflect.ValueOf(config).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		key := value.Type().Field(i).Tag.Get("env")
+		if key == "" {
+			continue
+		}
+		raw, _ := lookupEnv(key)
+		path, _ := lookupEnv(key + fileEnvSuffix)
+		if raw != "" && path != "" {
+			problems = append(problems, fmt.Sprintf("%s: set either %s or %s%s, not both", key, key, key, fileEnvSuffix))
+			continue
+		}
+		if path != "" {
+			content, err := readValueFile(path)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s%s: %v", key, fileEnvSuffix, err))
+				continue
+			}
+			if config.files == nil {
+				config.files = map[string]string{}
+			}
+			config.files[key] = path
+			raw = content
+		}
+		if raw == "" {
+			continue
+		}
+		if err := setField(value.Field(i), raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+		}
+	}
+	return problems
+}
+
+func readValueFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func (config *Config) Files() map[string]string {
+	files := make(map[string]string, len(config.files))
+	for key, path := range config.files {
+		files[key] = path
+	}
+	return files
+}
+
+func (config *Config) ReloadFiles() (*Config, []string, error) {
+	reloaded := *config
+	var changed []string
+	var problems []string
+	value := reflect.ValueOf(&reloaded).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		key := value.Type().Field(i).Tag.Get("env")
+		path, ok := config.files[key]
+		if key == "" || !ok {
+			continue
+		}
+		content, err := readValueFile(path)
+		if err == nil {
+			err = setField(value.Field(i), content)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s%s: %v", key, fileEnvSuffix, err))
+			continue
+		}
+		if !reflect.DeepEqual(value.Field(i).Interface(), reflect.ValueOf(config).Elem().Field(i).Interface()) {
+			changed = append(changed, key)
+		}
+	}
+	if len(problems) > 0 {
+		return config, nil, &ValidationError{Problems: problems}
+	}
+	return &reloaded, changed, nil
+}
+
+func setField(field reflect.Value, raw string) error {
+	if field.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		field.SetBool(parsed)
+	case reflect.Int:
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		field.SetInt(int64(parsed))
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported config type %s", field.Type())
+	}
+	return nil
+}
+
+func (config *Config) Redacted() map[string]interface{} {
+	view := map[string]interface{}{}
+	value := reflect.ValueOf(config).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := field.Tag.Get("yaml")
+		if name == "" {
+			continue
+		}
+		switch {
+		case field.Tag.Get("secret") == "true":
+			if value.Field(i).String() != "" {
+				view[name] = redacted
+			} else {
+				view[name] = ""
+			}
+		case field.Type == durationType:
+			view[name] = time.Duration(value.Field(i).Int()).String()
+		default:
+			view[name] = value.Field(i).Interface()
+		}
+	}
+	return view
+}
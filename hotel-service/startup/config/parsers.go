@@ -0,0 +1,26 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+func ParseMethodTimeouts(value string) (map[string]time.Duration, error) {
+	timeouts := map[string]time.Duration{}
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		method, rawTimeout, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid method timeout %q, expected Service/Method=duration", entry)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(rawTimeout))
+		if err != nil {
+			return nil, fmt.Errorf("invalid method timeout %q: %v", entry, err)
+		}
+		timeouts["/"+strings.TrimPrefix(strings.TrimSpace(method), "/")] = timeout
+	}
+	return timeouts, nil
+}
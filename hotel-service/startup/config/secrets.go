@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+type RotationListener func(updated *Config) error
+
+type rotationSubscription struct {
+	name     string
+	keys     []string
+	listener RotationListener
+	applied  *Config
+}
+
+type SecretWatcher struct {
+	mutex         sync.Mutex
+	current       *Config
+	subscriptions []*rotationSubscription
+}
+
+func NewSecretWatcher(config *Config) *SecretWatcher {
+	return &SecretWatcher{current: config}
+}
+
+func (watcher *SecretWatcher) OnRotation(name string, listener RotationListener, keys ...string) {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+	watcher.subscriptions = append(watcher.subscriptions, &rotationSubscription{name: name, keys: keys, listener: listener, applied: watcher.current})
+}
+
+func (watcher *SecretWatcher) Current() *Config {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+	return watcher.current
+}
+
+func (watcher *SecretWatcher) Poll() error {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+	reloaded, changed, err := watcher.current.ReloadFiles()
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		log.Printf("Secret files changed: %s", strings.Join(changed, ", "))
+		if unhandled := watcher.unhandled(changed); len(unhandled) > 0 {
+			log.Printf("Changes to %s require a restart to take effect", strings.Join(unhandled, ", "))
+		}
+	}
+	watcher.current = reloaded
+
+	var problems []string
+	for _, subscription := range watcher.subscriptions {
+		if !subscription.applied.differs(reloaded, subscription.keys) {
+			continue
+		}
+		if err := subscription.listener(reloaded); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", subscription.name, err))
+			continue
+		}
+		log.Printf("Applied rotated secrets to %s", subscription.name)
+		subscription.applied = reloaded
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("failed to apply rotated secrets: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (watcher *SecretWatcher) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := watcher.Poll(); err != nil {
+				log.Printf("Secret rotation: %v", err)
+			}
+		}
+	}()
+}
+
+func (watcher *SecretWatcher) unhandled(changed []string) []string {
+	var unhandled []string
+	for _, key := range changed {
+		handled := false
+		for _, subscription := range watcher.subscriptions {
+			for _, subscribed := range subscription.keys {
+				handled = handled || subscribed == key
+			}
+		}
+		if !handled {
+			unhandled = append(unhandled, key)
+		}
+	}
+	return unhandled
+}
+
+func (config *Config) differs(other *Config, keys []string) bool {
+	value, otherValue := reflect.ValueOf(config).Elem(), reflect.ValueOf(other).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		key := value.Type().Field(i).Tag.Get("env")
+		for _, wanted := range keys {
+			if key == wanted && !reflect.DeepEqual(value.Field(i).Interface(), otherValue.Field(i).Interface()) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var kafkaOffsetResets = []string{"earliest", "latest", "smallest", "largest", "beginning", "end", "error"}
+
+type ValidationError struct {
+	Problems []string
+}
+
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration (%d problems):\n  - %s", len(err.Problems), strings.Join(err.Problems, "\n  - "))
+}
+
+func (config *Config) Validate() error {
+	if problems := config.validate(); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (config *Config) validate() []string {
+	var problems []string
+	problemf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	checkPort := func(key, value string, required bool) {
+		if value == "" {
+			if required {
+				problemf("%s is required", key)
+			}
+			return
+		}
+		if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+			problemf("%s must be a port between 1 and 65535, got %q", key, value)
+		}
+	}
+	checkRequired := func(key, value string) {
+		if strings.TrimSpace(value) == "" {
+			problemf("%s is required", key)
+		}
+	}
+	checkOneOf := func(key, value string, allowed ...string) {
+		for _, option := range allowed {
+			if value == option {
+				return
+			}
+		}
+		problemf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), value)
+	}
+	checkURL := func(key, value string) {
+		if value == "" {
+			return
+		}
+		if parsed, err := url.Parse(value); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			problemf("%s must be an http(s) URL, got %q", key, value)
+		}
+	}
+	checkPositive := func(key string, value time.Duration) {
+		if value <= 0 {
+			problemf("%s must be positive, got %s", key, value)
+		}
+	}
+	checkNotNegative := func(key string, value int64) {
+		if value < 0 {
+			problemf("%s must not be negative, got %d", key, value)
+		}
+	}
+
+	checkPort("SERVICE_PORT", config.Port, true)
+	checkPort("ADMIN_PORT", config.AdminPort, false)
+	if config.AdminPort != "" && config.AdminPort == config.Port {
+		problemf("ADMIN_PORT must differ from SERVICE_PORT, debug endpoints must not be served on the public port")
+	}
+
+	checkOneOf("ACCOMMODATION_STORE", config.AccommodationStore, MongoStore, MemoryStore)
+	requiresMongoHost := config.AccommodationStore == MongoStore && config.MongoURI == ""
+	checkPort("DB_PORT", config.HotelDBPort, requiresMongoHost)
+	if requiresMongoHost {
+		checkRequired("DB_HOST", config.HotelDBHost)
+	}
+	if config.MongoURI != "" && !strings.HasPrefix(config.MongoURI, "mongodb://") && !strings.HasPrefix(config.MongoURI, "mongodb+srv://") {
+		problemf("MONGO_URI must start with mongodb:// or mongodb+srv://")
+	}
+	if config.MongoTLSCAFile != "" && !config.MongoTLS {
+		problemf("MONGO_TLS_CA_FILE requires MONGO_TLS=true")
+	}
+	checkNotNegative("MONGO_MAX_POOL_SIZE", int64(config.MongoMaxPoolSize))
+	checkNotNegative("MONGO_MIN_POOL_SIZE", int64(config.MongoMinPoolSize))
+	if config.MongoMaxPoolSize > 0 && config.MongoMinPoolSize > config.MongoMaxPoolSize {
+		problemf("MONGO_MIN_POOL_SIZE (%d) must not exceed MONGO_MAX_POOL_SIZE (%d)", config.MongoMinPoolSize, config.MongoMaxPoolSize)
+	}
+	checkNotNegative("MONGO_MAX_CONN_IDLE_TIME", int64(config.MongoMaxConnIdleTime))
+	checkPositive("MONGO_CONNECT_TIMEOUT", config.MongoConnectTimeout)
+	checkPositive("MONGO_SERVER_SELECTION_TIMEOUT", config.MongoServerSelectionTimeout)
+	checkNotNegative("MONGO_SOCKET_TIMEOUT", int64(config.MongoSocketTimeout))
+	if config.MongoWriteConcern != "" {
+		if w, err := strconv.Atoi(config.MongoWriteConcern); config.MongoWriteConcern != "majority" && (err != nil || w < 0) {
+			problemf("MONGO_WRITE_CONCERN must be majority or a number of nodes, got %q", config.MongoWriteConcern)
+		}
+	}
+	if config.MongoReadConcern != "" {
+		checkOneOf("MONGO_READ_CONCERN", config.MongoReadConcern, "local", "available", "majority", "linearizable", "snapshot")
+	}
+	if config.MongoReadPreference != "" {
+		checkOneOf("MONGO_READ_PREFERENCE", strings.ToLower(config.MongoReadPreference), "primary", "primarypreferred", "secondary", "secondarypreferred", "nearest")
+	}
+	if config.MongoConnectRetries < 1 {
+		problemf("MONGO_CONNECT_RETRIES must be at least 1, got %d", config.MongoConnectRetries)
+	}
+	checkPositive("MONGO_CONNECT_RETRY_BACKOFF", config.MongoConnectRetryBackoff)
+	checkPositive("MONGO_CONNECT_MAX_RETRY_BACKOFF", config.MongoConnectMaxRetryBackoff)
+	if config.MongoConnectMaxRetryBackoff > 0 && config.MongoConnectMaxRetryBackoff < config.MongoConnectRetryBackoff {
+		problemf("MONGO_CONNECT_MAX_RETRY_BACKOFF (%s) must not be shorter than MONGO_CONNECT_RETRY_BACKOFF (%s)", config.MongoConnectMaxRetryBackoff, config.MongoConnectRetryBackoff)
+	}
+
+	checkOneOf("DEPENDENCIES", config.Dependencies, RemoteDependencies, LocalDependencies)
+	remote := config.Dependencies == RemoteDependencies
+	if remote {
+		checkRequired("BOOKING_HOST", config.BookingHost)
+		checkRequired("SEARCH_HOST", config.SearchHost)
+	}
+	checkPort("BOOKING_PORT", config.BookingPort, remote)
+	checkPort("SEARCH_PORT", config.SearchPort, remote)
+
+	if config.KafkaEnabled {
+		checkRequired("KAFKA_BOOTSTRAP_SERVERS", config.BootstrapServers)
+		checkRequired("KAFKA_AUTH_USERNAME", config.KafkaAuthUsername)
+		checkRequired("KAFKA_GROUP_ID", config.KafkaGroupId)
+		checkOneOf("KAFKA_AUTO_OFFSET_RESET", config.KafkaAutoOffsetReset, kafkaOffsetResets...)
+	}
+
+	checkURL("JAEGER_ENDPOINT", config.JaegerHost)
+	checkURL("LOKI_ENDPOINT", config.LokiHost)
+	if config.TraceSampleRatio < 0 || config.TraceSampleRatio > 1 {
+		problemf("TRACE_SAMPLE_RATIO must be between 0 and 1, got %v", config.TraceSampleRatio)
+	}
+	checkPositive("LOKI_BATCH_WAIT", config.LokiBatchWait)
+	if config.LokiBatchEntries <= 0 {
+		problemf("LOKI_BATCH_ENTRIES must be positive, got %d", config.LokiBatchEntries)
+	}
+
+	checkPositive("DELETED_RETENTION", config.DeletedRetention)
+	checkPositive("PURGE_INTERVAL", config.PurgeInterval)
+	checkPositive("HOST_DELETION_RESUME_INTERVAL", config.HostDeletionResumeInterval)
+	checkPositive("HOST_DELETION_SAGA_LEASE", config.HostDeletionSagaLease)
+	checkOneOf("GEOCODER", config.Geocoder, "offline", "nominatim", "none")
+	if config.Geocoder == "nominatim" {
+		checkRequired("NOMINATIM_URL", config.NominatimUrl)
+		checkURL("NOMINATIM_URL", config.NominatimUrl)
+		checkPositive("GEOCODER_MIN_INTERVAL", config.GeocoderMinInterval)
+		if usesPublicNominatim(config.NominatimUrl) && !identifiesContact(config.GeocoderUserAgent) {
+			problemf("GEOCODER_USER_AGENT must include a contact email or URL when using the public Nominatim instance, got %q", config.GeocoderUserAgent)
+		}
+	}
+	checkPositive("GEOCODER_TIMEOUT", config.GeocoderTimeout)
+	checkPositive("GEOCODE_CACHE_TTL", config.GeocodeCacheTTL)
+
+	checkPositive("GRPC_CALL_TIMEOUT", config.GrpcCallTimeout)
+	if _, err := ParseMethodTimeouts(config.GrpcMethodTimeouts); err != nil {
+		problemf("GRPC_METHOD_TIMEOUTS: %v", err)
+	}
+	checkNotNegative("GRPC_MAX_RETRIES", int64(config.GrpcMaxRetries))
+	checkNotNegative("GRPC_RETRY_BACKOFF", int64(config.GrpcRetryBackoff))
+	if config.GrpcMaxRetries > 0 {
+		checkPositive("GRPC_MAX_RETRY_BACKOFF", config.GrpcMaxRetryBackoff)
+	} else {
+		checkNotNegative("GRPC_MAX_RETRY_BACKOFF", int64(config.GrpcMaxRetryBackoff))
+	}
+	checkPositive("GRPC_RETRY_DEADLINE", config.GrpcRetryDeadline)
+	if config.GrpcRetryDeadline > 0 && config.GrpcRetryDeadline < config.GrpcCallTimeout {
+		problemf("GRPC_RETRY_DEADLINE (%s) must not be shorter than GRPC_CALL_TIMEOUT (%s)", config.GrpcRetryDeadline, config.GrpcCallTimeout)
+	}
+	checkNotNegative("GRPC_BREAKER_FAILURES", int64(config.GrpcBreakerFailures))
+	checkPositive("GRPC_BREAKER_OPEN_TIMEOUT", config.GrpcBreakerOpenTimeout)
+	if config.GrpcTransport != "" {
+		checkOneOf("GRPC_TRANSPORT", config.GrpcTransport, "insecure", "tls", "mtls")
+	}
+	if config.GrpcTransport == "mtls" && (config.GrpcTLSCertFile == "" || config.GrpcTLSKeyFile == "") {
+		problemf("GRPC_TRANSPORT: transport %q requires a certificate and key file", config.GrpcTransport)
+	}
+	checkPositive("SECRET_POLL_INTERVAL", config.SecretPollInterval)
+	checkPositive("MONGO_DRAIN_TIMEOUT", config.MongoDrainTimeout)
+	checkPositive("IDEMPOTENCY_TTL", config.IdempotencyTTL)
+	checkPositive("IDEMPOTENCY_LEASE", config.IdempotencyLease)
+	if config.IdempotencyLease > config.IdempotencyTTL {
+		problemf("IDEMPOTENCY_LEASE (%s) must not exceed IDEMPOTENCY_TTL (%s)", config.IdempotencyLease, config.IdempotencyTTL)
+	}
+
+	checkOneOf("RATE_LIMITER", config.RateLimiter, MemoryRateLimiter, RedisRateLimiter)
+	if config.RateLimiter == RedisRateLimiter {
+		checkRequired("REDIS_ADDRESS", config.RedisAddress)
+		checkPositive("REDIS_TIMEOUT", config.RedisTimeout)
+	}
+	checkBudget := func(name string, perMinute, burst int) {
+		checkNotNegative("RATE_LIMIT_"+name+"_PER_MINUTE", int64(perMinute))
+		if perMinute > 0 && burst < 1 {
+			problemf("RATE_LIMIT_%s_BURST must be at least 1 when RATE_LIMIT_%s_PER_MINUTE is set, got %d", name, name, burst)
+		}
+	}
+	checkNotNegative("RATE_LIMIT_TRUSTED_PROXY_HOPS", int64(config.RateLimitTrustedProxyHops))
+	checkBudget("READS", config.RateLimitReadsPerMinute, config.RateLimitReadsBurst)
+	checkBudget("WRITES", config.RateLimitWritesPerMinute, config.RateLimitWritesBurst)
+	checkBudget("UPLOADS", config.RateLimitUploadsPerMinute, config.RateLimitUploadsBurst)
+	checkNotNegative("MAX_REQUEST_BODY_BYTES", int64(config.MaxRequestBodyBytes))
+	checkNotNegative("MAX_UPLOAD_BODY_BYTES", int64(config.MaxUploadBodyBytes))
+	return problems
+}
+
+// The public Nominatim usage policy requires an identifying User-Agent with a way to reach the operator.
+func usesPublicNominatim(nominatimUrl string) bool {
+	parsed, err := url.Parse(nominatimUrl)
+	return err == nil && strings.EqualFold(parsed.Hostname(), "nominatim.openstreetmap.org")
+}
+
+func identifiesContact(userAgent string) bool {
+	return strings.Contains(userAgent, "@") || strings.Contains(userAgent, "http://") || strings.Contains(userAgent, "https://")
+}
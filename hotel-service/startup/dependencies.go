@@ -0,0 +1,59 @@
+package startup
+
+import (
+	booking "github.com/ZMS-DevOps/booking-service/proto"
+	"github.com/ZMS-DevOps/hotel-service/application/external"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/api"
+	"github.com/ZMS-DevOps/hotel-service/startup/config"
+	search "github.com/ZMS-DevOps/search-service/proto"
+	"log"
+)
+
+func (server *Server) initDependencyClients() (booking.BookingServiceClient, search.SearchServiceClient) {
+	if server.config.Dependencies != config.LocalDependencies {
+		resilience := server.resilienceConfig()
+		transport, err := external.NewClientCredentials(server.transportConfig())
+		if err != nil {
+			log.Fatalf("Failed to load gRPC transport credentials: %v", err)
+		}
+		return external.NewBookingClient(server.getBookingAddress(), resilience, transport), external.NewSearchClient(server.getSearchAddress(), resilience, transport)
+	}
+	recorder, err := external.NewStubRecorder(server.config.StubFailures)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if server.config.AdminPort != "" {
+		log.Printf("Using local booking and search stubs, inspect calls at /debug/stubs on the admin port")
+		api.NewStubDebugHandler(recorder).Init(server.adminRouter)
+	} else {
+		log.Printf("Using local booking and search stubs")
+	}
+	return external.NewLocalBookingClient(recorder), external.NewLocalSearchClient(recorder)
+}
+
+func (server *Server) resilienceConfig() external.ResilienceConfig {
+	methodTimeouts, err := config.ParseMethodTimeouts(server.config.GrpcMethodTimeouts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return external.ResilienceConfig{
+		Timeout:          server.config.GrpcCallTimeout,
+		MethodTimeouts:   methodTimeouts,
+		MaxRetries:       server.config.GrpcMaxRetries,
+		BaseBackoff:      server.config.GrpcRetryBackoff,
+		MaxBackoff:       server.config.GrpcMaxRetryBackoff,
+		RetryDeadline:    server.config.GrpcRetryDeadline,
+		FailureThreshold: server.config.GrpcBreakerFailures,
+		OpenTimeout:      server.config.GrpcBreakerOpenTimeout,
+	}
+}
+
+func (server *Server) transportConfig() external.TransportConfig {
+	return external.TransportConfig{
+		Mode:       server.config.GrpcTransport,
+		CAFile:     server.config.GrpcTLSCAFile,
+		CertFile:   server.config.GrpcTLSCertFile,
+		KeyFile:    server.config.GrpcTLSKeyFile,
+		ServerName: server.config.GrpcTLSServerName,
+	}
+}
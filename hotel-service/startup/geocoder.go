@@ -0,0 +1,33 @@
+package startup
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/application"
+	"github.com/ZMS-DevOps/hotel-service/application/external"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/persistence"
+	"github.com/ZMS-DevOps/hotel-service/startup/config"
+	"log"
+)
+
+func (server *Server) initGeocoder(client *persistence.ReloadableClient) domain.Geocoder {
+	var geocoder domain.Geocoder
+	switch server.config.Geocoder {
+	case "none":
+		return nil
+	case "nominatim":
+		geocoder = external.NewNominatimGeocoder(server.config.NominatimUrl, server.config.GeocoderUserAgent, server.config.GeocoderTimeout, server.config.GeocoderMinInterval)
+	default:
+		offlineGeocoder, err := external.NewOfflineGeocoder()
+		if err != nil {
+			log.Fatal(err)
+		}
+		geocoder = offlineGeocoder
+	}
+	var cache domain.GeocodeCacheStore
+	if server.config.AccommodationStore == config.MemoryStore {
+		cache = persistence.NewGeocodeCacheMemoryStore(server.config.GeocodeCacheTTL)
+	} else {
+		cache = persistence.NewGeocodeCacheMongoDBStore(client, server.config.GeocodeCacheTTL)
+	}
+	return application.NewCachingGeocoder(geocoder, cache)
+}
@@ -0,0 +1,56 @@
+package startup
+
+import (
+	"context"
+	"github.com/ZMS-DevOps/hotel-service/application/external"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/startup/config"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"log"
+	"time"
+)
+
+func (server *Server) initKafkaProducer() external.EventProducer {
+	if !server.config.KafkaEnabled {
+		log.Printf("Kafka is disabled, host deletion rejections will not be published")
+		return nil
+	}
+	producer, err := kafka.NewProducer(KafkaConfigMap(server.config))
+	if err != nil {
+		log.Printf("Failed to create producer: %s", err)
+		return nil
+	}
+	server.OnSecretRotation("kafka producer", func(updated *config.Config) error {
+		return producer.SetSaslCredentials(updated.KafkaAuthUsername, updated.KafkaAuthPassword)
+	}, "KAFKA_AUTH_USERNAME", "KAFKA_AUTH_PASSWORD")
+	go func() {
+		for event := range producer.Events() {
+			if message, ok := event.(*kafka.Message); ok && message.TopicPartition.Error != nil {
+				log.Printf("Failed to deliver message to %v: %v", message.TopicPartition, message.TopicPartition.Error)
+			}
+		}
+	}()
+	return producer
+}
+
+// startHostDeletionResumer retries sagas left unfinished by a crash or a transient failure; it runs
+// on every replica, and the saga lease keeps them from advancing the same saga twice.
+func (server *Server) startHostDeletionResumer() {
+	go func() {
+		server.resumeHostDeletionSagas()
+		ticker := time.NewTicker(server.config.HostDeletionResumeInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			server.resumeHostDeletionSagas()
+		}
+	}()
+}
+
+func (server *Server) resumeHostDeletionSagas() {
+	ctx, span := server.traceProvider.Tracer(domain.ServiceName).Start(context.Background(), "resume-host-deletion-sagas")
+	defer func() { span.End() }()
+
+	if err := server.hostDeletionSaga.ResumeUnfinished(ctx, span, server.loki); err != nil {
+		log.Printf("Failed to resume host deletion sagas: %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package startup
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/startup/config"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+func KafkaConfigMap(config *config.Config) *kafka.ConfigMap {
+	return &kafka.ConfigMap{
+		"bootstrap.servers": config.BootstrapServers,
+		"security.protocol": config.KafkaSecurityProtocol,
+		"sasl.mechanism":    config.KafkaSaslMechanism,
+		"sasl.username":     config.KafkaAuthUsername,
+		"sasl.password":     config.KafkaAuthPassword,
+	}
+}
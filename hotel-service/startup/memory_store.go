@@ -0,0 +1,24 @@
+package startup
+
+import (
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/persistence"
+	"log"
+)
+
+func (server *Server) initAccommodationMemoryStore() domain.AccommodationStore {
+	log.Printf("Using in-memory accommodation store, data will not survive a restart")
+	store := persistence.NewAccommodationMemoryStore()
+	matcher := domain.NewAmenityMatcher(amenities)
+	for _, seed := range accommodations {
+		accommodation := *seed
+		if accommodation.Address == nil {
+			if address, ok := domain.ParseLegacyLocation(accommodation.Location); ok {
+				accommodation.Address = address
+			}
+		}
+		accommodation.Benefits, accommodation.UnmappedBenefits = matcher.Match(accommodation.Benefits)
+		_ = store.InsertWithId(&accommodation)
+	}
+	return store
+}
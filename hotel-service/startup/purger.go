@@ -0,0 +1,27 @@
+package startup
+
+import (
+	"context"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"log"
+	"time"
+)
+
+func (server *Server) startPurger() {
+	go func() {
+		ticker := time.NewTicker(server.config.PurgeInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			server.purgeDeletedAccommodations()
+		}
+	}()
+}
+
+func (server *Server) purgeDeletedAccommodations() {
+	_, span := server.traceProvider.Tracer(domain.ServiceName).Start(context.Background(), "purge-deleted-accommodations")
+	defer func() { span.End() }()
+
+	if _, err := server.accommodationService.PurgeDeleted(server.config.DeletedRetention, span, server.loki); err != nil {
+		log.Printf("Failed to purge deleted accommodations: %v", err)
+	}
+}
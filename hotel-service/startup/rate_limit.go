@@ -0,0 +1,65 @@
+package startup
+
+import (
+	"context"
+	"github.com/ZMS-DevOps/hotel-service/domain"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/api"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/persistence"
+	"github.com/ZMS-DevOps/hotel-service/startup/config"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"sync/atomic"
+)
+
+type redisCredentials struct {
+	username string
+	password string
+}
+
+func (server *Server) initRateLimits() *api.RateLimits {
+	budgets := map[string]api.RequestBudget{
+		api.ReadBudget: {
+			Limit:        domain.RateLimit{PerMinute: server.config.RateLimitReadsPerMinute, Burst: server.config.RateLimitReadsBurst},
+			MaxBodyBytes: int64(server.config.MaxRequestBodyBytes),
+		},
+		api.WriteBudget: {
+			Limit:        domain.RateLimit{PerMinute: server.config.RateLimitWritesPerMinute, Burst: server.config.RateLimitWritesBurst},
+			MaxBodyBytes: int64(server.config.MaxRequestBodyBytes),
+		},
+		api.UploadBudget: {
+			Limit:        domain.RateLimit{PerMinute: server.config.RateLimitUploadsPerMinute, Burst: server.config.RateLimitUploadsBurst},
+			MaxBodyBytes: int64(server.config.MaxUploadBodyBytes),
+		},
+	}
+	return api.NewRateLimits(server.initRateLimiter(), budgets, server.config.RateLimitTrustedProxyHops)
+}
+
+func (server *Server) initRateLimiter() domain.RateLimiter {
+	if server.config.RateLimiter != config.RedisRateLimiter {
+		return persistence.NewRateLimitMemoryStore()
+	}
+	var credentials atomic.Pointer[redisCredentials]
+	credentials.Store(&redisCredentials{username: server.config.RedisUsername, password: server.config.RedisPassword})
+	client := redis.NewClient(&redis.Options{
+		Addr: server.config.RedisAddress,
+		DB:   server.config.RedisDB,
+		CredentialsProvider: func() (string, string) {
+			current := credentials.Load()
+			return current.username, current.password
+		},
+		DialTimeout:  server.config.RedisTimeout,
+		ReadTimeout:  server.config.RedisTimeout,
+		WriteTimeout: server.config.RedisTimeout,
+	})
+	server.OnSecretRotation("redis client", func(updated *config.Config) error {
+		credentials.Store(&redisCredentials{username: updated.RedisUsername, password: updated.RedisPassword})
+		return nil
+	}, "REDIS_USERNAME", "REDIS_PASSWORD")
+
+	ctx, cancel := context.WithTimeout(context.Background(), server.config.RedisTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("Redis at %s is unreachable, requests are allowed until it recovers: %v", server.config.RedisAddress, err)
+	}
+	return persistence.NewRateLimitRedisStore(client, server.config.RedisTimeout)
+}
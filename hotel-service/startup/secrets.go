@@ -0,0 +1,39 @@
+package startup
+
+import (
+	"context"
+	"github.com/ZMS-DevOps/hotel-service/infrastructure/persistence"
+	"github.com/ZMS-DevOps/hotel-service/startup/config"
+	"log"
+)
+
+func newSecretWatcher(current *config.Config) *config.SecretWatcher {
+	return config.NewSecretWatcher(current)
+}
+
+func (server *Server) OnSecretRotation(name string, listener config.RotationListener, keys ...string) {
+	server.secrets.OnRotation(name, listener, keys...)
+}
+
+func (server *Server) startSecretWatcher() {
+	if len(server.config.Files()) == 0 {
+		return
+	}
+	log.Printf("Watching secret files every %s", server.config.SecretPollInterval)
+	server.secrets.Start(server.config.SecretPollInterval)
+}
+
+func (server *Server) reconnectMongo(reloadable *persistence.ReloadableClient) config.RotationListener {
+	return func(updated *config.Config) error {
+		client, err := persistence.GetClient(mongoOptions(updated))
+		if err != nil {
+			return err
+		}
+		if err := persistence.Ping(client, updated.MongoServerSelectionTimeout); err != nil {
+			_ = client.Disconnect(context.Background())
+			return err
+		}
+		reloadable.Swap(client)
+		return nil
+	}
+}
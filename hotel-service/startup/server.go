@@ -1,6 +1,7 @@
 package startup
 
 import (
+	"expvar"
 	"fmt"
 	booking "github.com/ZMS-DevOps/booking-service/proto"
 	"github.com/ZMS-DevOps/hotel-service/application/external"
@@ -22,37 +23,69 @@ import (
 type Server struct {
 	config               *config.Config
 	router               *mux.Router
+	adminRouter          *mux.Router
 	AccommodationHandler *api.AccommodationHandler
+	accommodationService *application.AccommodationService
+	hostDeletionSaga     *application.HostDeletionSagaService
 	traceProvider        *sdktrace.TracerProvider
 	loki                 promtail.Client
+	secrets              *config.SecretWatcher
 }
 
 func NewServer(config *config.Config, traceProvider *sdktrace.TracerProvider, loki promtail.Client) *Server {
 	server := &Server{
 		config:        config,
 		router:        mux.NewRouter(),
+		adminRouter:   mux.NewRouter(),
 		traceProvider: traceProvider,
 		loki:          loki,
+		secrets:       newSecretWatcher(config),
 	}
 	server.AccommodationHandler = server.setupHandlers()
 	return server
 }
 
 func (server *Server) setupHandlers() *api.AccommodationHandler {
+	server.router.Use(server.initRateLimits().Middleware)
 	mongoClient := server.initMongoClient()
-	bookingClient := external.NewBookingClient(server.getBookingAddress())
-	searchClient := external.NewSearchClient(server.getSearchAddress())
+	bookingClient, searchClient := server.initDependencyClients()
+	amenityStore := server.initAmenityStore(mongoClient)
 	accommodationStore := server.initAccommodationStore(mongoClient)
-	accommodationService := server.initAccommodationService(accommodationStore, bookingClient, searchClient)
-	accommodationHandler := server.initAccommodationHandler(accommodationService)
+	accommodationAuditStore := server.initAccommodationAuditStore(mongoClient)
+	geocoder := server.initGeocoder(mongoClient)
+	accommodationService := server.initAccommodationService(accommodationStore, accommodationAuditStore, bookingClient, searchClient, geocoder)
+	server.accommodationService = accommodationService
+	hostDeletionSagaStore := server.initHostDeletionSagaStore(mongoClient)
+	hostDeletionSaga := server.initHostDeletionSagaService(hostDeletionSagaStore, accommodationService, bookingClient, server.initKafkaProducer())
+	server.hostDeletionSaga = hostDeletionSaga
+	amenityService := server.initAmenityService(amenityStore, accommodationStore)
+	server.initAmenityHandler(amenityService).Init(server.router)
+	idempotency := api.NewIdempotency(server.initIdempotencyStore(mongoClient), server.config.IdempotencyLease)
+	accommodationHandler := server.initAccommodationHandler(accommodationService, amenityService, hostDeletionSaga, idempotency)
 	accommodationHandler.Init(server.router)
+	server.adminRouter.Handle("/debug/vars", expvar.Handler()).Methods("GET")
+	api.NewConfigDebugHandler(server.config.Redacted).Init(server.adminRouter)
 	return accommodationHandler
 }
 
 func (server *Server) Start() {
+	server.startPurger()
+	server.startSecretWatcher()
+	server.startHostDeletionResumer()
+	server.startAdminListener()
 	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%s", server.config.Port), server.router))
 }
 
+func (server *Server) startAdminListener() {
+	if server.config.AdminPort == "" {
+		log.Printf("ADMIN_PORT not set, /debug/vars and /debug/config are disabled")
+		return
+	}
+	go func() {
+		log.Fatal(http.ListenAndServe(fmt.Sprintf(":%s", server.config.AdminPort), server.adminRouter))
+	}()
+}
+
 func (server *Server) getBookingAddress() string {
 	return fmt.Sprintf("%s:%s", server.config.BookingHost, server.config.BookingPort)
 }
@@ -61,27 +94,112 @@ func (server *Server) getSearchAddress() string {
 	return fmt.Sprintf("%s:%s", server.config.SearchHost, server.config.SearchPort)
 }
 
-func (server *Server) initMongoClient() *mongo.Client {
-	client, err := persistence.GetClient(server.config.HotelDBUsername, server.config.HotelDBPassword, server.config.HotelDBHost, server.config.HotelDBPort)
+func (server *Server) initMongoClient() *persistence.ReloadableClient {
+	var client *mongo.Client
+	var err error
+	if server.config.AccommodationStore == config.MongoStore {
+		client, err = persistence.ConnectWithRetry(mongoOptions(server.config), server.config.MongoConnectRetries, server.config.MongoConnectRetryBackoff, server.config.MongoConnectMaxRetryBackoff)
+	} else {
+		client, err = persistence.GetClient(mongoOptions(server.config))
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
-	return client
+	reloadable := persistence.NewReloadableClient(client, server.config.MongoDrainTimeout)
+	server.OnSecretRotation("mongo client", server.reconnectMongo(reloadable), "MONGO_INITDB_ROOT_USERNAME", "MONGO_INITDB_ROOT_PASSWORD", "MONGO_URI")
+	return reloadable
 }
 
-func (server *Server) initAccommodationStore(client *mongo.Client) domain.AccommodationStore {
+func mongoOptions(config *config.Config) persistence.MongoOptions {
+	return persistence.MongoOptions{
+		URI:                    config.MongoURI,
+		Host:                   config.HotelDBHost,
+		Port:                   config.HotelDBPort,
+		Username:               config.HotelDBUsername,
+		Password:               config.HotelDBPassword,
+		AuthSource:             config.MongoAuthSource,
+		ReplicaSet:             config.MongoReplicaSet,
+		TLS:                    config.MongoTLS,
+		TLSCAFile:              config.MongoTLSCAFile,
+		MaxPoolSize:            uint64(config.MongoMaxPoolSize),
+		MinPoolSize:            uint64(config.MongoMinPoolSize),
+		MaxConnIdleTime:        config.MongoMaxConnIdleTime,
+		ConnectTimeout:         config.MongoConnectTimeout,
+		ServerSelectionTimeout: config.MongoServerSelectionTimeout,
+		SocketTimeout:          config.MongoSocketTimeout,
+		WriteConcern:           config.MongoWriteConcern,
+		ReadConcern:            config.MongoReadConcern,
+		ReadPreference:         config.MongoReadPreference,
+	}
+}
+
+func (server *Server) initAccommodationStore(client *persistence.ReloadableClient) domain.AccommodationStore {
+	if server.config.AccommodationStore == config.MemoryStore {
+		return server.initAccommodationMemoryStore()
+	}
 	store := persistence.NewAccommodationMongoDBStore(client)
 	store.DeleteAll()
 	for _, accommodation := range accommodations {
 		_ = store.InsertWithId(accommodation)
 	}
+	if err := persistence.Migrate(client.Client()); err != nil {
+		log.Fatal(err)
+	}
+	return store
+}
+
+func (server *Server) initAmenityStore(client *persistence.ReloadableClient) domain.AmenityStore {
+	var store domain.AmenityStore
+	if server.config.AccommodationStore == config.MemoryStore {
+		store = persistence.NewAmenityMemoryStore()
+	} else {
+		store = persistence.NewAmenityMongoDBStore(client)
+	}
+	for _, amenity := range amenities {
+		if err := store.InsertIfMissing(amenity); err != nil {
+			log.Fatal(err)
+		}
+	}
 	return store
 }
 
-func (server *Server) initAccommodationService(store domain.AccommodationStore, bookingClient booking.BookingServiceClient, searchClient search.SearchServiceClient) *application.AccommodationService {
-	return application.NewAccommodationService(store, bookingClient, searchClient, server.loki)
+func (server *Server) initAmenityService(store domain.AmenityStore, accommodationStore domain.AccommodationStore) *application.AmenityService {
+	return application.NewAmenityService(store, accommodationStore, server.loki)
+}
+
+func (server *Server) initAmenityHandler(service *application.AmenityService) *api.AmenityHandler {
+	return api.NewAmenityHandler(service, server.traceProvider, server.loki)
+}
+
+func (server *Server) initAccommodationAuditStore(client *persistence.ReloadableClient) domain.AccommodationAuditStore {
+	if server.config.AccommodationStore == config.MemoryStore {
+		return persistence.NewAccommodationAuditMemoryStore()
+	}
+	return persistence.NewAccommodationAuditMongoDBStore(client)
+}
+
+func (server *Server) initAccommodationService(store domain.AccommodationStore, auditStore domain.AccommodationAuditStore, bookingClient booking.BookingServiceClient, searchClient search.SearchServiceClient, geocoder domain.Geocoder) *application.AccommodationService {
+	return application.NewAccommodationService(store, auditStore, bookingClient, searchClient, geocoder, server.loki)
+}
+
+func (server *Server) initHostDeletionSagaStore(client *persistence.ReloadableClient) domain.HostDeletionSagaStore {
+	if server.config.AccommodationStore == config.MemoryStore {
+		return persistence.NewHostDeletionSagaMemoryStore()
+	}
+	return persistence.NewHostDeletionSagaMongoDBStore(client)
+}
+
+func (server *Server) initHostDeletionSagaService(store domain.HostDeletionSagaStore, accommodationService *application.AccommodationService, bookingClient booking.BookingServiceClient, producer external.EventProducer) *application.HostDeletionSagaService {
+	return application.NewHostDeletionSagaService(store, accommodationService, bookingClient, producer, server.config.HostDeletionSagaLease, server.loki)
+}
+
+func (server *Server) initIdempotencyStore(client *persistence.ReloadableClient) domain.IdempotencyStore {
+	if server.config.AccommodationStore == config.MemoryStore {
+		return persistence.NewIdempotencyMemoryStore(server.config.IdempotencyTTL)
+	}
+	return persistence.NewIdempotencyMongoDBStore(client, server.config.IdempotencyTTL)
 }
 
-func (server *Server) initAccommodationHandler(service *application.AccommodationService) *api.AccommodationHandler {
-	return api.NewAccommodationHandler(service, server.traceProvider, server.loki)
+func (server *Server) initAccommodationHandler(service *application.AccommodationService, amenityService *application.AmenityService, hostDeletionSaga *application.HostDeletionSagaService, idempotency *api.Idempotency) *api.AccommodationHandler {
+	return api.NewAccommodationHandler(service, amenityService, hostDeletionSaga, server.config.DeletedRetention, server.traceProvider, server.loki, idempotency)
 }